@@ -30,11 +30,23 @@ func loggingMiddleware(next http.Handler) http.Handler {
 }
 
 func main() {
-	// Initialize storage
-	store := storage.NewMemoryStorage()
+	// Initialize storage (STORAGE_DRIVER=memory|sqlite, SQLITE_PATH)
+	store, err := storage.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
+	// AuthMiddleware verifies initData signatures with the bot token
+	if os.Getenv("TELEGRAM_BOT_TOKEN") == "" && os.Getenv("DEV_FAKE_USER_ID") == "" {
+		log.Println("WARNING: TELEGRAM_BOT_TOKEN not set - API requests cannot be authenticated")
+	}
 
 	// Initialize handlers
-	logsHandler := handlers.NewLogsHandler(store)
+	logsHandler := handlers.NewLogsHandler(store, store)
+	goalHandler := handlers.NewGoalHandler(store, store)
+	statsHandler := handlers.NewStatsHandler(store, store)
+	exportHandler := handlers.NewExportHandler(store, store)
+	profileHandler := handlers.NewProfileHandler(store)
 
 	// Create HTTP router
 	mux := http.NewServeMux()
@@ -69,6 +81,48 @@ func main() {
 		}
 	})))
 
+	// Daily goal routes (GET returns today's progress, PUT updates goal/timezone)
+	mux.Handle("/api/goal", middleware.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			goalHandler.GetGoal(w, r)
+		case http.MethodPut:
+			goalHandler.UpdateGoal(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
+
+	// Profile routes (timezone, language, locale, units and other preferences)
+	mux.Handle("/api/profile", middleware.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			profileHandler.GetProfile(w, r)
+		case http.MethodPut:
+			profileHandler.UpdateProfile(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
+
+	// Aggregated statistics (bucketed totals in the user's timezone)
+	mux.Handle("/api/stats", middleware.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		statsHandler.GetStats(w, r)
+	})))
+
+	// Log history download (CSV or newline-delimited JSON)
+	mux.Handle("/api/export", middleware.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		exportHandler.Export(w, r)
+	})))
+
 	// Configure CORS for development
 	allowedOrigins := []string{"http://localhost:5173"}
 
@@ -80,7 +134,7 @@ func main() {
 
 	corsHandler := cors.New(cors.Options{
 		AllowedOrigins:   allowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Content-Type", "X-Telegram-Init-Data"},
 		AllowCredentials: true,
 		Debug:            false, // Disable verbose CORS logging (set true for debugging)
@@ -34,7 +34,7 @@ func NewBotTester(judge *GeminiJudge) (*BotTester, error) {
 	store := storage.NewMemoryStorage()
 
 	// Create real handler with fake sender and fake estimator
-	handler := handlers.NewEstimateHandler(fakeSender, sessionManager, fakeEstimator, store)
+	handler := handlers.NewEstimateHandler(fakeSender, sessionManager, fakeEstimator, store, store, nil)
 
 	// Create test user
 	testUser := &tele.User{
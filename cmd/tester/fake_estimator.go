@@ -14,13 +14,26 @@ func NewFakeEstimator() *FakeEstimator {
 	return &FakeEstimator{}
 }
 
-// EstimateFromImage returns a deterministic fake estimate
-func (f *FakeEstimator) EstimateFromImage(ctx context.Context, imageBytes []byte, mimeType string) (*models.EstimateResult, error) {
+// EstimateFromImages returns a deterministic fake estimate
+func (f *FakeEstimator) EstimateFromImages(ctx context.Context, images []models.ImageInput, hints string) (*models.EstimateResult, error) {
 	// Return a deterministic structured estimate for stable testing
 	return &models.EstimateResult{
-		FoodItems:  []string{"Rice", "Chicken"},
+		FoodItems: []models.FoodItem{
+			{Name: "Rice", PortionGrams: 150, Calories: intPtr(200)},
+			{Name: "Chicken", PortionGrams: 180, Calories: intPtr(300)},
+		},
 		Calories:   500,
 		Confidence: "high",
 		Reasoning:  "Test estimation for integration testing",
 	}, nil
 }
+
+// EstimateFromText returns the same deterministic fake estimate for descriptions
+func (f *FakeEstimator) EstimateFromText(ctx context.Context, description string) (*models.EstimateResult, error) {
+	return f.EstimateFromImages(ctx, nil, "")
+}
+
+// intPtr returns a pointer to an int value
+func intPtr(i int) *int {
+	return &i
+}
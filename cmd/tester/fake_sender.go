@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strconv"
 
 	tele "gopkg.in/telebot.v3"
 )
@@ -80,6 +81,69 @@ func (f *FakeSender) Send(to tele.Recipient, what interface{}, opts ...interface
 	}, nil
 }
 
+// Edit records message edits and updates the recorded message in place
+func (f *FakeSender) Edit(msg tele.Editable, what interface{}, opts ...interface{}) (*tele.Message, error) {
+	msgID, chatID := msg.MessageSig()
+
+	text := ""
+	switch v := what.(type) {
+	case string:
+		text = v
+	default:
+		text = fmt.Sprintf("%v", what)
+	}
+
+	var replyMarkup *tele.ReplyMarkup
+	for _, opt := range opts {
+		if rm, ok := opt.(*tele.ReplyMarkup); ok {
+			replyMarkup = rm
+			break
+		}
+	}
+
+	id, _ := strconv.Atoi(msgID)
+	edited := SentMessage{
+		ChatID:      chatID,
+		Text:        text,
+		MessageID:   id,
+		ReplyMarkup: replyMarkup,
+	}
+	f.EditedMessages = append(f.EditedMessages, edited)
+	if id >= 1 && id <= len(f.SentMessages) {
+		f.SentMessages[id-1] = edited
+	}
+
+	return &tele.Message{
+		ID:   id,
+		Text: text,
+		Chat: &tele.Chat{ID: chatID},
+	}, nil
+}
+
+// EditReplyMarkup records inline keyboard edits
+func (f *FakeSender) EditReplyMarkup(msg tele.Editable, markup *tele.ReplyMarkup) (*tele.Message, error) {
+	msgID, chatID := msg.MessageSig()
+	id, _ := strconv.Atoi(msgID)
+
+	text := ""
+	if id >= 1 && id <= len(f.SentMessages) {
+		f.SentMessages[id-1].ReplyMarkup = markup
+		text = f.SentMessages[id-1].Text
+	}
+	f.EditedMessages = append(f.EditedMessages, SentMessage{
+		ChatID:      chatID,
+		Text:        text,
+		MessageID:   id,
+		ReplyMarkup: markup,
+	})
+
+	return &tele.Message{
+		ID:   id,
+		Text: text,
+		Chat: &tele.Chat{ID: chatID},
+	}, nil
+}
+
 // Delete records message deletions
 func (f *FakeSender) Delete(msg tele.Editable) error {
 	// Extract message ID
@@ -1,20 +1,34 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
+	"text/template"
 	"time"
 
+	"github.com/freezind/telegram-calories-bot/prompts"
 	"google.golang.org/genai"
 )
 
+// judgePromptVersion selects prompts/judge/<version>.tmpl
+const judgePromptVersion = "v1"
+
+// judgePromptData is the input to the judge prompt template
+type judgePromptData struct {
+	Scenario         string
+	ExpectedBehavior string
+	Evidence         string // Indented JSON of captured evidence
+}
+
 // GeminiJudge evaluates test scenarios using Gemini LLM
 type GeminiJudge struct {
-	apiKey  string
-	model   string
-	prompts []string // Archive all prompts used during testing
+	apiKey   string
+	model    string
+	template *template.Template
+	prompts  []string // Archive all prompts used during testing
 }
 
 // JudgeVerdict represents the structured output from LLM judge
@@ -24,12 +38,20 @@ type JudgeVerdict struct {
 }
 
 // NewGeminiJudge creates a new Gemini judge instance
-func NewGeminiJudge(apiKey string) *GeminiJudge {
-	return &GeminiJudge{
-		apiKey:  apiKey,
-		model:   "gemini-2.5-flash", // Fast model for deterministic evaluation
-		prompts: []string{},
+// The prompt is loaded from the embedded prompts/judge templates
+func NewGeminiJudge(apiKey string) (*GeminiJudge, error) {
+	tmpl, err := template.New(judgePromptVersion).Option("missingkey=error").
+		ParseFS(prompts.FS, "judge/"+judgePromptVersion+".tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load judge prompt %s: %w", judgePromptVersion, err)
 	}
+
+	return &GeminiJudge{
+		apiKey:   apiKey,
+		model:    "gemini-2.5-flash", // Fast model for deterministic evaluation
+		template: tmpl,
+		prompts:  []string{},
+	}, nil
 }
 
 // Evaluate evaluates a test scenario using Gemini LLM judge
@@ -40,28 +62,16 @@ func (gj *GeminiJudge) Evaluate(ctx context.Context, scenarioName, expectedBehav
 		return JudgeVerdict{}, fmt.Errorf("failed to marshal evidence: %w", err)
 	}
 
-	// Format judge prompt
-	prompt := fmt.Sprintf(`You are a test evaluator for a Telegram bot testing system.
-
-**Scenario:** %s
-**Expected Behavior:** %s
-
-**Captured Evidence:**
-%s
-
-Evaluate whether the captured evidence demonstrates the expected behavior.
-
-Output ONLY valid JSON:
-{
-  "verdict": "PASS" or "FAIL",
-  "rationale": "brief explanation (1-2 sentences)"
-}
-
-Rules:
-- PASS if evidence clearly matches expected behavior
-- FAIL if evidence contradicts expected behavior or is missing critical elements
-- Be strict: ambiguous evidence = FAIL
-- Do not output anything other than the JSON object`, scenarioName, expectedBehavior, string(evidenceJSON))
+	// Render judge prompt
+	var rendered bytes.Buffer
+	if err := gj.template.ExecuteTemplate(&rendered, judgePromptVersion+".tmpl", judgePromptData{
+		Scenario:         scenarioName,
+		ExpectedBehavior: expectedBehavior,
+		Evidence:         string(evidenceJSON),
+	}); err != nil {
+		return JudgeVerdict{}, fmt.Errorf("failed to render judge prompt: %w", err)
+	}
+	prompt := strings.TrimSpace(rendered.String())
 
 	// Archive prompt
 	gj.prompts = append(gj.prompts, prompt)
@@ -46,7 +46,10 @@ func main() {
 	defer cancel()
 
 	// Initialize components
-	judge := NewGeminiJudge(config.GeminiAPIKey)
+	judge, err := NewGeminiJudge(config.GeminiAPIKey)
+	if err != nil {
+		log.Fatalf("Failed to create judge: %v", err)
+	}
 	botTester, err := NewBotTester(judge)
 	if err != nil {
 		log.Fatalf("Failed to create bot tester: %v", err)
@@ -37,8 +37,12 @@ func main() {
 	// ====================================
 	// 1. Initialize shared storage
 	// ====================================
-	store := storage.NewMemoryStorage()
-	log.Println("[STORAGE] ✓ Shared MemoryStorage initialized")
+	// Backend is selected via STORAGE_DRIVER (memory|sqlite) and SQLITE_PATH
+	store, err := storage.NewFromEnv()
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize storage: %v", err)
+	}
+	log.Println("[STORAGE] ✓ Shared storage initialized")
 
 	// ====================================
 	// 2. Initialize Telegram Bot (Spec 002)
@@ -48,11 +52,6 @@ func main() {
 		log.Fatal("❌ TELEGRAM_BOT_TOKEN environment variable is required")
 	}
 
-	// GEMINI_API_KEY is read by NewGeminiClient() from environment
-	if os.Getenv("GEMINI_API_KEY") == "" {
-		log.Fatal("❌ GEMINI_API_KEY environment variable is required")
-	}
-
 	// Create bot instance
 	pref := tele.Settings{
 		Token:  botToken,
@@ -69,16 +68,54 @@ func main() {
 
 	// Initialize bot dependencies
 	sessionManager := services.NewSessionManager()
-	geminiClient, err := services.NewGeminiClient()
+	// Versioned prompt templates (embedded, or PROMPTS_DIR) with optional A/B experiment
+	prompts, err := services.NewPromptsFromEnv()
+	if err != nil {
+		log.Fatalf("❌ Failed to load prompts: %v", err)
+	}
+
+	// Providers are selected via ESTIMATOR_PROVIDERS (default: gemini, which needs GEMINI_API_KEY)
+	chain, err := services.NewEstimatorFromEnv(prompts)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize estimator: %v", err)
+	}
+
+	// Repeated photos reuse the cached estimate (ESTIMATE_CACHE_TTL, ESTIMATE_CACHE_SIZE, ESTIMATE_CACHE_MODE)
+	estimator, err := services.NewCachingEstimatorFromEnv(chain, prompts)
 	if err != nil {
-		log.Fatalf("❌ Failed to initialize Gemini client: %v", err)
+		log.Fatalf("❌ Failed to configure estimate cache: %v", err)
 	}
-	estimator := services.NewGeminiEstimator(geminiClient)
-	estimateHandler := bothandlers.NewEstimateHandler(sender, sessionManager, estimator, store)
+
+	// Uploads are decoded, stripped of EXIF, oriented and downsized (IMAGE_MAX_EDGE, IMAGE_MAX_BYTES)
+	images, err := services.NewImagePreprocessorFromEnv()
+	if err != nil {
+		log.Fatalf("❌ Failed to configure image preprocessing: %v", err)
+	}
+	estimateHandler := bothandlers.NewEstimateHandler(sender, sessionManager, estimator, store, store, images)
+
+	// Estimates are saved when the user taps Save, or after ESTIMATE_AUTOSAVE_AFTER if set
+	autoSave, err := bothandlers.AutoSaveDelayFromEnv()
+	if err != nil {
+		log.Fatalf("❌ Failed to configure auto-save: %v", err)
+	}
+	estimateHandler.SetAutoSave(autoSave)
+
+	// Fill new users' language and locale from their Telegram client
+	tgBot.Use(estimateHandler.DetectLanguage)
 
 	// Register bot command handlers
 	tgBot.Handle("/start", estimateHandler.HandleStart)
 	tgBot.Handle("/estimate", estimateHandler.HandleEstimate)
+	tgBot.Handle("/goal", estimateHandler.HandleGoal)
+	tgBot.Handle("/oneshot", estimateHandler.HandleOneShot)
+	tgBot.Handle("/meals", estimateHandler.HandleMeals)
+	tgBot.Handle("/settings", estimateHandler.HandleSettings)
+	tgBot.Handle("/log", estimateHandler.HandleLog)
+	tgBot.Handle("/today", estimateHandler.HandleToday)
+	tgBot.Handle("/week", estimateHandler.HandleWeek)
+	tgBot.Handle("/month", estimateHandler.HandleMonth)
+	tgBot.Handle("/export", estimateHandler.HandleExport)
+	tgBot.Handle(tele.OnText, estimateHandler.HandleText)
 	tgBot.Handle(tele.OnPhoto, estimateHandler.HandlePhoto)
 	tgBot.Handle(tele.OnDocument, estimateHandler.HandleDocument)
 
@@ -89,14 +126,30 @@ func main() {
 
 		log.Printf("[BOT CALLBACK] User %d clicked button: '%s'", userID, callbackData)
 
-		switch callbackData {
+		// Result buttons carry the pending estimate ID after the action, e.g. "save|12"
+		action, _ := bothandlers.ParseCallbackData(callbackData)
+		switch action {
 		case "re_estimate":
 			return estimateHandler.HandleReEstimate(c)
 		case "cancel":
 			return estimateHandler.HandleCancel(c)
+		case "done":
+			return estimateHandler.HandleDone(c)
+		case "save":
+			return estimateHandler.HandleSave(c)
+		case "adjust":
+			return estimateHandler.HandleAdjust(c)
+		case "discard":
+			return estimateHandler.HandleDiscard(c)
+		case "scale_0.5", "scale_1.5", "scale_2":
+			return estimateHandler.HandleScale(c)
+		case "manual_kcal":
+			return estimateHandler.HandleManualCalories(c)
+		case "meal_breakfast", "meal_lunch", "meal_dinner", "meal_snack":
+			return estimateHandler.HandleMealType(c)
 		default:
 			log.Printf("[BOT CALLBACK] Unknown callback: '%s'", callbackData)
-			return c.Respond(&tele.CallbackResponse{Text: "Unknown action"})
+			return estimateHandler.HandleUnknownCallback(c)
 		}
 	})
 
@@ -107,7 +160,11 @@ func main() {
 	// ====================================
 	// 3. Initialize HTTP API Server (Spec 003)
 	// ====================================
-	logsHandler := apihandlers.NewLogsHandler(store)
+	logsHandler := apihandlers.NewLogsHandler(store, store)
+	goalHandler := apihandlers.NewGoalHandler(store, store)
+	statsHandler := apihandlers.NewStatsHandler(store, store)
+	exportHandler := apihandlers.NewExportHandler(store, store)
+	profileHandler := apihandlers.NewProfileHandler(store)
 
 	mux := http.NewServeMux()
 
@@ -140,6 +197,48 @@ func main() {
 		}
 	})))
 
+	// Daily goal routes (GET returns today's progress, PUT updates goal/timezone)
+	mux.Handle("/api/goal", middleware.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			goalHandler.GetGoal(w, r)
+		case http.MethodPut:
+			goalHandler.UpdateGoal(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
+
+	// Profile routes (timezone, language, locale, units and other preferences)
+	mux.Handle("/api/profile", middleware.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			profileHandler.GetProfile(w, r)
+		case http.MethodPut:
+			profileHandler.UpdateProfile(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
+
+	// Aggregated statistics (bucketed totals in the user's timezone)
+	mux.Handle("/api/stats", middleware.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		statsHandler.GetStats(w, r)
+	})))
+
+	// Log history download (CSV or newline-delimited JSON)
+	mux.Handle("/api/export", middleware.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		exportHandler.Export(w, r)
+	})))
+
 	// Configure CORS
 	allowedOrigins := []string{
 		"http://localhost:5173",
@@ -156,7 +255,7 @@ func main() {
 
 	corsHandler := cors.New(cors.Options{
 		AllowedOrigins:   allowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Content-Type", "X-Telegram-Init-Data"},
 		AllowCredentials: true,
 		Debug:            false,
@@ -6,9 +6,11 @@ toolchain go1.24.11
 
 require (
 	github.com/google/uuid v1.6.0
+	github.com/mattn/go-sqlite3 v1.14.33
 	github.com/playwright-community/playwright-go v0.5200.1
 	github.com/rs/cors v1.11.1
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/image v0.25.0
 	google.golang.org/genai v1.39.0
 	gopkg.in/telebot.v3 v3.3.8
 )
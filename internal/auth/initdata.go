@@ -1,9 +1,30 @@
 package auth
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMissingHash is returned when initData has no hash field to verify
+	ErrMissingHash = errors.New("hash not found in initData")
+
+	// ErrInvalidSignature is returned when the initData hash does not match the bot token signature
+	ErrInvalidSignature = errors.New("initData signature is invalid")
+
+	// ErrMissingAuthDate is returned when initData has no usable auth_date field
+	ErrMissingAuthDate = errors.New("auth_date not found in initData")
+
+	// ErrExpired is returned when auth_date is older than the allowed max age
+	ErrExpired = errors.New("initData has expired")
 )
 
 // TelegramUser represents a user extracted from Telegram initData
@@ -12,10 +33,76 @@ type TelegramUser struct {
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
 	Username  string `json:"username"`
+
+	// LanguageCode is the IETF tag of the user's Telegram client language (e.g. "en", "pt-br"), may be empty
+	LanguageCode string `json:"language_code"`
+}
+
+// ValidateInitData verifies the initData signature against the bot token and
+// rejects data whose auth_date is older than maxAge (maxAge <= 0 disables the age check).
+// See https://core.telegram.org/bots/webapps#validating-data-received-via-the-mini-app
+func ValidateInitData(initData, botToken string, maxAge time.Duration) (*TelegramUser, error) {
+	if initData == "" {
+		return nil, errors.New("initData is empty")
+	}
+
+	values, err := url.ParseQuery(initData)
+	if err != nil {
+		return nil, errors.New("invalid initData format")
+	}
+
+	receivedHash := values.Get("hash")
+	if receivedHash == "" {
+		return nil, ErrMissingHash
+	}
+
+	expectedHash := SignInitData(values, botToken)
+	if !hmac.Equal([]byte(receivedHash), []byte(expectedHash)) {
+		return nil, ErrInvalidSignature
+	}
+
+	authDate, err := strconv.ParseInt(values.Get("auth_date"), 10, 64)
+	if err != nil || authDate <= 0 {
+		return nil, ErrMissingAuthDate
+	}
+	if maxAge > 0 && time.Since(time.Unix(authDate, 0)) > maxAge {
+		return nil, ErrExpired
+	}
+
+	return ParseInitData(initData)
+}
+
+// SignInitData computes the hex-encoded hash Telegram attaches to initData:
+// HMAC-SHA256(data_check_string, HMAC-SHA256(bot_token, "WebAppData"))
+// The "hash" field itself is excluded from the data check string
+func SignInitData(values url.Values, botToken string) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if key == "hash" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+values.Get(key))
+	}
+	dataCheckString := strings.Join(pairs, "\n")
+
+	secretMAC := hmac.New(sha256.New, []byte("WebAppData"))
+	secretMAC.Write([]byte(botToken))
+	secretKey := secretMAC.Sum(nil)
+
+	mac := hmac.New(sha256.New, secretKey)
+	mac.Write([]byte(dataCheckString))
+	return hex.EncodeToString(mac.Sum(nil))
 }
 
 // ParseInitData extracts user information from Telegram WebApp initData
 // The initData is a URL-encoded query string containing user info
+// It does NOT verify the signature; use ValidateInitData for untrusted input
 func ParseInitData(initData string) (*TelegramUser, error) {
 	if initData == "" {
 		return nil, errors.New("initData is empty")
@@ -44,8 +131,5 @@ func ParseInitData(initData string) (*TelegramUser, error) {
 		return nil, errors.New("user ID is zero or missing in parsed JSON")
 	}
 
-	// For demo MVP, we skip hash validation
-	// In production, you would validate the hash to ensure data authenticity
-
 	return &user, nil
 }
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/freezind/telegram-calories-bot/internal/middleware"
+	"github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+)
+
+// ExportHandler handles log history export HTTP requests
+type ExportHandler struct {
+	logs     storage.LogStorage
+	profiles storage.ProfileStorage
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(logs storage.LogStorage, profiles storage.ProfileStorage) *ExportHandler {
+	return &ExportHandler{logs: logs, profiles: profiles}
+}
+
+// Export handles GET /api/export?format=csv|ndjson&from=&to=
+// from/to accept a date (YYYY-MM-DD, inclusive, in the user's timezone) or an RFC3339 instant
+// Defaults: format = csv, the whole history; logs are written oldest first as a file download
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized: user ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	profile, err := h.profiles.GetProfile(userID)
+	if err != nil {
+		http.Error(w, "Failed to fetch profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	loc := profile.Location()
+
+	query := r.URL.Query()
+	format, err := models.ParseExportFormat(query.Get("format"))
+	if err != nil {
+		http.Error(w, "Invalid format: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var from, to *time.Time
+	for _, param := range []struct {
+		name     string
+		target   **time.Time
+		endOfDay bool
+	}{{"from", &from, false}, {"to", &to, true}} {
+		raw := query.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		t, err := models.ParseRangeBound(raw, loc, param.endOfDay)
+		if err != nil {
+			http.Error(w, "Invalid "+param.name+": "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		*param.target = &t
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		http.Error(w, "Invalid range: from must be before to", http.StatusBadRequest)
+		return
+	}
+
+	logs, err := h.logs.ListLogs(userID)
+	if err != nil {
+		http.Error(w, "Failed to fetch logs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	logs = models.ExportLogs(logs, from, to)
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="`+format.FileName(time.Now().In(loc))+`"`)
+	w.WriteHeader(http.StatusOK)
+	if err := models.WriteExport(w, format, logs, loc); err != nil {
+		// Headers are already sent; the client sees a truncated file
+		log.Printf("[API] Export: Failed to write %s export for user %d: %v", format, userID, err)
+		return
+	}
+	log.Printf("[API] Export: Wrote %d log(s) as %s for user %d", len(logs), format, userID)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/freezind/telegram-calories-bot/internal/middleware"
+	"github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+)
+
+// GoalHandler handles daily goal HTTP requests
+type GoalHandler struct {
+	logs     storage.LogStorage
+	profiles storage.ProfileStorage
+}
+
+// NewGoalHandler creates a new goal handler
+func NewGoalHandler(logs storage.LogStorage, profiles storage.ProfileStorage) *GoalHandler {
+	return &GoalHandler{logs: logs, profiles: profiles}
+}
+
+// GoalResponse is the JSON body returned by /api/goal
+type GoalResponse struct {
+	DailyGoal     int    `json:"dailyGoal"`
+	Timezone      string `json:"timezone"`
+	ConsumedToday int    `json:"consumedToday"`
+	Remaining     int    `json:"remaining"`
+}
+
+// GoalUpdate is the JSON body accepted by PUT /api/goal
+type GoalUpdate struct {
+	DailyGoal *int    `json:"dailyGoal,omitempty"`
+	Timezone  *string `json:"timezone,omitempty"`
+}
+
+// GetGoal handles GET /api/goal
+func (h *GoalHandler) GetGoal(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized: user ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	profile, err := h.profiles.GetProfile(userID)
+	if err != nil {
+		http.Error(w, "Failed to fetch goal: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.writeGoal(w, profile)
+}
+
+// UpdateGoal handles PUT /api/goal
+func (h *GoalHandler) UpdateGoal(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized: user ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var update GoalUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.profiles.GetProfile(userID)
+	if err != nil {
+		http.Error(w, "Failed to fetch goal: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if update.DailyGoal != nil {
+		profile.DailyGoal = *update.DailyGoal
+	}
+	if update.Timezone != nil {
+		profile.Timezone = *update.Timezone
+	}
+
+	if err := h.profiles.SaveProfile(profile); err != nil {
+		http.Error(w, "Failed to update goal: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("[API] UpdateGoal: User %d goal=%d timezone=%q", userID, profile.DailyGoal, profile.Timezone)
+	h.writeGoal(w, profile)
+}
+
+// writeGoal computes today's consumption in the user's timezone and encodes the response
+func (h *GoalHandler) writeGoal(w http.ResponseWriter, profile *models.UserProfile) {
+	logs, err := h.logs.ListLogs(profile.UserID)
+	if err != nil {
+		http.Error(w, "Failed to fetch logs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	consumed := models.CaloriesOnDay(logs, time.Now(), profile.Location())
+	resp := GoalResponse{
+		DailyGoal:     profile.DailyGoal,
+		Timezone:      profile.Timezone,
+		ConsumedToday: consumed,
+		Remaining:     profile.DailyGoal - consumed,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -2,8 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/freezind/telegram-calories-bot/internal/middleware"
 	"github.com/freezind/telegram-calories-bot/internal/models"
@@ -12,15 +18,21 @@ import (
 
 // LogsHandler handles log-related HTTP requests
 type LogsHandler struct {
-	storage storage.LogStorage
+	storage  storage.LogStorage
+	profiles storage.ProfileStorage
 }
 
 // NewLogsHandler creates a new logs handler
-func NewLogsHandler(storage storage.LogStorage) *LogsHandler {
-	return &LogsHandler{storage: storage}
+func NewLogsHandler(storage storage.LogStorage, profiles storage.ProfileStorage) *LogsHandler {
+	return &LogsHandler{storage: storage, profiles: profiles}
 }
 
 // ListLogs handles GET /api/logs
+// Query params: limit, cursor, order (desc|asc, default desc), from/to (a date in the user's timezone,
+// to inclusive, or an RFC3339 instant), confidence (comma-separated), q (food item search),
+// units (metric|imperial) and energy (kcal|kj) to override the user's display units
+// Responds with {"logs": [...], "nextCursor": "...", "units": {...}}; pass nextCursor back as cursor for the next page
+// Each log keeps its stored kcal and grams and adds a "display" block in the requested units
 func (h *LogsHandler) ListLogs(w http.ResponseWriter, r *http.Request) {
 	// Extract userID from context (added by AuthMiddleware)
 	userID, ok := middleware.GetUserID(r.Context())
@@ -29,31 +41,133 @@ func (h *LogsHandler) ListLogs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Fetch logs for user
-	logs, err := h.storage.ListLogs(userID)
+	profile, err := h.profiles.GetProfile(userID)
+	if err != nil {
+		http.Error(w, "Failed to fetch profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	query, err := parseLogQuery(r.URL.Query(), profile.Location())
+	if err != nil {
+		http.Error(w, "Invalid query: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := query.Validate(); err != nil {
+		http.Error(w, "Invalid query: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	units, ok := profileUnits(w, r, profile)
+	if !ok {
+		return
+	}
+
+	// Fetch one page of logs for user
+	page, err := h.storage.QueryLogs(userID, query)
 	if err != nil {
 		http.Error(w, "Failed to fetch logs: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	// Log the result for debugging
-	if len(logs) == 0 {
+	if len(page.Logs) == 0 {
 		// This is the first-time user case - return empty array (not an error!)
-		log.Printf("[API] ListLogs: User %d has no logs yet (returning empty array)", userID)
+		log.Printf("[API] ListLogs: User %d has no matching logs (returning empty page)", userID)
 	} else {
-		log.Printf("[API] ListLogs: Found %d log(s) for user %d", len(logs), userID)
+		log.Printf("[API] ListLogs: Found %d log(s) for user %d (more: %t)", len(page.Logs), userID, page.NextCursor != "")
 	}
 
-	// Return logs as JSON (empty array for new users, which is correct behavior)
+	// Return page as JSON (empty logs array for new users, which is correct behavior)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(logs); err != nil {
+	if err := json.NewEncoder(w).Encode(units.DisplayPage(page)); err != nil {
 		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
+// parseLogQuery builds a LogQuery from URL query parameters; from/to dates are read in loc
+func parseLogQuery(values url.Values, loc *time.Location) (*models.LogQuery, error) {
+	query := &models.LogQuery{
+		Cursor: values.Get("cursor"),
+		Search: strings.TrimSpace(values.Get("q")),
+	}
+
+	order, err := models.ParseLogOrder(values.Get("order"))
+	if err != nil {
+		return nil, err
+	}
+	query.Order = order
+
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, errors.New("limit must be an integer")
+		}
+		query.Limit = limit
+	}
+
+	for _, param := range []struct {
+		name     string
+		target   **time.Time
+		endOfDay bool
+	}{{"from", &query.From, false}, {"to", &query.To, true}} {
+		raw := values.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		t, err := models.ParseRangeBound(raw, loc, param.endOfDay)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD) or RFC3339 timestamp", param.name)
+		}
+		*param.target = &t
+	}
+
+	for _, raw := range values["confidence"] {
+		for _, level := range strings.Split(raw, ",") {
+			if level = strings.TrimSpace(level); level != "" {
+				query.Confidence = append(query.Confidence, models.ConfidenceLevel(level))
+			}
+		}
+	}
+
+	return query, nil
+}
+
+// displayUnits returns the user's display units overridden by the units and energy query params
+// On failure it writes the error response and returns false
+func (h *LogsHandler) displayUnits(w http.ResponseWriter, r *http.Request, userID int64) (models.DisplayUnits, bool) {
+	profile, err := h.profiles.GetProfile(userID)
+	if err != nil {
+		http.Error(w, "Failed to fetch profile: "+err.Error(), http.StatusInternalServerError)
+		return models.DisplayUnits{}, false
+	}
+	return profileUnits(w, r, profile)
+}
+
+// profileUnits returns the profile's display units overridden by the units and energy query params
+// On failure it writes the error response and returns false
+func profileUnits(w http.ResponseWriter, r *http.Request, profile *models.UserProfile) (models.DisplayUnits, bool) {
+	units := profile.DisplayUnits()
+
+	var err error
+	values := r.URL.Query()
+	if raw := values.Get("units"); raw != "" {
+		if units.System, err = models.ParseUnitSystem(raw); err != nil {
+			http.Error(w, "Invalid units: "+err.Error(), http.StatusBadRequest)
+			return models.DisplayUnits{}, false
+		}
+	}
+	if raw := values.Get("energy"); raw != "" {
+		if units.Energy, err = models.ParseEnergyUnit(raw); err != nil {
+			http.Error(w, "Invalid energy: "+err.Error(), http.StatusBadRequest)
+			return models.DisplayUnits{}, false
+		}
+	}
+	return units, true
+}
+
 // CreateLog handles POST /api/logs
+// Accepts the units and energy query params of ListLogs for the response's display block
 func (h *LogsHandler) CreateLog(w http.ResponseWriter, r *http.Request) {
 	// Extract userID from context
 	userID, ok := middleware.GetUserID(r.Context())
@@ -62,6 +176,11 @@ func (h *LogsHandler) CreateLog(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	units, ok := h.displayUnits(w, r, userID)
+	if !ok {
+		return
+	}
+
 	// Parse request body
 	var log models.Log
 	if err := json.NewDecoder(r.Body).Decode(&log); err != nil {
@@ -78,13 +197,14 @@ func (h *LogsHandler) CreateLog(w http.ResponseWriter, r *http.Request) {
 	// Return created log as JSON
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	if err := json.NewEncoder(w).Encode(log); err != nil {
+	if err := json.NewEncoder(w).Encode(models.DisplayLog{Log: log, Display: units.Display(&log)}); err != nil {
 		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
 // UpdateLog handles PATCH /api/logs/:id
+// Accepts the units and energy query params of ListLogs for the response's display block
 func (h *LogsHandler) UpdateLog(w http.ResponseWriter, r *http.Request) {
 	// Extract userID from context
 	userID, ok := middleware.GetUserID(r.Context())
@@ -101,6 +221,10 @@ func (h *LogsHandler) UpdateLog(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Log ID is required", http.StatusBadRequest)
 		return
 	}
+	units, ok := h.displayUnits(w, r, userID)
+	if !ok {
+		return
+	}
 
 	// Parse request body
 	var update models.LogUpdate
@@ -146,7 +270,7 @@ func (h *LogsHandler) UpdateLog(w http.ResponseWriter, r *http.Request) {
 
 	// Return updated log as JSON
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(updatedLog); err != nil {
+	if err := json.NewEncoder(w).Encode(models.DisplayLog{Log: *updatedLog, Display: units.Display(updatedLog)}); err != nil {
 		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/freezind/telegram-calories-bot/internal/middleware"
+	"github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+)
+
+// ProfileHandler handles user profile HTTP requests
+type ProfileHandler struct {
+	profiles storage.ProfileStorage
+}
+
+// NewProfileHandler creates a new profile handler
+func NewProfileHandler(profiles storage.ProfileStorage) *ProfileHandler {
+	return &ProfileHandler{profiles: profiles}
+}
+
+// GetProfile handles GET /api/profile
+// A user without a language gets one from their Telegram language_code
+func (h *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized: user ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	profile, err := h.profiles.GetProfile(userID)
+	if err != nil {
+		http.Error(w, "Failed to fetch profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if profile.ApplyLanguageCode(middleware.GetLanguageCode(r.Context())) {
+		if err := h.profiles.SaveProfile(profile); err != nil {
+			log.Printf("[API] GetProfile: Failed to save detected language for user %d: %v", userID, err)
+		}
+	}
+
+	writeProfile(w, profile)
+}
+
+// UpdateProfile handles PUT /api/profile (only the fields present are changed)
+func (h *ProfileHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized: user ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	var update models.ProfileUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.profiles.GetProfile(userID)
+	if err != nil {
+		http.Error(w, "Failed to fetch profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := profile.ApplyUpdate(&update); err != nil {
+		http.Error(w, "Failed to update profile: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.profiles.SaveProfile(profile); err != nil {
+		http.Error(w, "Failed to update profile: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("[API] UpdateProfile: User %d timezone=%q language=%q locale=%q units=%q energy=%q",
+		userID, profile.Timezone, profile.Language, profile.Locale, profile.Units, profile.Energy)
+	writeProfile(w, profile)
+}
+
+// writeProfile encodes the profile as the JSON response
+func writeProfile(w http.ResponseWriter, profile *models.UserProfile) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(profile); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/freezind/telegram-calories-bot/internal/middleware"
+	"github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+)
+
+// defaultStatsDays is the range used when the from parameter is omitted
+const defaultStatsDays = 30
+
+// StatsHandler handles aggregated statistics HTTP requests
+type StatsHandler struct {
+	logs     storage.LogStorage
+	profiles storage.ProfileStorage
+}
+
+// NewStatsHandler creates a new stats handler
+func NewStatsHandler(logs storage.LogStorage, profiles storage.ProfileStorage) *StatsHandler {
+	return &StatsHandler{logs: logs, profiles: profiles}
+}
+
+// GetStats handles GET /api/stats?from=&to=&granularity=day|week|month
+// from/to accept a date (YYYY-MM-DD, inclusive, in the user's timezone) or an RFC3339 instant
+// Defaults: to = end of today, from = 30 days before to, granularity = day
+func (h *StatsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized: user ID not found in context", http.StatusUnauthorized)
+		return
+	}
+
+	profile, err := h.profiles.GetProfile(userID)
+	if err != nil {
+		http.Error(w, "Failed to fetch profile: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	loc := profile.Location()
+
+	query := r.URL.Query()
+	granularity, err := models.ParseGranularity(query.Get("granularity"))
+	if err != nil {
+		http.Error(w, "Invalid granularity: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	_, to := models.DayBounds(time.Now(), loc)
+	if raw := query.Get("to"); raw != "" {
+		if to, err = models.ParseRangeBound(raw, loc, true); err != nil {
+			http.Error(w, "Invalid to: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	from := to.AddDate(0, 0, -defaultStatsDays)
+	if raw := query.Get("from"); raw != "" {
+		if from, err = models.ParseRangeBound(raw, loc, false); err != nil {
+			http.Error(w, "Invalid from: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	logs, err := h.logs.ListLogs(userID)
+	if err != nil {
+		http.Error(w, "Failed to fetch logs: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	stats, err := models.BuildStats(logs, from, to, granularity, loc)
+	if err != nil {
+		http.Error(w, "Invalid range: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		http.Error(w, "Failed to encode response: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -2,10 +2,13 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
+
 	"github.com/freezind/telegram-calories-bot/internal/auth"
 )
 
@@ -15,8 +18,28 @@ type contextKey string
 const (
 	// UserIDKey is the context key for storing authenticated user ID
 	UserIDKey contextKey = "userID"
+
+	// LanguageCodeKey is the context key for the user's Telegram language_code (absent in dev mode)
+	LanguageCodeKey contextKey = "languageCode"
+
+	// DefaultInitDataMaxAge is how long signed initData stays valid when INIT_DATA_MAX_AGE is unset
+	DefaultInitDataMaxAge = 24 * time.Hour
 )
 
+// initDataMaxAge reads INIT_DATA_MAX_AGE (Go duration, e.g. "1h"; "0" disables the check)
+func initDataMaxAge() time.Duration {
+	raw := os.Getenv("INIT_DATA_MAX_AGE")
+	if raw == "" {
+		return DefaultInitDataMaxAge
+	}
+	maxAge, err := time.ParseDuration(raw)
+	if err != nil {
+		log.Printf("[AUTH] ⚠️  Invalid INIT_DATA_MAX_AGE value: %s (error: %v), using default %v", raw, err, DefaultInitDataMaxAge)
+		return DefaultInitDataMaxAge
+	}
+	return maxAge
+}
+
 // AuthMiddleware validates Telegram initData and adds userID to request context
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -44,19 +67,35 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		}
 		log.Printf("[AUTH] ✓ X-Telegram-Init-Data header present (length: %d) for %s %s", len(initData), r.Method, r.URL.Path)
 
-		// Parse initData to extract user information
-		user, err := auth.ParseInitData(initData)
+		// The bot token is the HMAC secret for initData signatures
+		botToken := os.Getenv("TELEGRAM_BOT_TOKEN")
+		if botToken == "" {
+			log.Printf("[AUTH] ❌ TELEGRAM_BOT_TOKEN not set - cannot verify initData for %s %s", r.Method, r.URL.Path)
+			http.Error(w, "Server misconfigured: initData verification unavailable", http.StatusInternalServerError)
+			return
+		}
+
+		// Verify signature and freshness, then extract user information
+		user, err := auth.ValidateInitData(initData, botToken, initDataMaxAge())
 		if err != nil {
-			log.Printf("[AUTH] ❌ Failed to parse initData for %s %s: %v", r.Method, r.URL.Path, err)
-			http.Error(w, "Unauthorized: Invalid initData - "+err.Error(), http.StatusUnauthorized)
+			log.Printf("[AUTH] ❌ Failed to validate initData for %s %s: %v", r.Method, r.URL.Path, err)
+			switch {
+			case errors.Is(err, auth.ErrMissingHash), errors.Is(err, auth.ErrInvalidSignature):
+				http.Error(w, "Unauthorized: Invalid initData signature", http.StatusUnauthorized)
+			case errors.Is(err, auth.ErrExpired):
+				http.Error(w, "Unauthorized: initData expired - reopen the Mini App", http.StatusUnauthorized)
+			default:
+				http.Error(w, "Unauthorized: Invalid initData - "+err.Error(), http.StatusUnauthorized)
+			}
 			return
 		}
 
 		// Debug logging: successful auth
 		log.Printf("[AUTH] ✓ User authenticated: ID=%d, Username=%s for %s %s", user.ID, user.Username, r.Method, r.URL.Path)
 
-		// Add userID to request context
+		// Add userID and language to request context
 		ctx := context.WithValue(r.Context(), UserIDKey, user.ID)
+		ctx = context.WithValue(ctx, LanguageCodeKey, user.LanguageCode)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -66,3 +105,10 @@ func GetUserID(ctx context.Context) (int64, bool) {
 	userID, ok := ctx.Value(UserIDKey).(int64)
 	return userID, ok
 }
+
+// GetLanguageCode extracts the user's Telegram language_code from request context
+// Returns an empty string when unknown
+func GetLanguageCode(ctx context.Context) string {
+	code, _ := ctx.Value(LanguageCodeKey).(string)
+	return code
+}
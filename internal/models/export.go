@@ -0,0 +1,166 @@
+package models
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFormat is a file format for a user's log history
+type ExportFormat string
+
+const (
+	ExportCSV    ExportFormat = "csv"
+	ExportNDJSON ExportFormat = "ndjson" // One Log JSON object per line
+)
+
+// exportColumns is the CSV header; energy and weights are in the stored units (kcal, grams)
+var exportColumns = []string{
+	"date", "time", "meal_type", "calories_kcal",
+	"protein_g", "carbs_g", "fat_g", "fiber_g", "sugar_g",
+	"items", "confidence", "note", "id", "timestamp",
+}
+
+// ParseExportFormat validates an export format (empty defaults to CSV; "json" and "jsonl" mean NDJSON)
+func ParseExportFormat(value string) (ExportFormat, error) {
+	switch strings.ToLower(value) {
+	case "", "csv":
+		return ExportCSV, nil
+	case "ndjson", "jsonl", "json":
+		return ExportNDJSON, nil
+	default:
+		return "", errors.New("format must be one of: csv, ndjson")
+	}
+}
+
+// MIME returns the media type of the format, e.g. "text/csv"
+func (f ExportFormat) MIME() string {
+	if f == ExportNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv"
+}
+
+// ContentType returns the HTTP Content-Type of the format (CSV declares UTF-8)
+func (f ExportFormat) ContentType() string {
+	if f == ExportCSV {
+		return f.MIME() + "; charset=utf-8"
+	}
+	return f.MIME()
+}
+
+// FileName returns the download name for an export made at now, e.g. "calorie-log-2024-05-10.csv"
+func (f ExportFormat) FileName(now time.Time) string {
+	return "calorie-log-" + now.Format("2006-01-02") + "." + string(f)
+}
+
+// ExportLogs returns the logs with Timestamp in [from, to), oldest first (nil bounds are open)
+func ExportLogs(logs []Log, from, to *time.Time) []Log {
+	query := &LogQuery{From: from, To: to}
+	var result []Log
+	for i := range logs {
+		if query.Matches(&logs[i]) {
+			result = append(result, logs[i])
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result
+}
+
+// WriteExport writes logs in the given format; CSV dates and times are in loc
+func WriteExport(w io.Writer, format ExportFormat, logs []Log, loc *time.Location) error {
+	if format == ExportNDJSON {
+		return writeNDJSON(w, logs)
+	}
+	return writeCSV(w, logs, loc)
+}
+
+// writeNDJSON writes each log as a JSON object on its own line
+func writeNDJSON(w io.Writer, logs []Log) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for i := range logs {
+		if err := encoder.Encode(&logs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCSV writes a header row and one row per log
+func writeCSV(w io.Writer, logs []Log, loc *time.Location) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportColumns); err != nil {
+		return err
+	}
+
+	for _, l := range logs {
+		local := l.Timestamp.In(loc)
+		row := []string{
+			local.Format("2006-01-02"),
+			local.Format("15:04"),
+			string(l.MealType),
+			strconv.Itoa(l.Calories),
+			"", "", "", "", "",
+			spreadsheetSafe(exportItems(l.FoodItems)),
+			string(l.Confidence),
+			spreadsheetSafe(l.Note),
+			l.ID,
+			l.Timestamp.UTC().Format(time.RFC3339),
+		}
+		if m := l.Macros; m != nil {
+			row[4], row[5], row[6] = formatGrams(m.Protein), formatGrams(m.Carbs), formatGrams(m.Fat)
+			if m.Fiber != nil {
+				row[7] = formatGrams(*m.Fiber)
+			}
+			if m.Sugar != nil {
+				row[8] = formatGrams(*m.Sugar)
+			}
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportItems renders food items as "Rice (150 g, 200 kcal); Salt", omitting unknown parts
+func exportItems(items []FoodItem) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		var details []string
+		if item.PortionGrams > 0 {
+			details = append(details, formatGrams(item.PortionGrams)+" g")
+		}
+		if item.Calories != nil {
+			details = append(details, strconv.Itoa(*item.Calories)+" kcal")
+		}
+		parts[i] = item.Name
+		if len(details) > 0 {
+			parts[i] += " (" + strings.Join(details, ", ") + ")"
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// formatGrams formats a weight with the fewest digits needed, e.g. "12.5"
+func formatGrams(grams float64) string {
+	return strconv.FormatFloat(grams, 'f', -1, 64)
+}
+
+// spreadsheetSafe prefixes user text that a spreadsheet would evaluate as a formula
+func spreadsheetSafe(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// FoodItem is a single food within a log entry
+// The bot's estimator item (src/models.FoodItem) uses "grams" instead of "portionGrams" because
+// that is the key the estimation prompts ask the model for; this type is the API and storage schema
+type FoodItem struct {
+	Name         string  `json:"name"`
+	PortionGrams float64 `json:"portionGrams,omitempty"`
+
+	// Calories is nil when the item has no calorie estimate (legacy string items, or an item
+	// added without one); 0 is a real value, e.g. water or black coffee
+	Calories *int `json:"calories,omitempty"`
+}
+
+// UnmarshalJSON accepts either an item object or a plain string name
+// Keeps older clients and previously stored string-only items working
+func (i *FoodItem) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*i = FoodItem{Name: name}
+		return nil
+	}
+
+	type plain FoodItem // avoids recursing into this method
+	var item plain
+	if err := json.Unmarshal(data, &item); err != nil {
+		return err
+	}
+	*i = FoodItem(item)
+	return nil
+}
+
+// FoodItemEdit edits or removes a single item of a log, addressed by index
+type FoodItemEdit struct {
+	Index        int      `json:"index"`
+	Name         *string  `json:"name,omitempty"`
+	PortionGrams *float64 `json:"portionGrams,omitempty"`
+	Calories     *int     `json:"calories,omitempty"`
+	Remove       bool     `json:"remove,omitempty"`
+}
+
+// apply returns a copy of items with the edit applied; items itself is not modified
+func (e *FoodItemEdit) apply(items []FoodItem) ([]FoodItem, error) {
+	if e.Index < 0 || e.Index >= len(items) {
+		return nil, fmt.Errorf("item index %d out of range", e.Index)
+	}
+
+	if e.Remove {
+		result := make([]FoodItem, 0, len(items)-1)
+		result = append(result, items[:e.Index]...)
+		return append(result, items[e.Index+1:]...), nil
+	}
+
+	result := make([]FoodItem, len(items))
+	copy(result, items)
+	item := &result[e.Index]
+	if e.Name != nil {
+		item.Name = *e.Name
+	}
+	if e.PortionGrams != nil {
+		item.PortionGrams = *e.PortionGrams
+	}
+	if e.Calories != nil {
+		calories := *e.Calories
+		item.Calories = &calories
+	}
+	return result, nil
+}
+
+// ItemCalories returns the sum of per-item calories
+// ok is false when there are no items or any item has no calorie estimate, since a partial
+// sum would undercount
+func ItemCalories(items []FoodItem) (total int, ok bool) {
+	for _, item := range items {
+		if item.Calories == nil {
+			return 0, false
+		}
+		total += *item.Calories
+	}
+	return total, len(items) > 0
+}
+
+// validateItem checks a single item's numeric fields
+func validateItem(item FoodItem) error {
+	if item.PortionGrams < 0 {
+		return errors.New("item portion must be non-negative")
+	}
+	if item.Calories != nil && *item.Calories < 0 {
+		return errors.New("item calories must be non-negative")
+	}
+	return nil
+}
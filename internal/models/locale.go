@@ -0,0 +1,84 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultLanguage is used for users whose language is unknown
+const DefaultLanguage = "en"
+
+// UnitSystem is the measurement system used to display portion sizes
+type UnitSystem string
+
+const (
+	UnitsMetric   UnitSystem = "metric"
+	UnitsImperial UnitSystem = "imperial"
+)
+
+// ParseUnitSystem validates a unit system value (empty defaults to metric)
+func ParseUnitSystem(value string) (UnitSystem, error) {
+	switch UnitSystem(strings.ToLower(value)) {
+	case "":
+		return UnitsMetric, nil
+	case UnitsMetric, UnitsImperial:
+		return UnitSystem(strings.ToLower(value)), nil
+	default:
+		return "", errors.New("units must be one of: metric, imperial")
+	}
+}
+
+// NormalizeLocale validates a BCP 47 language tag and returns it in canonical case
+// e.g. "pt-br" → "pt-BR", "zh_hans" → "zh-Hans", "EN" → "en"
+// Only language, script and region subtags are supported
+func NormalizeLocale(tag string) (string, error) {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	if !isAlpha(parts[0]) || len(parts[0]) < 2 || len(parts[0]) > 3 {
+		return "", fmt.Errorf("invalid locale %q: expected a language code like en, es or pt-BR", tag)
+	}
+	parts[0] = strings.ToLower(parts[0])
+
+	for i := 1; i < len(parts); i++ {
+		part := parts[i]
+		switch {
+		case len(part) == 4 && isAlpha(part) && i == 1: // Script, e.g. Hans
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		case len(part) == 2 && isAlpha(part): // Region, e.g. BR
+			parts[i] = strings.ToUpper(part)
+		case len(part) == 3 && isDigits(part): // UN M.49 region, e.g. 419
+		default:
+			return "", fmt.Errorf("invalid locale %q: expected a language code like en, es or pt-BR", tag)
+		}
+	}
+	if len(parts) > 3 {
+		return "", fmt.Errorf("invalid locale %q: expected a language code like en, es or pt-BR", tag)
+	}
+	return strings.Join(parts, "-"), nil
+}
+
+// LanguageOf returns the language subtag of a normalized locale, e.g. "pt" for "pt-BR"
+func LanguageOf(locale string) string {
+	language, _, _ := strings.Cut(locale, "-")
+	return language
+}
+
+// isAlpha reports whether s is non-empty and only ASCII letters
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isDigits reports whether s is non-empty and only ASCII digits
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
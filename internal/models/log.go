@@ -4,6 +4,7 @@ import (
 	"errors"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // ConfidenceLevel represents the AI's confidence in calorie estimation
@@ -15,24 +16,60 @@ const (
 	ConfidenceLow    ConfidenceLevel = "low"
 )
 
+// Macros holds macronutrient amounts in grams
+type Macros struct {
+	Protein float64  `json:"protein"`
+	Carbs   float64  `json:"carbs"`
+	Fat     float64  `json:"fat"`
+	Fiber   *float64 `json:"fiber,omitempty"`
+	Sugar   *float64 `json:"sugar,omitempty"`
+}
+
 // Log represents a calorie log entry
 type Log struct {
-	ID         string          `json:"id"`
-	UserID     int64           `json:"userId"`
-	FoodItems  []string        `json:"foodItems"`
-	Calories   int             `json:"calories"`
-	Confidence ConfidenceLevel `json:"confidence"`
-	Timestamp  time.Time       `json:"timestamp"`
-	CreatedAt  time.Time       `json:"createdAt"`
-	UpdatedAt  time.Time       `json:"updatedAt"`
+	ID            string          `json:"id"`
+	UserID        int64           `json:"userId"`
+	FoodItems     []FoodItem      `json:"foodItems"`
+	Calories      int             `json:"calories"`
+	Macros        *Macros         `json:"macros,omitempty"`
+	Confidence    ConfidenceLevel `json:"confidence"`
+	Provider      string          `json:"provider,omitempty"`      // Estimator backend that produced the entry (empty for manual logs)
+	PromptVersion string          `json:"promptVersion,omitempty"` // Estimation prompt template version (empty for manual logs)
+	Note          string          `json:"note,omitempty"`          // User's caption or notes sent with the photo
+	MealType      MealType        `json:"mealType,omitempty"`      // Inferred from Timestamp on creation unless given
+	Timestamp     time.Time       `json:"timestamp"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	UpdatedAt     time.Time       `json:"updatedAt"`
 }
 
 // LogUpdate represents partial updates to a log entry
+// Item edits a single food item; the total is then recomputed from the items
 type LogUpdate struct {
-	FoodItems  *[]string        `json:"foodItems,omitempty"`
+	FoodItems  *[]FoodItem      `json:"foodItems,omitempty"`
+	Item       *FoodItemEdit    `json:"item,omitempty"`
 	Calories   *int             `json:"calories,omitempty"`
+	Macros     *Macros          `json:"macros,omitempty"`
 	Confidence *ConfidenceLevel `json:"confidence,omitempty"`
 	Timestamp  *time.Time       `json:"timestamp,omitempty"`
+	Note       *string          `json:"note,omitempty"`
+	MealType   *MealType        `json:"mealType,omitempty"`
+}
+
+// MaxNoteLength bounds Log.Note in characters (Telegram captions are at most 1024)
+const MaxNoteLength = 1024
+
+// Validate performs validation on a Macros instance
+func (m *Macros) Validate() error {
+	if m.Protein < 0 || m.Carbs < 0 || m.Fat < 0 {
+		return errors.New("macros must be non-negative")
+	}
+	if m.Fiber != nil && (*m.Fiber < 0 || *m.Fiber > m.Carbs) {
+		return errors.New("fiber must be between 0 and carbs")
+	}
+	if m.Sugar != nil && (*m.Sugar < 0 || *m.Sugar > m.Carbs) {
+		return errors.New("sugar must be between 0 and carbs")
+	}
+	return nil
 }
 
 // Validate performs validation on a Log instance
@@ -42,6 +79,13 @@ func (l *Log) Validate() error {
 		return errors.New("calories must be non-negative")
 	}
 
+	// Macros are optional but must be valid when present
+	if l.Macros != nil {
+		if err := l.Macros.Validate(); err != nil {
+			return err
+		}
+	}
+
 	// Confidence must be valid enum value
 	if l.Confidence != ConfidenceHigh && l.Confidence != ConfidenceMedium && l.Confidence != ConfidenceLow {
 		return errors.New("confidence must be one of: high, medium, low")
@@ -55,18 +99,85 @@ func (l *Log) Validate() error {
 		return errors.New("food items cannot exceed 10 items")
 	}
 
-	// Check total length of all food items
+	// Check total length of all food item names
 	totalLength := 0
 	for _, item := range l.FoodItems {
-		item = strings.TrimSpace(item)
-		if item == "" {
+		name := strings.TrimSpace(item.Name)
+		if name == "" {
 			return errors.New("food items cannot contain empty strings")
 		}
-		totalLength += len(item)
+		if err := validateItem(item); err != nil {
+			return err
+		}
+		totalLength += len(name)
 	}
 	if totalLength > 1000 {
 		return errors.New("total food items text cannot exceed 1000 characters")
 	}
 
+	if utf8.RuneCountInString(l.Note) > MaxNoteLength {
+		return errors.New("note cannot exceed 1024 characters")
+	}
+
+	// Meal type is optional (entries saved before it existed) but must be valid when present
+	if l.MealType != "" && !l.MealType.Valid() {
+		return errors.New("meal type must be one of: breakfast, lunch, dinner, snack")
+	}
+
+	return nil
+}
+
+// SyncCalories sets the total to the sum of per-item calories
+// Leaves the total unchanged unless every item carries a calorie estimate (e.g. manual
+// entries, or an item added without calories), since a partial sum would undercount
+func (l *Log) SyncCalories() {
+	if total, ok := ItemCalories(l.FoodItems); ok {
+		l.Calories = total
+	}
+}
+
+// ApplyUpdate applies a partial update to the log (does not validate or touch UpdatedAt)
+// When the items change and every item carries calories, the total is recomputed from
+// the item breakdown and overrides an explicit Calories value
+func (l *Log) ApplyUpdate(update *LogUpdate) error {
+	itemsChanged := false
+	if update.FoodItems != nil {
+		l.FoodItems = *update.FoodItems
+		itemsChanged = true
+	}
+	if update.Item != nil {
+		items, err := update.Item.apply(l.FoodItems)
+		if err != nil {
+			return err
+		}
+		l.FoodItems = items
+		itemsChanged = true
+	}
+
+	if update.Calories != nil {
+		l.Calories = *update.Calories
+	}
+	if itemsChanged {
+		l.SyncCalories()
+	}
+
+	if update.Macros != nil {
+		l.Macros = update.Macros
+	}
+	if update.Confidence != nil {
+		l.Confidence = *update.Confidence
+	}
+	if update.Timestamp != nil {
+		l.Timestamp = *update.Timestamp
+	}
+	if update.Note != nil {
+		l.Note = strings.TrimSpace(*update.Note)
+	}
+	if update.MealType != nil {
+		if !update.MealType.Valid() {
+			return errors.New("meal type must be one of: breakfast, lunch, dinner, snack")
+		}
+		l.MealType = *update.MealType
+	}
 	return nil
 }
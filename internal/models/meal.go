@@ -0,0 +1,142 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MealType classifies a log entry as one of the day's meals
+type MealType string
+
+const (
+	MealBreakfast MealType = "breakfast"
+	MealLunch     MealType = "lunch"
+	MealDinner    MealType = "dinner"
+	MealSnack     MealType = "snack"
+)
+
+// MealTypes lists the meal types in the order of the day (used for grouping and buttons)
+var MealTypes = []MealType{MealBreakfast, MealLunch, MealDinner, MealSnack}
+
+// Valid reports whether m is one of the known meal types
+func (m MealType) Valid() bool {
+	for _, t := range MealTypes {
+		if m == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Label returns the capitalized meal name, e.g. "Breakfast"
+func (m MealType) Label() string {
+	if m == "" {
+		return ""
+	}
+	return strings.ToUpper(string(m[:1])) + string(m[1:])
+}
+
+// MealWindow is a local time-of-day range [Start, End) assigned to a meal type
+// Start and End are minutes after midnight
+type MealWindow struct {
+	Type  MealType
+	Start int
+	End   int
+}
+
+// DefaultMealWindows are used when the user has not configured their own
+// Anything outside the windows is a snack
+var DefaultMealWindows = []MealWindow{
+	{Type: MealBreakfast, Start: 5 * 60, End: 10*60 + 30},
+	{Type: MealLunch, Start: 11 * 60, End: 14*60 + 30},
+	{Type: MealDinner, Start: 17*60 + 30, End: 21*60 + 30},
+}
+
+// DefaultMealWindowsSpec is DefaultMealWindows in the ParseMealWindows format
+var DefaultMealWindowsSpec = FormatMealWindows(DefaultMealWindows)
+
+// InferMealType returns the meal type whose window contains t in loc (snack when none does)
+func InferMealType(t time.Time, loc *time.Location, windows []MealWindow) MealType {
+	local := t.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	for _, w := range windows {
+		if minute >= w.Start && minute < w.End {
+			return w.Type
+		}
+	}
+	return MealSnack
+}
+
+// ParseMealWindows parses windows like "breakfast=06:00-10:00,lunch=12:00-15:00,dinner=18:00-22:00"
+// Meals left out have no window; windows must not overlap or wrap past midnight
+func ParseMealWindows(spec string) ([]MealWindow, error) {
+	var windows []MealWindow
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, span, ok := strings.Cut(part, "=")
+		mealType := MealType(strings.ToLower(strings.TrimSpace(name)))
+		if !ok || !mealType.Valid() || mealType == MealSnack {
+			return nil, fmt.Errorf("invalid meal window %q: expected breakfast, lunch or dinner=HH:MM-HH:MM", part)
+		}
+		from, to, ok := strings.Cut(span, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid meal window %q: expected HH:MM-HH:MM", part)
+		}
+		start, err := parseClock(from)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(to)
+		if err != nil {
+			return nil, err
+		}
+		if start >= end {
+			return nil, fmt.Errorf("meal window %q must end after it starts", part)
+		}
+
+		for _, w := range windows {
+			if w.Type == mealType {
+				return nil, fmt.Errorf("meal window for %s is given twice", mealType)
+			}
+			if start < w.End && w.Start < end {
+				return nil, fmt.Errorf("meal windows for %s and %s overlap", w.Type, mealType)
+			}
+		}
+		windows = append(windows, MealWindow{Type: mealType, Start: start, End: end})
+	}
+
+	if len(windows) == 0 {
+		return nil, errors.New("meal windows cannot be empty")
+	}
+	return windows, nil
+}
+
+// FormatMealWindows renders windows in the ParseMealWindows format
+func FormatMealWindows(windows []MealWindow) string {
+	parts := make([]string, len(windows))
+	for i, w := range windows {
+		parts[i] = fmt.Sprintf("%s=%s-%s", w.Type, formatClock(w.Start), formatClock(w.End))
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseClock parses "HH:MM" (24h, "24:00" allowed as an end) into minutes after midnight
+func parseClock(value string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(strings.TrimSpace(value), "%d:%d", &hour, &minute); err != nil ||
+		hour < 0 || minute < 0 || minute > 59 || hour*60+minute > 24*60 {
+		return 0, fmt.Errorf("invalid time %q: expected HH:MM", strings.TrimSpace(value))
+	}
+	return hour*60 + minute, nil
+}
+
+// formatClock renders minutes after midnight as "HH:MM"
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
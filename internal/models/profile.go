@@ -0,0 +1,265 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxDailyGoal is the upper bound accepted for a daily calorie goal (kcal)
+const MaxDailyGoal = 20000
+
+// MaxDietaryNotesLength bounds the dietary notes passed to the estimation prompt (characters)
+const MaxDietaryNotesLength = 200
+
+// UserProfile holds per-user preferences shared by the bot and the Mini App
+type UserProfile struct {
+	UserID int64 `json:"userId"`
+
+	// DailyGoal is the daily calorie target in kcal (0 means no goal set)
+	DailyGoal int `json:"dailyGoal"`
+
+	// Timezone is an IANA zone name (e.g. "Europe/Madrid"); empty means server local time
+	Timezone string `json:"timezone"`
+
+	// OneShot estimates any food photo immediately, without sending /estimate first
+	OneShot bool `json:"oneShot"`
+
+	// MealWindows overrides DefaultMealWindows in the ParseMealWindows format (empty means defaults)
+	MealWindows string `json:"mealWindows,omitempty"`
+
+	// Language is the ISO 639 code for bot replies (e.g. "es"); filled from Telegram's language_code
+	Language string `json:"language"`
+
+	// Locale is a BCP 47 tag for number and date formatting (e.g. "es-MX"); empty means Language
+	Locale string `json:"locale"`
+
+	// Units is the measurement system for portion sizes (empty means metric)
+	Units UnitSystem `json:"units"`
+
+	// Energy is the unit for displaying calories (empty means kcal); logs are always stored in kcal
+	Energy EnergyUnit `json:"energy"`
+
+	// DietaryNotes is free text passed to the estimator, e.g. "vegetarian, cooks with olive oil"
+	DietaryNotes string `json:"dietaryNotes"`
+
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Validate performs validation on a UserProfile instance
+func (p *UserProfile) Validate() error {
+	if p.DailyGoal < 0 {
+		return errors.New("daily goal must be non-negative")
+	}
+	if p.DailyGoal > MaxDailyGoal {
+		return errors.New("daily goal cannot exceed 20000 kcal")
+	}
+	if p.Timezone != "" {
+		if _, err := time.LoadLocation(p.Timezone); err != nil {
+			return errors.New("timezone must be a valid IANA name (e.g. Europe/Madrid)")
+		}
+	}
+	if p.MealWindows != "" {
+		if _, err := ParseMealWindows(p.MealWindows); err != nil {
+			return err
+		}
+	}
+	if p.Language != "" {
+		if locale, err := NormalizeLocale(p.Language); err != nil || locale != LanguageOf(locale) {
+			return errors.New("language must be an ISO 639 code (e.g. en, es)")
+		}
+	}
+	if p.Locale != "" {
+		if _, err := NormalizeLocale(p.Locale); err != nil {
+			return err
+		}
+	}
+	if _, err := ParseUnitSystem(string(p.Units)); err != nil {
+		return err
+	}
+	if _, err := ParseEnergyUnit(string(p.Energy)); err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(p.DietaryNotes) > MaxDietaryNotesLength {
+		return errors.New("dietary notes cannot exceed 200 characters")
+	}
+	if strings.ContainsFunc(p.DietaryNotes, unicode.IsControl) {
+		return errors.New("dietary notes must be a single line")
+	}
+	return nil
+}
+
+// ProfileUpdate represents partial updates to a profile (PUT /api/profile)
+type ProfileUpdate struct {
+	DailyGoal    *int        `json:"dailyGoal,omitempty"`
+	Timezone     *string     `json:"timezone,omitempty"`
+	OneShot      *bool       `json:"oneShot,omitempty"`
+	MealWindows  *string     `json:"mealWindows,omitempty"`
+	Language     *string     `json:"language,omitempty"`
+	Locale       *string     `json:"locale,omitempty"`
+	Units        *UnitSystem `json:"units,omitempty"`
+	Energy       *EnergyUnit `json:"energy,omitempty"`
+	DietaryNotes *string     `json:"dietaryNotes,omitempty"`
+}
+
+// ApplyUpdate applies a partial update to the profile (does not validate)
+// Language and locale are normalized; setting a locale also sets the language
+func (p *UserProfile) ApplyUpdate(update *ProfileUpdate) error {
+	if update.DailyGoal != nil {
+		p.DailyGoal = *update.DailyGoal
+	}
+	if update.Timezone != nil {
+		p.Timezone = *update.Timezone
+	}
+	if update.OneShot != nil {
+		p.OneShot = *update.OneShot
+	}
+	if update.MealWindows != nil {
+		p.MealWindows = *update.MealWindows
+	}
+	if update.Language != nil {
+		p.SetLanguage(*update.Language)
+	}
+	if update.Locale != nil {
+		if err := p.SetLocale(*update.Locale); err != nil {
+			return err
+		}
+	}
+	if update.Units != nil {
+		p.Units = UnitSystem(strings.ToLower(string(*update.Units)))
+	}
+	if update.Energy != nil {
+		p.Energy = EnergyUnit(strings.ToLower(string(*update.Energy)))
+	}
+	if update.DietaryNotes != nil {
+		p.DietaryNotes = strings.TrimSpace(*update.DietaryNotes)
+	}
+	return nil
+}
+
+// SetLanguage stores a language code, dropping a locale for a different language (empty clears it)
+func (p *UserProfile) SetLanguage(language string) {
+	p.Language = strings.ToLower(strings.TrimSpace(language))
+	if p.Locale != "" && LanguageOf(p.Locale) != p.Language {
+		p.Locale = ""
+	}
+}
+
+// SetLocale normalizes and stores a locale tag and switches Language to match (empty clears it)
+func (p *UserProfile) SetLocale(tag string) error {
+	if strings.TrimSpace(tag) == "" {
+		p.Locale = ""
+		return nil
+	}
+	locale, err := NormalizeLocale(tag)
+	if err != nil {
+		return err
+	}
+	p.Locale = locale
+	p.Language = LanguageOf(locale)
+	return nil
+}
+
+// ApplyLanguageCode fills Language and Locale from Telegram's language_code (e.g. "pt-br")
+// unless the user already has a language; reports whether the profile changed
+func (p *UserProfile) ApplyLanguageCode(code string) bool {
+	if p.Language != "" || code == "" {
+		return false
+	}
+	locale, err := NormalizeLocale(code)
+	if err != nil {
+		return false
+	}
+	p.Language = LanguageOf(locale)
+	if p.Locale == "" {
+		p.Locale = locale
+	}
+	return true
+}
+
+// LanguageOrDefault returns the user's language, falling back to DefaultLanguage
+func (p *UserProfile) LanguageOrDefault() string {
+	if p.Language == "" {
+		return DefaultLanguage
+	}
+	return p.Language
+}
+
+// LocaleOrDefault returns the locale for number and date formatting, falling back to the language
+func (p *UserProfile) LocaleOrDefault() string {
+	if p.Locale == "" {
+		return p.LanguageOrDefault()
+	}
+	return p.Locale
+}
+
+// UnitsOrDefault returns the user's unit system, falling back to metric
+func (p *UserProfile) UnitsOrDefault() UnitSystem {
+	if p.Units == "" {
+		return UnitsMetric
+	}
+	return p.Units
+}
+
+// EnergyOrDefault returns the user's energy unit, falling back to kcal
+func (p *UserProfile) EnergyOrDefault() EnergyUnit {
+	if p.Energy == "" {
+		return EnergyKcal
+	}
+	return p.Energy
+}
+
+// DisplayUnits returns the units the user sees energy and portion sizes in
+func (p *UserProfile) DisplayUnits() DisplayUnits {
+	return DisplayUnits{System: p.UnitsOrDefault(), Energy: p.EnergyOrDefault()}
+}
+
+// Location returns the profile's time zone, falling back to server local time
+func (p *UserProfile) Location() *time.Location {
+	if p.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// MealWindowList returns the user's meal windows, falling back to DefaultMealWindows
+func (p *UserProfile) MealWindowList() []MealWindow {
+	if p.MealWindows == "" {
+		return DefaultMealWindows
+	}
+	windows, err := ParseMealWindows(p.MealWindows)
+	if err != nil {
+		return DefaultMealWindows
+	}
+	return windows
+}
+
+// MealTypeAt infers the meal eaten at t from the user's timezone and meal windows
+func (p *UserProfile) MealTypeAt(t time.Time) MealType {
+	return InferMealType(t, p.Location(), p.MealWindowList())
+}
+
+// DayBounds returns the [start, end) interval of the calendar day containing t in loc
+func DayBounds(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	local := t.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// CaloriesOnDay sums calories of logs whose Timestamp falls on the same calendar day as day in loc
+func CaloriesOnDay(logs []Log, day time.Time, loc *time.Location) int {
+	start, end := DayBounds(day, loc)
+	total := 0
+	for _, l := range logs {
+		if !l.Timestamp.Before(start) && l.Timestamp.Before(end) {
+			total += l.Calories
+		}
+	}
+	return total
+}
@@ -0,0 +1,182 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPageSize is the number of logs returned when no limit is given
+	DefaultPageSize = 50
+
+	// MaxPageSize bounds the limit a client may request
+	MaxPageSize = 200
+)
+
+// LogOrder is the order of query results by Timestamp, then ID
+type LogOrder string
+
+const (
+	OrderDesc LogOrder = "desc" // Newest first (default)
+	OrderAsc  LogOrder = "asc"  // Oldest first
+)
+
+// ParseLogOrder validates a sort order (empty defaults to newest first)
+func ParseLogOrder(value string) (LogOrder, error) {
+	switch LogOrder(strings.ToLower(value)) {
+	case "", OrderDesc:
+		return OrderDesc, nil
+	case OrderAsc:
+		return OrderAsc, nil
+	default:
+		return "", errors.New("order must be one of: asc, desc")
+	}
+}
+
+// LogQuery filters and paginates a user's logs
+// Results are ordered by Timestamp, then ID, in Order (descending by default)
+type LogQuery struct {
+	From       *time.Time        // Inclusive lower bound on Timestamp
+	To         *time.Time        // Exclusive upper bound on Timestamp
+	Confidence []ConfidenceLevel // Any of these levels (empty = all)
+	Search     string            // Case-insensitive substring of any food item name
+	Order      LogOrder          // OrderDesc or OrderAsc (empty = OrderDesc)
+	Limit      int               // Page size (0 = DefaultPageSize)
+	Cursor     string            // Opaque cursor from a previous LogPage.NextCursor, issued for the same Order
+}
+
+// LogPage is a single page of query results
+type LogPage struct {
+	Logs       []Log  `json:"logs"`
+	NextCursor string `json:"nextCursor,omitempty"` // Empty when there are no more results
+}
+
+// Validate checks query bounds and normalizes Limit and Order
+func (q *LogQuery) Validate() error {
+	if q.Limit < 0 {
+		return errors.New("limit must not be negative")
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultPageSize
+	}
+	if q.Limit > MaxPageSize {
+		return fmt.Errorf("limit must be at most %d", MaxPageSize)
+	}
+	if q.From != nil && q.To != nil && !q.From.Before(*q.To) {
+		return errors.New("from must be before to")
+	}
+	for _, level := range q.Confidence {
+		if level != ConfidenceHigh && level != ConfidenceMedium && level != ConfidenceLow {
+			return fmt.Errorf("invalid confidence: %s", level)
+		}
+	}
+	order, err := ParseLogOrder(string(q.Order))
+	if err != nil {
+		return err
+	}
+	q.Order = order
+	if q.Cursor != "" {
+		_, _, cursorOrder, err := DecodeLogCursor(q.Cursor)
+		if err != nil {
+			return err
+		}
+		if cursorOrder != q.Order {
+			return errors.New("cursor was issued for a different order")
+		}
+	}
+	return nil
+}
+
+// Matches reports whether a log passes the query filters (cursor and limit are not applied)
+func (q *LogQuery) Matches(l *Log) bool {
+	if q.From != nil && l.Timestamp.Before(*q.From) {
+		return false
+	}
+	if q.To != nil && !l.Timestamp.Before(*q.To) {
+		return false
+	}
+	if len(q.Confidence) > 0 {
+		found := false
+		for _, level := range q.Confidence {
+			if l.Confidence == level {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if q.Search != "" {
+		needle := strings.ToLower(q.Search)
+		found := false
+		for _, item := range l.FoodItems {
+			if strings.Contains(strings.ToLower(item.Name), needle) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseRangeBound parses a date (YYYY-MM-DD) in loc or an RFC3339 timestamp
+// Dates used as an upper bound include the whole day (end of day, exclusive)
+func ParseRangeBound(raw string, loc *time.Location, endOfDay bool) (time.Time, error) {
+	if day, err := time.ParseInLocation("2006-01-02", raw, loc); err == nil {
+		if endOfDay {
+			return day.AddDate(0, 0, 1), nil
+		}
+		return day, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.New("expected YYYY-MM-DD or RFC3339 timestamp")
+}
+
+// EncodeLogCursor builds the cursor pointing just past the given log in the given order
+func EncodeLogCursor(l *Log, order LogOrder) string {
+	raw := string(order) + ":" + strconv.FormatInt(l.Timestamp.UnixNano(), 10) + ":" + l.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeLogCursor returns the Timestamp and ID of the last log on the previous page and the
+// order the cursor was issued for
+func DecodeLogCursor(cursor string) (time.Time, string, LogOrder, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", "", errors.New("invalid cursor")
+	}
+	order, rest, _ := strings.Cut(string(raw), ":")
+	nanos, id, ok := strings.Cut(rest, ":")
+	if !ok || id == "" || (LogOrder(order) != OrderAsc && LogOrder(order) != OrderDesc) {
+		return time.Time{}, "", "", errors.New("invalid cursor")
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, "", "", errors.New("invalid cursor")
+	}
+	return time.Unix(0, n), id, LogOrder(order), nil
+}
+
+// AfterCursor reports whether l comes after the cursor position in (Timestamp, ID) order
+func AfterCursor(l *Log, cursorTime time.Time, cursorID string, order LogOrder) bool {
+	if order == OrderAsc {
+		if !l.Timestamp.Equal(cursorTime) {
+			return l.Timestamp.After(cursorTime)
+		}
+		return l.ID > cursorID
+	}
+	if !l.Timestamp.Equal(cursorTime) {
+		return l.Timestamp.Before(cursorTime)
+	}
+	return l.ID < cursorID
+}
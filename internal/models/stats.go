@@ -0,0 +1,141 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Granularity is the bucket size for aggregated statistics
+type Granularity string
+
+const (
+	GranularityDay   Granularity = "day"
+	GranularityWeek  Granularity = "week"
+	GranularityMonth Granularity = "month"
+)
+
+// MaxStatsBuckets bounds the number of buckets a single stats request may produce
+const MaxStatsBuckets = 400
+
+// ConfidenceCounts is the number of entries per confidence level
+type ConfidenceCounts struct {
+	High   int `json:"high"`
+	Medium int `json:"medium"`
+	Low    int `json:"low"`
+}
+
+// add counts one entry with the given confidence
+func (c *ConfidenceCounts) add(level ConfidenceLevel) {
+	switch level {
+	case ConfidenceHigh:
+		c.High++
+	case ConfidenceMedium:
+		c.Medium++
+	case ConfidenceLow:
+		c.Low++
+	}
+}
+
+// StatsBucket aggregates the logs falling into [Start, End)
+type StatsBucket struct {
+	Start      time.Time        `json:"start"`
+	End        time.Time        `json:"end"`
+	Calories   int              `json:"calories"`
+	Entries    int              `json:"entries"`
+	Confidence ConfidenceCounts `json:"confidence"`
+}
+
+// Stats is the bucketed aggregate returned by /api/stats
+type Stats struct {
+	From          time.Time        `json:"from"`
+	To            time.Time        `json:"to"`
+	Granularity   Granularity      `json:"granularity"`
+	Timezone      string           `json:"timezone"`
+	Buckets       []StatsBucket    `json:"buckets"`
+	TotalCalories int              `json:"totalCalories"`
+	Entries       int              `json:"entries"`
+	Confidence    ConfidenceCounts `json:"confidence"`
+}
+
+// ParseGranularity validates a granularity query value (empty defaults to day)
+func ParseGranularity(value string) (Granularity, error) {
+	switch Granularity(value) {
+	case "":
+		return GranularityDay, nil
+	case GranularityDay, GranularityWeek, GranularityMonth:
+		return Granularity(value), nil
+	default:
+		return "", errors.New("granularity must be one of: day, week, month")
+	}
+}
+
+// BucketStart truncates t to the start of its bucket in loc
+// Weeks start on Monday; months start on the 1st
+func BucketStart(t time.Time, g Granularity, loc *time.Location) time.Time {
+	local := t.In(loc)
+	switch g {
+	case GranularityWeek:
+		offset := (int(local.Weekday()) + 6) % 7 // Monday = 0
+		return time.Date(local.Year(), local.Month(), local.Day()-offset, 0, 0, 0, 0, loc)
+	case GranularityMonth:
+		return time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	}
+}
+
+// nextBucket returns the start of the bucket following start
+func nextBucket(start time.Time, g Granularity) time.Time {
+	switch g {
+	case GranularityWeek:
+		return start.AddDate(0, 0, 7)
+	case GranularityMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// BuildStats aggregates logs with Timestamp in [from, to) into calendar buckets in loc
+// The first bucket starts at the bucket boundary containing from
+func BuildStats(logs []Log, from, to time.Time, g Granularity, loc *time.Location) (*Stats, error) {
+	if !from.Before(to) {
+		return nil, errors.New("from must be before to")
+	}
+
+	stats := &Stats{
+		From:        from,
+		To:          to,
+		Granularity: g,
+		Timezone:    loc.String(),
+		Buckets:     []StatsBucket{},
+	}
+
+	for start := BucketStart(from, g, loc); start.Before(to); start = nextBucket(start, g) {
+		if len(stats.Buckets) >= MaxStatsBuckets {
+			return nil, errors.New("range too large for requested granularity")
+		}
+		stats.Buckets = append(stats.Buckets, StatsBucket{Start: start, End: nextBucket(start, g)})
+	}
+
+	for _, l := range logs {
+		if l.Timestamp.Before(from) || !l.Timestamp.Before(to) {
+			continue
+		}
+		for i := range stats.Buckets {
+			bucket := &stats.Buckets[i]
+			if l.Timestamp.Before(bucket.Start) || !l.Timestamp.Before(bucket.End) {
+				continue
+			}
+			bucket.Calories += l.Calories
+			bucket.Entries++
+			bucket.Confidence.add(l.Confidence)
+			break
+		}
+		stats.TotalCalories += l.Calories
+		stats.Entries++
+		stats.Confidence.add(l.Confidence)
+	}
+
+	return stats, nil
+}
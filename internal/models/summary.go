@@ -0,0 +1,117 @@
+package models
+
+import "time"
+
+// DaySummary aggregates a single calendar day of logs
+type DaySummary struct {
+	Date     time.Time `json:"date"` // Midnight at the start of the day in the user's timezone
+	Calories int       `json:"calories"`
+	Entries  int       `json:"entries"`
+}
+
+// MealSummary aggregates the entries of one meal type
+type MealSummary struct {
+	MealType MealType `json:"mealType"`
+	Calories int      `json:"calories"`
+	Entries  int      `json:"entries"`
+}
+
+// PeriodSummary aggregates logs over a range of whole days
+type PeriodSummary struct {
+	Start time.Time     `json:"start"` // Inclusive, midnight in the user's timezone
+	End   time.Time     `json:"end"`   // Exclusive, midnight in the user's timezone
+	Days  []DaySummary  `json:"days"`  // One entry per calendar day, oldest first
+	Meals []MealSummary `json:"meals"` // One entry per meal type in MealTypes order
+
+	TotalCalories int `json:"totalCalories"`
+	Entries       int `json:"entries"`
+	DaysLogged    int `json:"daysLogged"` // Days with at least one entry
+
+	// Goal fields are only meaningful when DailyGoal > 0
+	DailyGoal      int `json:"dailyGoal"`
+	DaysWithinGoal int `json:"daysWithinGoal"` // Logged days at or under the goal
+}
+
+// SummarizeDays aggregates logs over the given number of whole days ending with the day of now
+// Days are bucketed in loc so "today" matches the user's calendar; logs without a meal type are
+// grouped by the user's meal windows
+func SummarizeDays(logs []Log, now time.Time, days int, loc *time.Location, windows []MealWindow, dailyGoal int) PeriodSummary {
+	todayStart, end := DayBounds(now, loc)
+	start := todayStart.AddDate(0, 0, -(days - 1))
+
+	summary := PeriodSummary{
+		Start:     start,
+		End:       end,
+		Days:      make([]DaySummary, days),
+		Meals:     make([]MealSummary, len(MealTypes)),
+		DailyGoal: dailyGoal,
+	}
+	for i := range summary.Days {
+		summary.Days[i].Date = start.AddDate(0, 0, i)
+	}
+	for i, mealType := range MealTypes {
+		summary.Meals[i].MealType = mealType
+	}
+
+	for _, l := range logs {
+		if l.Timestamp.Before(start) || !l.Timestamp.Before(end) {
+			continue
+		}
+		dayStart, _ := DayBounds(l.Timestamp, loc)
+		// Count calendar days rather than dividing durations (DST days are 23h/25h)
+		idx := daysBetween(start, dayStart)
+		if idx < 0 || idx >= days {
+			continue
+		}
+		summary.Days[idx].Calories += l.Calories
+		summary.Days[idx].Entries++
+		meal := &summary.Meals[mealIndex(l, loc, windows)]
+		meal.Calories += l.Calories
+		meal.Entries++
+		summary.TotalCalories += l.Calories
+		summary.Entries++
+	}
+
+	for _, day := range summary.Days {
+		if day.Entries == 0 {
+			continue
+		}
+		summary.DaysLogged++
+		if dailyGoal > 0 && day.Calories <= dailyGoal {
+			summary.DaysWithinGoal++
+		}
+	}
+
+	return summary
+}
+
+// AveragePerLoggedDay returns the mean calories over days that have entries
+func (s *PeriodSummary) AveragePerLoggedDay() int {
+	if s.DaysLogged == 0 {
+		return 0
+	}
+	return s.TotalCalories / s.DaysLogged
+}
+
+// mealIndex returns the position of the log's meal type in MealTypes
+// Entries saved before meal types existed are classified with the given windows
+func mealIndex(l Log, loc *time.Location, windows []MealWindow) int {
+	mealType := l.MealType
+	if !mealType.Valid() {
+		mealType = InferMealType(l.Timestamp, loc, windows)
+	}
+	for i, t := range MealTypes {
+		if t == mealType {
+			return i
+		}
+	}
+	return len(MealTypes) - 1
+}
+
+// daysBetween counts calendar days from a to b (both midnights in the same location)
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	// Compare as UTC dates so the result is independent of DST offsets
+	return int(time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC).Sub(time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+}
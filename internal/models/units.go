@@ -0,0 +1,119 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// EnergyUnit is the unit used to display energy; stored values are always kcal
+type EnergyUnit string
+
+const (
+	EnergyKcal       EnergyUnit = "kcal"
+	EnergyKilojoules EnergyUnit = "kj"
+)
+
+const (
+	// KilojoulesPerKcal converts thermochemical kilocalories to kilojoules
+	KilojoulesPerKcal = 4.184
+
+	// GramsPerOunce is the avoirdupois ounce in grams
+	GramsPerOunce = 28.349523125
+)
+
+// ParseEnergyUnit validates an energy unit value (empty defaults to kcal)
+func ParseEnergyUnit(value string) (EnergyUnit, error) {
+	switch EnergyUnit(strings.ToLower(value)) {
+	case "":
+		return EnergyKcal, nil
+	case EnergyKcal, EnergyKilojoules:
+		return EnergyUnit(strings.ToLower(value)), nil
+	default:
+		return "", errors.New("energy must be one of: kcal, kj")
+	}
+}
+
+// DisplayUnits selects how energy and portion sizes are presented (zero value is kcal and grams)
+type DisplayUnits struct {
+	System UnitSystem `json:"system"`
+	Energy EnergyUnit `json:"energy"`
+}
+
+// ConvertEnergy converts kcal to the display energy unit, rounded to a whole number
+func (u DisplayUnits) ConvertEnergy(kcal int) int {
+	if u.Energy == EnergyKilojoules {
+		return int(math.Round(float64(kcal) * KilojoulesPerKcal))
+	}
+	return kcal
+}
+
+// ConvertPortion converts grams to the display unit, returning the amount and "g" or "oz"
+// Ounces are rounded to one decimal place
+func (u DisplayUnits) ConvertPortion(grams float64) (float64, string) {
+	if u.System == UnitsImperial {
+		return math.Round(grams/GramsPerOunce*10) / 10, "oz"
+	}
+	return grams, "g"
+}
+
+// LogDisplay is a log's energy and item portions converted to display units
+type LogDisplay struct {
+	Energy     int           `json:"energy"`
+	EnergyUnit EnergyUnit    `json:"energyUnit"`
+	Items      []ItemDisplay `json:"items,omitempty"`
+}
+
+// ItemDisplay is one food item in display units, in the same order as Log.FoodItems
+type ItemDisplay struct {
+	Portion     float64 `json:"portion,omitempty"`     // 0 when the portion is unknown
+	PortionUnit string  `json:"portionUnit,omitempty"` // "g" or "oz"
+	Energy      *int    `json:"energy,omitempty"`      // nil when the item has no calorie estimate
+}
+
+// Display converts a log's calories and item portions to the display units
+func (u DisplayUnits) Display(l *Log) *LogDisplay {
+	display := &LogDisplay{Energy: u.ConvertEnergy(l.Calories), EnergyUnit: u.energyUnit()}
+	for _, item := range l.FoodItems {
+		var d ItemDisplay
+		if item.Calories != nil {
+			energy := u.ConvertEnergy(*item.Calories)
+			d.Energy = &energy
+		}
+		if item.PortionGrams > 0 {
+			d.Portion, d.PortionUnit = u.ConvertPortion(item.PortionGrams)
+		}
+		display.Items = append(display.Items, d)
+	}
+	return display
+}
+
+// energyUnit returns the energy unit, defaulting to kcal
+func (u DisplayUnits) energyUnit() EnergyUnit {
+	if u.Energy == "" {
+		return EnergyKcal
+	}
+	return u.Energy
+}
+
+// DisplayLog is a Log together with its values in display units
+type DisplayLog struct {
+	Log
+	Display *LogDisplay `json:"display"`
+}
+
+// DisplayLogPage is a LogPage with each log converted to display units (GET /api/logs)
+type DisplayLogPage struct {
+	Logs       []DisplayLog `json:"logs"`
+	NextCursor string       `json:"nextCursor,omitempty"`
+	Units      DisplayUnits `json:"units"`
+}
+
+// DisplayPage converts every log of a page to the display units
+func (u DisplayUnits) DisplayPage(page *LogPage) *DisplayLogPage {
+	result := &DisplayLogPage{Logs: make([]DisplayLog, 0, len(page.Logs)), NextCursor: page.NextCursor, Units: u}
+	for i := range page.Logs {
+		result.Logs = append(result.Logs, DisplayLog{Log: page.Logs[i], Display: u.Display(&page.Logs[i])})
+	}
+	return result
+}
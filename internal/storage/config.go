@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+// DefaultSQLitePath is used when STORAGE_DRIVER=sqlite and SQLITE_PATH is not set
+const DefaultSQLitePath = "data/calories.db"
+
+// NewFromEnv selects the storage backend from environment variables
+//   - STORAGE_DRIVER: "memory" (default) or "sqlite"
+//   - SQLITE_PATH: database file location for the sqlite driver
+func NewFromEnv() (Store, error) {
+	driver := os.Getenv("STORAGE_DRIVER")
+
+	switch driver {
+	case "", "memory":
+		log.Println("[STORAGE] Using in-memory storage (data is lost on restart)")
+		return NewMemoryStorage(), nil
+	case "sqlite":
+		path := os.Getenv("SQLITE_PATH")
+		if path == "" {
+			path = DefaultSQLitePath
+		}
+		store, err := NewSQLiteStorage(path)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("[STORAGE] Using SQLite storage at %s", path)
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q (expected \"memory\" or \"sqlite\")", driver)
+	}
+}
@@ -7,6 +7,11 @@ type LogStorage interface {
 	// ListLogs retrieves all logs for a given user, sorted by Timestamp descending
 	ListLogs(userID int64) ([]models.Log, error)
 
+	// QueryLogs retrieves one page of a user's logs matching the query
+	// Ordered by Timestamp (ID breaks ties), descending unless query.Order is asc; NextCursor
+	// is empty on the last page
+	QueryLogs(userID int64, query *models.LogQuery) (*models.LogPage, error)
+
 	// CreateLog creates a new log entry for a user
 	CreateLog(userID int64, log *models.Log) error
 
@@ -18,3 +23,19 @@ type LogStorage interface {
 	// Returns error if log not found or user is not authorized
 	DeleteLog(userID int64, logID string) error
 }
+
+// ProfileStorage defines the interface for per-user profile persistence
+type ProfileStorage interface {
+	// GetProfile retrieves a user's profile
+	// Returns a default (empty) profile if the user has not saved one yet
+	GetProfile(userID int64) (*models.UserProfile, error)
+
+	// SaveProfile creates or replaces a user's profile
+	SaveProfile(profile *models.UserProfile) error
+}
+
+// Store combines all persistence interfaces implemented by a storage backend
+type Store interface {
+	LogStorage
+	ProfileStorage
+}
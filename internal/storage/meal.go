@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"log"
+
+	"github.com/freezind/telegram-calories-bot/internal/models"
+)
+
+// inferMealType fills in a missing MealType from the log's Timestamp and the user's profile
+// (timezone and meal windows), falling back to the defaults when the profile cannot be read
+func inferMealType(profiles ProfileStorage, userID int64, logEntry *models.Log) {
+	if logEntry.MealType != "" {
+		return
+	}
+
+	profile, err := profiles.GetProfile(userID)
+	if err != nil {
+		log.Printf("[STORAGE] Failed to load profile for user %d, using default meal windows: %v", userID, err)
+		profile = &models.UserProfile{UserID: userID}
+	}
+	logEntry.MealType = profile.MealTypeAt(logEntry.Timestamp)
+}
@@ -11,16 +11,18 @@ import (
 	"github.com/freezind/telegram-calories-bot/internal/models"
 )
 
-// MemoryStorage implements LogStorage using in-memory map
+// MemoryStorage implements LogStorage and ProfileStorage using in-memory maps
 type MemoryStorage struct {
-	mu   sync.RWMutex
-	logs map[int64][]models.Log
+	mu       sync.RWMutex
+	logs     map[int64][]models.Log
+	profiles map[int64]models.UserProfile
 }
 
 // NewMemoryStorage creates a new in-memory storage instance
 func NewMemoryStorage() *MemoryStorage {
 	return &MemoryStorage{
-		logs: make(map[int64][]models.Log),
+		logs:     make(map[int64][]models.Log),
+		profiles: make(map[int64]models.UserProfile),
 	}
 }
 
@@ -54,11 +56,61 @@ func (s *MemoryStorage) ListLogs(userID int64) ([]models.Log, error) {
 	return result, nil
 }
 
+// QueryLogs retrieves one page of a user's logs matching the query
+func (s *MemoryStorage) QueryLogs(userID int64, query *models.LogQuery) (*models.LogPage, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var cursorTime time.Time
+	var cursorID string
+	if query.Cursor != "" {
+		cursorTime, cursorID, _, _ = models.DecodeLogCursor(query.Cursor)
+	}
+
+	s.mu.RLock()
+	matched := []models.Log{}
+	for _, l := range s.logs[userID] {
+		if !query.Matches(&l) {
+			continue
+		}
+		if query.Cursor != "" && !models.AfterCursor(&l, cursorTime, cursorID, query.Order) {
+			continue
+		}
+		matched = append(matched, l)
+	}
+	s.mu.RUnlock()
+
+	// Sort by Timestamp, then ID, in the query order for a stable cursor order
+	sort.Slice(matched, func(i, j int) bool {
+		a, b := &matched[i], &matched[j]
+		if query.Order == models.OrderAsc {
+			a, b = b, a
+		}
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.After(b.Timestamp)
+		}
+		return a.ID > b.ID
+	})
+
+	page := &models.LogPage{Logs: matched}
+	if len(matched) > query.Limit {
+		page.Logs = matched[:query.Limit]
+		page.NextCursor = models.EncodeLogCursor(&page.Logs[query.Limit-1], query.Order)
+	}
+
+	log.Printf("[STORAGE] User %d query returned %d log(s)", userID, len(page.Logs))
+	return page, nil
+}
+
 // CreateLog creates a new log entry
 func (s *MemoryStorage) CreateLog(userID int64, logEntry *models.Log) error {
+	logEntry.SyncCalories()
 	if err := logEntry.Validate(); err != nil {
 		return err
 	}
+	// GetProfile takes the lock itself
+	inferMealType(s, userID, logEntry)
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -108,19 +160,11 @@ func (s *MemoryStorage) UpdateLog(userID int64, logID string, update *models.Log
 		return errors.New("log not found")
 	}
 
-	// Apply updates
-	logEntry := &s.logs[userID][logIndex]
-	if update.FoodItems != nil {
-		logEntry.FoodItems = *update.FoodItems
-	}
-	if update.Calories != nil {
-		logEntry.Calories = *update.Calories
-	}
-	if update.Confidence != nil {
-		logEntry.Confidence = *update.Confidence
-	}
-	if update.Timestamp != nil {
-		logEntry.Timestamp = *update.Timestamp
+	// Apply updates to a copy so a failed validation leaves the stored log untouched
+	// (item edits recompute the total)
+	logEntry := s.logs[userID][logIndex]
+	if err := logEntry.ApplyUpdate(update); err != nil {
+		return err
 	}
 	logEntry.UpdatedAt = time.Now()
 
@@ -129,6 +173,7 @@ func (s *MemoryStorage) UpdateLog(userID int64, logID string, update *models.Log
 		return err
 	}
 
+	s.logs[userID][logIndex] = logEntry
 	return nil
 }
 
@@ -157,3 +202,29 @@ func (s *MemoryStorage) DeleteLog(userID int64, logID string) error {
 
 	return errors.New("log not found")
 }
+
+// GetProfile retrieves a user's profile, returning a default profile if none is saved
+func (s *MemoryStorage) GetProfile(userID int64) (*models.UserProfile, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	profile, exists := s.profiles[userID]
+	if !exists {
+		return &models.UserProfile{UserID: userID}, nil
+	}
+	return &profile, nil
+}
+
+// SaveProfile creates or replaces a user's profile
+func (s *MemoryStorage) SaveProfile(profile *models.UserProfile) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	profile.UpdatedAt = time.Now()
+	s.profiles[profile.UserID] = *profile
+	return nil
+}
@@ -0,0 +1,493 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+)
+
+// sqliteDriver is the go-sqlite3 driver with unicode_lower() registered on every connection
+// SQLite's built-in lower() only folds ASCII; unicode_lower uses strings.ToLower so searches
+// match the same food item names as LogQuery.Matches (e.g. "Борщ" for "борщ")
+const sqliteDriver = "sqlite3_unicode"
+
+func init() {
+	sql.Register(sqliteDriver, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("unicode_lower", strings.ToLower, true)
+		},
+	})
+}
+
+// migrations holds the ordered schema changes for the SQLite backend
+// Each entry is applied exactly once; append new entries, never edit existing ones
+var migrations = []string{
+	// 1: logs table
+	`CREATE TABLE logs (
+		id         TEXT PRIMARY KEY,
+		user_id    INTEGER NOT NULL,
+		food_items TEXT    NOT NULL,
+		calories   INTEGER NOT NULL,
+		confidence TEXT    NOT NULL,
+		timestamp  INTEGER NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	);
+	CREATE INDEX idx_logs_user_timestamp ON logs (user_id, timestamp DESC);`,
+
+	// 2: user profiles (daily goal, timezone)
+	`CREATE TABLE profiles (
+		user_id    INTEGER PRIMARY KEY,
+		daily_goal INTEGER NOT NULL DEFAULT 0,
+		timezone   TEXT    NOT NULL DEFAULT '',
+		updated_at INTEGER NOT NULL
+	);`,
+
+	// 3: macronutrient breakdown (JSON, NULL when unknown)
+	`ALTER TABLE logs ADD COLUMN macros TEXT;`,
+
+	// 4: estimator provider that produced the entry
+	`ALTER TABLE logs ADD COLUMN provider TEXT NOT NULL DEFAULT '';`,
+
+	// 5: estimation prompt template version
+	`ALTER TABLE logs ADD COLUMN prompt_version TEXT NOT NULL DEFAULT '';`,
+
+	// 6: user's caption/notes for the meal
+	`ALTER TABLE logs ADD COLUMN note TEXT NOT NULL DEFAULT '';`,
+
+	// 7: one-shot photo mode preference
+	`ALTER TABLE profiles ADD COLUMN one_shot INTEGER NOT NULL DEFAULT 0;`,
+
+	// 8: meal type per log (empty for entries saved before it existed) and per-user meal windows
+	`ALTER TABLE logs ADD COLUMN meal_type TEXT NOT NULL DEFAULT '';
+	ALTER TABLE profiles ADD COLUMN meal_windows TEXT NOT NULL DEFAULT '';`,
+
+	// 9: language, locale and unit preferences
+	`ALTER TABLE profiles ADD COLUMN language TEXT NOT NULL DEFAULT '';
+	ALTER TABLE profiles ADD COLUMN locale TEXT NOT NULL DEFAULT '';
+	ALTER TABLE profiles ADD COLUMN units TEXT NOT NULL DEFAULT '';`,
+
+	// 10: energy display unit (kcal or kJ)
+	`ALTER TABLE profiles ADD COLUMN energy TEXT NOT NULL DEFAULT '';`,
+
+	// 11: item calories became nullable; items stored with 0 meant "no estimate", so drop the key
+	// (CASE guards json_extract, which fails on legacy string items)
+	`UPDATE logs SET food_items = (
+		SELECT json_group_array(CASE
+			WHEN type != 'object' THEN value
+			WHEN json_extract(value, '$.calories') = 0 THEN json_remove(value, '$.calories')
+			ELSE json(value)
+		END)
+		FROM json_each(logs.food_items)
+	)
+	WHERE EXISTS (
+		SELECT 1 FROM json_each(logs.food_items)
+		WHERE CASE WHEN type = 'object' THEN json_extract(value, '$.calories') = 0 END
+	);`,
+
+	// 12: dietary notes passed to the estimation prompt
+	`ALTER TABLE profiles ADD COLUMN dietary_notes TEXT NOT NULL DEFAULT '';`,
+}
+
+// logColumns is the column list read by scanLog, in scan order
+const logColumns = "id, user_id, food_items, calories, macros, confidence, provider, prompt_version, note, meal_type, timestamp, created_at, updated_at"
+
+// SQLiteStorage implements LogStorage and ProfileStorage using an embedded SQLite database file
+// Data survives process restarts, unlike MemoryStorage
+type SQLiteStorage struct {
+	db *sql.DB
+}
+
+// NewSQLiteStorage opens (or creates) the SQLite database at path and runs pending migrations
+func NewSQLiteStorage(path string) (*SQLiteStorage, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+	}
+
+	db, err := sql.Open(sqliteDriver, path+"?_busy_timeout=5000&_journal_mode=WAL&_foreign_keys=on")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	// SQLite allows a single writer; serializing connections avoids "database is locked" errors
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to sqlite database: %w", err)
+	}
+
+	s := &SQLiteStorage{db: db}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Close releases the underlying database handle
+func (s *SQLiteStorage) Close() error {
+	return s.db.Close()
+}
+
+// migrate applies every migration newer than the recorded schema version
+func (s *SQLiteStorage) migrate() error {
+	if _, err := s.db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at INTEGER NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var current int
+	if err := s.db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for i := current; i < len(migrations); i++ {
+		version := i + 1
+
+		tx, err := s.db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %d: %w", version, err)
+		}
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d: %w", version, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, version, time.Now().Unix()); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %d: %w", version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", version, err)
+		}
+
+		log.Printf("[STORAGE] Applied SQLite migration %d", version)
+	}
+
+	return nil
+}
+
+// ListLogs retrieves all logs for a user, sorted by Timestamp descending
+func (s *SQLiteStorage) ListLogs(userID int64) ([]models.Log, error) {
+	rows, err := s.db.Query(`SELECT `+logColumns+`
+		FROM logs WHERE user_id = ? ORDER BY timestamp DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query logs: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.Log{}
+	for rows.Next() {
+		logEntry, err := scanLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *logEntry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate logs: %w", err)
+	}
+
+	log.Printf("[STORAGE] User %d has %d log(s)", userID, len(result))
+	return result, nil
+}
+
+// QueryLogs retrieves one page of a user's logs matching the query
+// Search folds case with unicode_lower and matches legacy string items as well as item names
+func (s *SQLiteStorage) QueryLogs(userID int64, query *models.LogQuery) (*models.LogPage, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	where := []string{"user_id = ?"}
+	args := []any{userID}
+
+	if query.From != nil {
+		where = append(where, "timestamp >= ?")
+		args = append(args, query.From.UnixNano())
+	}
+	if query.To != nil {
+		where = append(where, "timestamp < ?")
+		args = append(args, query.To.UnixNano())
+	}
+	if len(query.Confidence) > 0 {
+		placeholders := make([]string, len(query.Confidence))
+		for i, level := range query.Confidence {
+			placeholders[i] = "?"
+			args = append(args, string(level))
+		}
+		where = append(where, "confidence IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if query.Search != "" {
+		where = append(where, `EXISTS (SELECT 1 FROM json_each(logs.food_items)
+			WHERE instr(unicode_lower(coalesce(CASE type WHEN 'text' THEN value ELSE json_extract(value, '$.name') END, '')), unicode_lower(?)) > 0)`)
+		args = append(args, query.Search)
+	}
+	direction, past := "DESC", "<"
+	if query.Order == models.OrderAsc {
+		direction, past = "ASC", ">"
+	}
+	if query.Cursor != "" {
+		cursorTime, cursorID, _, _ := models.DecodeLogCursor(query.Cursor)
+		where = append(where, "(timestamp "+past+" ? OR (timestamp = ? AND id "+past+" ?))")
+		args = append(args, cursorTime.UnixNano(), cursorTime.UnixNano(), cursorID)
+	}
+
+	// Fetch one extra row to learn whether another page exists
+	args = append(args, query.Limit+1)
+	rows, err := s.db.Query(`SELECT `+logColumns+`
+		FROM logs WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY timestamp `+direction+`, id `+direction+` LIMIT ?`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query logs: %w", err)
+	}
+	defer rows.Close()
+
+	page := &models.LogPage{Logs: []models.Log{}}
+	for rows.Next() {
+		logEntry, err := scanLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		page.Logs = append(page.Logs, *logEntry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate logs: %w", err)
+	}
+
+	if len(page.Logs) > query.Limit {
+		page.Logs = page.Logs[:query.Limit]
+		page.NextCursor = models.EncodeLogCursor(&page.Logs[query.Limit-1], query.Order)
+	}
+
+	log.Printf("[STORAGE] User %d query returned %d log(s)", userID, len(page.Logs))
+	return page, nil
+}
+
+// CreateLog creates a new log entry
+func (s *SQLiteStorage) CreateLog(userID int64, logEntry *models.Log) error {
+	logEntry.SyncCalories()
+	if err := logEntry.Validate(); err != nil {
+		return err
+	}
+	inferMealType(s, userID, logEntry)
+
+	logEntry.ID = uuid.New().String()
+	logEntry.UserID = userID
+	now := time.Now()
+	logEntry.CreatedAt = now
+	logEntry.UpdatedAt = now
+
+	foodItems, macros, err := encodeLogColumns(logEntry)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`INSERT INTO logs (`+logColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		logEntry.ID, userID, foodItems, logEntry.Calories, macros, string(logEntry.Confidence), logEntry.Provider, logEntry.PromptVersion, logEntry.Note,
+		string(logEntry.MealType),
+		logEntry.Timestamp.UnixNano(), logEntry.CreatedAt.UnixNano(), logEntry.UpdatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to insert log: %w", err)
+	}
+
+	log.Printf("[STORAGE] Created log %s for user %d", logEntry.ID, userID)
+	return nil
+}
+
+// UpdateLog updates an existing log entry
+func (s *SQLiteStorage) UpdateLog(userID int64, logID string, update *models.LogUpdate) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	logEntry, err := scanLog(tx.QueryRow(`SELECT `+logColumns+`
+		FROM logs WHERE id = ?`, logID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("log not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	// Authorization check: verify log belongs to user
+	if logEntry.UserID != userID {
+		return errors.New("unauthorized: log does not belong to user")
+	}
+
+	// Apply updates (item edits recompute the total)
+	if err := logEntry.ApplyUpdate(update); err != nil {
+		return err
+	}
+	logEntry.UpdatedAt = time.Now()
+
+	// Validate after updates
+	if err := logEntry.Validate(); err != nil {
+		return err
+	}
+
+	foodItems, macros, err := encodeLogColumns(logEntry)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE logs SET food_items = ?, calories = ?, macros = ?, confidence = ?, note = ?, meal_type = ?, timestamp = ?, updated_at = ?
+		WHERE id = ?`,
+		foodItems, logEntry.Calories, macros, string(logEntry.Confidence), logEntry.Note, string(logEntry.MealType),
+		logEntry.Timestamp.UnixNano(), logEntry.UpdatedAt.UnixNano(), logID)
+	if err != nil {
+		return fmt.Errorf("failed to update log: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// DeleteLog deletes a log entry
+func (s *SQLiteStorage) DeleteLog(userID int64, logID string) error {
+	var ownerID int64
+	err := s.db.QueryRow(`SELECT user_id FROM logs WHERE id = ?`, logID).Scan(&ownerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("log not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to query log: %w", err)
+	}
+
+	// Authorization check: verify log belongs to user
+	if ownerID != userID {
+		return errors.New("unauthorized: log does not belong to user")
+	}
+
+	if _, err := s.db.Exec(`DELETE FROM logs WHERE id = ? AND user_id = ?`, logID, userID); err != nil {
+		return fmt.Errorf("failed to delete log: %w", err)
+	}
+
+	return nil
+}
+
+// GetProfile retrieves a user's profile, returning a default profile if none is saved
+func (s *SQLiteStorage) GetProfile(userID int64) (*models.UserProfile, error) {
+	profile := &models.UserProfile{UserID: userID}
+	var updatedAt int64
+
+	var units, energy string
+	err := s.db.QueryRow(`SELECT daily_goal, timezone, one_shot, meal_windows, language, locale, units, energy, dietary_notes, updated_at
+		FROM profiles WHERE user_id = ?`, userID).
+		Scan(&profile.DailyGoal, &profile.Timezone, &profile.OneShot, &profile.MealWindows, &profile.Language, &profile.Locale, &units, &energy,
+			&profile.DietaryNotes, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return profile, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query profile: %w", err)
+	}
+
+	profile.Units = models.UnitSystem(units)
+	profile.Energy = models.EnergyUnit(energy)
+	profile.UpdatedAt = time.Unix(0, updatedAt)
+	return profile, nil
+}
+
+// SaveProfile creates or replaces a user's profile
+func (s *SQLiteStorage) SaveProfile(profile *models.UserProfile) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+
+	profile.UpdatedAt = time.Now()
+	_, err := s.db.Exec(`INSERT INTO profiles (user_id, daily_goal, timezone, one_shot, meal_windows, language, locale, units, energy, dietary_notes, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET daily_goal = excluded.daily_goal, timezone = excluded.timezone,
+			one_shot = excluded.one_shot, meal_windows = excluded.meal_windows, language = excluded.language,
+			locale = excluded.locale, units = excluded.units, energy = excluded.energy,
+			dietary_notes = excluded.dietary_notes, updated_at = excluded.updated_at`,
+		profile.UserID, profile.DailyGoal, profile.Timezone, profile.OneShot, profile.MealWindows,
+		profile.Language, profile.Locale, string(profile.Units), string(profile.Energy), profile.DietaryNotes, profile.UpdatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to save profile: %w", err)
+	}
+
+	return nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanLog decodes a single logs row into a models.Log
+func scanLog(row rowScanner) (*models.Log, error) {
+	var (
+		logEntry                        models.Log
+		foodItems, confidence, provider string
+		promptVersion, note, mealType   string
+		macros                          sql.NullString
+		timestamp, createdAt, updatedAt int64
+	)
+
+	if err := row.Scan(&logEntry.ID, &logEntry.UserID, &foodItems, &logEntry.Calories, &macros, &confidence, &provider, &promptVersion, &note, &mealType,
+		&timestamp, &createdAt, &updatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan log: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(foodItems), &logEntry.FoodItems); err != nil {
+		return nil, fmt.Errorf("failed to decode food items for log %s: %w", logEntry.ID, err)
+	}
+	if macros.Valid {
+		logEntry.Macros = &models.Macros{}
+		if err := json.Unmarshal([]byte(macros.String), logEntry.Macros); err != nil {
+			return nil, fmt.Errorf("failed to decode macros for log %s: %w", logEntry.ID, err)
+		}
+	}
+
+	logEntry.Confidence = models.ConfidenceLevel(confidence)
+	logEntry.Provider = provider
+	logEntry.PromptVersion = promptVersion
+	logEntry.Note = note
+	logEntry.MealType = models.MealType(mealType)
+	logEntry.Timestamp = time.Unix(0, timestamp)
+	logEntry.CreatedAt = time.Unix(0, createdAt)
+	logEntry.UpdatedAt = time.Unix(0, updatedAt)
+
+	return &logEntry, nil
+}
+
+// encodeLogColumns serializes the JSON-encoded columns of a log row
+// macros is nil (SQL NULL) when the log has no macronutrient breakdown
+func encodeLogColumns(logEntry *models.Log) (foodItems string, macros any, err error) {
+	items, err := json.Marshal(logEntry.FoodItems)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode food items: %w", err)
+	}
+
+	if logEntry.Macros != nil {
+		encoded, err := json.Marshal(logEntry.Macros)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to encode macros: %w", err)
+		}
+		macros = string(encoded)
+	}
+
+	return string(items), macros, nil
+}
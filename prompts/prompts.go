@@ -0,0 +1,17 @@
+// Package prompts embeds the versioned LLM prompt templates
+//
+// Layout:
+//
+//	estimation/<version>.tmpl  calorie estimation prompts ("image", "text", "repair")
+//	judge/<version>.tmpl       cmd/tester LLM judge prompt
+//
+// Files are text/template sources; add a new version as a new file rather than editing a
+// released one so logs stamped with a version keep meaning the same prompt
+package prompts
+
+import "embed"
+
+// FS holds every prompt template shipped with the binary
+//
+//go:embed estimation/*.tmpl judge/*.tmpl
+var FS embed.FS
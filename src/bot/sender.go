@@ -10,6 +10,12 @@ type Sender interface {
 	// Send sends a message to the recipient
 	Send(to tele.Recipient, what interface{}, opts ...interface{}) (*tele.Message, error)
 
+	// Edit replaces the text (and optionally the inline keyboard) of a sent message
+	Edit(msg tele.Editable, what interface{}, opts ...interface{}) (*tele.Message, error)
+
+	// EditReplyMarkup replaces a sent message's inline keyboard (nil removes it)
+	EditReplyMarkup(msg tele.Editable, markup *tele.ReplyMarkup) (*tele.Message, error)
+
 	// Delete removes a message
 	Delete(msg tele.Editable) error
 
@@ -37,6 +43,14 @@ func (s *TelebotSender) Send(to tele.Recipient, what interface{}, opts ...interf
 	return s.bot.Send(to, what, opts...)
 }
 
+func (s *TelebotSender) Edit(msg tele.Editable, what interface{}, opts ...interface{}) (*tele.Message, error) {
+	return s.bot.Edit(msg, what, opts...)
+}
+
+func (s *TelebotSender) EditReplyMarkup(msg tele.Editable, markup *tele.ReplyMarkup) (*tele.Message, error) {
+	return s.bot.EditReplyMarkup(msg, markup)
+}
+
 func (s *TelebotSender) Delete(msg tele.Editable) error {
 	return s.bot.Delete(msg)
 }
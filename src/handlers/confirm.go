@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/src/i18n"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	telebot "gopkg.in/telebot.v3"
+)
+
+// maxManualCalories bounds a manual kcal entry for one meal
+const maxManualCalories = 10000
+
+// portionMultipliers are the Adjust shortcuts, keyed by callback data
+var portionMultipliers = map[string]float64{
+	"scale_0.5": 0.5,
+	"scale_1.5": 1.5,
+	"scale_2":   2,
+}
+
+// AutoSaveDelayFromEnv reads ESTIMATE_AUTOSAVE_AFTER (Go duration, e.g. 10m)
+// Returns 0 (estimates are only saved when the user taps Save) when unset or "0"
+func AutoSaveDelayFromEnv() (time.Duration, error) {
+	raw := os.Getenv("ESTIMATE_AUTOSAVE_AFTER")
+	if raw == "" {
+		return 0, nil
+	}
+	delay, err := time.ParseDuration(raw)
+	if err != nil || delay < 0 {
+		return 0, fmt.Errorf("invalid ESTIMATE_AUTOSAVE_AFTER %q: must be a duration like 10m (0 disables)", raw)
+	}
+	return delay, nil
+}
+
+// SetAutoSave saves estimates the user has not confirmed or discarded after delay (0 disables)
+func (h *EstimateHandler) SetAutoSave(delay time.Duration) {
+	h.autoSave = delay
+}
+
+// sendPending shows an estimate awaiting confirmation with Save / Adjust / Discard buttons
+// and the meal type buttons, editing the session's current result message in place when there is one
+func (h *EstimateHandler) sendPending(to telebot.Recipient, p *i18n.Printer, userID int64, pending *models.PendingEstimate) error {
+	text := models.FormatResult(p, pending.Result) + "\n\n" + models.FormatMealType(p, pending.MealType) + "\n\n" + p.T("confirm.question")
+	if h.autoSave > 0 {
+		text += autoSaveNotice(p, h.autoSave)
+	}
+
+	// Buttons acting on the estimate carry its ID so taps on an older result are rejected
+	id := strconv.Itoa(pending.ID)
+	markup := &telebot.ReplyMarkup{}
+	btnSave := markup.Data(p.T("button.save"), "save", id)
+	btnAdjust := markup.Data(p.T("button.adjust"), "adjust", id)
+	btnDiscard := markup.Data(p.T("button.discard"), "discard", id)
+	btnReEstimate := markup.Data(p.T("button.re_estimate"), "re_estimate", id)
+	btnCancel := markup.Data(p.T("button.cancel"), "cancel")
+
+	mealButtons := make([]telebot.Btn, len(internalmodels.MealTypes))
+	for i, mealType := range internalmodels.MealTypes {
+		label := models.FormatMealLabel(p, mealType)
+		if mealType == pending.MealType {
+			label = "✓ " + label
+		}
+		mealButtons[i] = markup.Data(label, mealCallbackPrefix+string(mealType), id)
+	}
+
+	markup.Inline(
+		markup.Row(btnSave, btnAdjust, btnDiscard),
+		markup.Row(mealButtons...),
+		markup.Row(btnReEstimate, btnCancel),
+	)
+
+	msgID, err := h.showMessageID(to, userID, text, markup)
+	if err != nil {
+		return err
+	}
+	h.sessionManager.SetPendingMessage(userID, pending.ID, msgID)
+	return nil
+}
+
+// ParseCallbackData splits inline button data into the action and the ID of the pending
+// estimate the button was offered for, e.g. "\fsave|12" → ("save", 12)
+// The ID is 0 for buttons that carry none
+func ParseCallbackData(data string) (action string, pendingID int) {
+	action, payload, _ := strings.Cut(strings.TrimSpace(data), "|")
+	pendingID, _ = strconv.Atoi(payload)
+	return action, pendingID
+}
+
+// tappedPendingID returns the pending estimate ID carried by the tapped button (0 if none)
+func tappedPendingID(c telebot.Context) int {
+	_, id := ParseCallbackData(c.Callback().Data)
+	return id
+}
+
+// answerNoPending answers a tap that matched no pending estimate with the key's text, or
+// rejects it as stale when the button was offered for a specific estimate
+func (h *EstimateHandler) answerNoPending(c telebot.Context, p *i18n.Printer, pendingID int, key string) error {
+	if pendingID == 0 {
+		return c.Respond(&telebot.CallbackResponse{Text: p.T(key)})
+	}
+	return h.rejectStaleTap(c, p)
+}
+
+// rejectStaleTap answers a tap on the buttons of an estimate that was since saved, discarded
+// or replaced, and removes those buttons
+func (h *EstimateHandler) rejectStaleTap(c telebot.Context, p *i18n.Printer) error {
+	if tapped := tappedMessage(c); tapped != nil {
+		if _, err := h.sender.EditReplyMarkup(tapped, nil); err != nil {
+			log.Printf("[HANDLER ERROR] Failed to remove stale buttons from message %d for user %d: %v", tapped.ID, c.Sender().ID, err)
+		}
+	}
+	return c.Respond(&telebot.CallbackResponse{Text: p.T("callback.outdated")})
+}
+
+// autoSaveNotice tells the user when an unconfirmed estimate will be saved, in whole minutes when possible
+func autoSaveNotice(p *i18n.Printer, delay time.Duration) string {
+	if delay >= time.Minute && delay%time.Minute == 0 {
+		minutes := int(delay / time.Minute)
+		return p.N("confirm.autosave_minutes", minutes, minutes)
+	}
+	seconds := int(math.Ceil(delay.Seconds()))
+	return p.N("confirm.autosave_seconds", seconds, seconds)
+}
+
+// HandleSave handles the Save button: the pending estimate is written to the log
+func (h *EstimateHandler) HandleSave(c telebot.Context) error {
+	userID := c.Sender().ID
+	log.Printf("[HANDLER] HandleSave called for user %d", userID)
+	p := h.printer(c)
+
+	pendingID := tappedPendingID(c)
+	pending := h.sessionManager.TakePending(userID, pendingID)
+	if pending == nil {
+		return h.answerNoPending(c, p, pendingID, "callback.nothing_save")
+	}
+	if err := c.Respond(&telebot.CallbackResponse{Text: p.T("callback.saved")}); err != nil {
+		log.Printf("[HANDLER ERROR] Failed to respond to save callback for user %d: %v", userID, err)
+	}
+	h.leaveCaloriesEntry(userID, pending)
+	h.clearButtons(userID, tappedMessage(c), pending)
+
+	return h.savePending(c.Sender(), p, userID, pending, p.T("confirm.saved"))
+}
+
+// HandleDiscard handles the Discard button: the pending estimate is dropped without saving
+func (h *EstimateHandler) HandleDiscard(c telebot.Context) error {
+	userID := c.Sender().ID
+	log.Printf("[HANDLER] HandleDiscard called for user %d", userID)
+	p := h.printer(c)
+
+	pendingID := tappedPendingID(c)
+	pending := h.sessionManager.TakePending(userID, pendingID)
+	if pending == nil {
+		return h.answerNoPending(c, p, pendingID, "callback.nothing_discard")
+	}
+	if err := c.Respond(&telebot.CallbackResponse{Text: p.T("callback.discarded")}); err != nil {
+		log.Printf("[HANDLER ERROR] Failed to respond to discard callback for user %d: %v", userID, err)
+	}
+	h.leaveCaloriesEntry(userID, pending)
+	h.clearButtons(userID, tappedMessage(c), pending)
+
+	_, err := h.sender.Send(c.Sender(), p.T("confirm.discarded"))
+	return err
+}
+
+// HandleAdjust handles the Adjust button: offers portion multipliers and a manual kcal entry
+func (h *EstimateHandler) HandleAdjust(c telebot.Context) error {
+	userID := c.Sender().ID
+	p := h.printer(c)
+
+	pendingID := tappedPendingID(c)
+	if !h.sessionManager.HasPending(userID, pendingID) {
+		return h.answerNoPending(c, p, pendingID, "callback.nothing_adjust")
+	}
+	if err := c.Respond(&telebot.CallbackResponse{}); err != nil {
+		log.Printf("[HANDLER ERROR] Failed to respond to adjust callback for user %d: %v", userID, err)
+	}
+
+	id := strconv.Itoa(pendingID)
+	markup := &telebot.ReplyMarkup{}
+	btnHalf := markup.Data("×"+p.Decimal(0.5, -1), "scale_0.5", id)
+	btnOneAndHalf := markup.Data("×"+p.Decimal(1.5, -1), "scale_1.5", id)
+	btnDouble := markup.Data("×2", "scale_2", id)
+	btnManual := markup.Data(p.T("button.manual_kcal"), "manual_kcal", id)
+	markup.Inline(
+		markup.Row(btnHalf, btnOneAndHalf, btnDouble),
+		markup.Row(btnManual),
+	)
+
+	msg, err := h.sender.Send(c.Sender(), p.T("adjust.prompt"), markup)
+	if err != nil {
+		return err
+	}
+	// The menu's buttons are removed together with the result's once the estimate is settled
+	h.sessionManager.AddPendingMenu(userID, pendingID, msg.ID)
+	return nil
+}
+
+// HandleScale handles a portion multiplier button (×0.5, ×1.5, ×2)
+// Multipliers apply to the original estimate, so tapping ×2 twice stays ×2
+func (h *EstimateHandler) HandleScale(c telebot.Context) error {
+	userID := c.Sender().ID
+	p := h.printer(c)
+
+	action, pendingID := ParseCallbackData(c.Callback().Data)
+	factor, ok := portionMultipliers[action]
+	if !ok {
+		return c.Respond(&telebot.CallbackResponse{Text: p.T("callback.unknown_adjust")})
+	}
+
+	pending := h.sessionManager.AdjustPending(userID, pendingID, func(original *models.EstimateResult) *models.EstimateResult {
+		return original.Scaled(factor)
+	})
+	if pending == nil {
+		return h.answerNoPending(c, p, pendingID, "callback.nothing_adjust")
+	}
+	if err := c.Respond(&telebot.CallbackResponse{Text: p.T("callback.portion", p.Decimal(factor, -1))}); err != nil {
+		log.Printf("[HANDLER ERROR] Failed to respond to scale callback for user %d: %v", userID, err)
+	}
+	h.leaveCaloriesEntry(userID, pending)
+	log.Printf("[HANDLER] User %d scaled estimate ×%g to %d kcal", userID, factor, pending.Result.Calories)
+
+	return h.sendPending(c.Sender(), p, userID, pending)
+}
+
+// HandleManualCalories handles the Enter kcal button → State: AwaitingCalories
+func (h *EstimateHandler) HandleManualCalories(c telebot.Context) error {
+	userID := c.Sender().ID
+	p := h.printer(c)
+
+	pendingID := tappedPendingID(c)
+	if !h.sessionManager.HasPending(userID, pendingID) {
+		return h.answerNoPending(c, p, pendingID, "callback.nothing_adjust")
+	}
+	if err := c.Respond(&telebot.CallbackResponse{}); err != nil {
+		log.Printf("[HANDLER ERROR] Failed to respond to manual_kcal callback for user %d: %v", userID, err)
+	}
+
+	h.sessionManager.UpdateSession(userID, models.StateAwaitingCalories)
+	_, err := h.sender.Send(c.Sender(), p.T("adjust.manual_prompt"))
+	return err
+}
+
+// processManualCalories applies a kcal total typed while AwaitingCalories to the pending estimate
+func (h *EstimateHandler) processManualCalories(c telebot.Context, text string) error {
+	userID := c.Sender().ID
+	p := h.printer(c)
+
+	calories, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(strings.ToLower(text), "kcal")))
+	if err != nil || calories <= 0 || calories > maxManualCalories {
+		return h.sendError(c, p.T("adjust.manual_range", p.Number(maxManualCalories)))
+	}
+
+	pending := h.sessionManager.AdjustPending(userID, 0, func(original *models.EstimateResult) *models.EstimateResult {
+		return original.ScaledTo(calories)
+	})
+	if pending == nil {
+		h.sessionManager.UpdateSession(userID, models.StateIdle)
+		return h.sendError(c, p.T("adjust.expired"))
+	}
+	h.leaveCaloriesEntry(userID, pending)
+	log.Printf("[HANDLER] User %d set estimate to %d kcal", userID, calories)
+
+	// The old result is above the user's reply; retire it and post the adjusted one below
+	h.clearButtons(userID, nil, nil)
+	return h.sendPending(c.Sender(), p, userID, pending)
+}
+
+// leaveCaloriesEntry restores the flow state if the user was asked for a manual kcal total
+func (h *EstimateHandler) leaveCaloriesEntry(userID int64, pending *models.PendingEstimate) {
+	h.sessionManager.TransitionState(userID, models.StateAwaitingCalories, pending.NextState)
+}
+
+// scheduleAutoSave saves the pending estimate after the auto-save delay unless
+// the user saved, discarded or replaced it in the meantime
+func (h *EstimateHandler) scheduleAutoSave(to telebot.Recipient, p *i18n.Printer, userID int64, pendingID int) {
+	time.AfterFunc(h.autoSave, func() {
+		pending := h.sessionManager.TakePending(userID, pendingID)
+		if pending == nil {
+			return
+		}
+		h.leaveCaloriesEntry(userID, pending)
+		h.clearButtons(userID, nil, pending)
+		log.Printf("[HANDLER] Auto-saving estimate for user %d", userID)
+		if err := h.savePending(to, p, userID, pending, p.T("confirm.autosaved")); err != nil {
+			log.Printf("[HANDLER ERROR] Auto-save reply failed for user %d: %v", userID, err)
+		}
+	})
+}
+
+// savePending writes a confirmed estimate to the log and replies with today's goal progress
+func (h *EstimateHandler) savePending(to telebot.Recipient, p *i18n.Printer, userID int64, pending *models.PendingEstimate, title string) error {
+	result := pending.Result
+	logEntry := &internalmodels.Log{
+		FoodItems:     models.LogItems(result.FoodItems),
+		Calories:      result.Calories,
+		Macros:        toLogMacros(result.Macros),
+		Confidence:    internalmodels.ConfidenceLevel(result.Confidence),
+		Provider:      result.Provider,
+		PromptVersion: result.PromptVersion,
+		Note:          pending.Note,
+		MealType:      pending.MealType,
+		Timestamp:     pending.DeliveredAt,
+	}
+
+	if err := h.storage.CreateLog(userID, logEntry); err != nil {
+		log.Printf("[HANDLER ERROR] Failed to save log entry for user %d: %v", userID, err)
+		_, sendErr := h.sender.Send(to, "❌ "+p.T("confirm.save_failed"))
+		return sendErr
+	}
+	log.Printf("[HANDLER] ✓ Log entry saved for user %d: %d kcal, %d items", userID, result.Calories, len(result.FoodItems))
+
+	reply := p.T("confirm.saved_calories", title, p.Energy(logEntry.Calories))
+	if progress := h.goalProgress(p, userID); progress != "" {
+		reply += "\n\n" + progress
+	}
+	_, err := h.sender.Send(to, reply)
+	return err
+}
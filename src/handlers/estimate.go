@@ -3,38 +3,64 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
 	"github.com/freezind/telegram-calories-bot/src/bot"
+	"github.com/freezind/telegram-calories-bot/src/i18n"
 	"github.com/freezind/telegram-calories-bot/src/models"
 	"github.com/freezind/telegram-calories-bot/src/services"
 	telebot "gopkg.in/telebot.v3"
 )
 
+// DefaultEstimateTimeout bounds one estimate in total, across retries and fallback providers,
+// so a user is never left in Processing waiting for a result that will not come
+const DefaultEstimateTimeout = 2 * time.Minute
+
 // EstimateHandler handles bot commands and interactions
 // Handles /start, /estimate, image uploads, and inline buttons
 type EstimateHandler struct {
 	sender         bot.Sender
 	sessionManager *services.SessionManager
 	estimator      services.Estimator
-	storage        LogStorage // Interface for log persistence (shared with miniapp)
+	storage        LogStorage     // Interface for log persistence (shared with miniapp)
+	profiles       ProfileStorage // Interface for user profiles (daily goal, timezone)
+	images         *services.ImagePreprocessor
+	autoSave       time.Duration // Save pending estimates after this delay (0 = only on Save)
+
+	// estimateTimeout bounds the total time of one estimate (0 = DefaultEstimateTimeout)
+	estimateTimeout time.Duration
+
+	// languageChecked records users whose Telegram language_code was already applied (DetectLanguage)
+	languageChecked sync.Map // map[int64]bool
 }
 
 // LogStorage defines the interface for storing calorie logs
 // This matches internal/storage/interface.go
 type LogStorage interface {
+	ListLogs(userID int64) ([]internalmodels.Log, error)
 	CreateLog(userID int64, log *internalmodels.Log) error
 }
 
+// ProfileStorage defines the interface for reading and saving user profiles
+// This matches internal/storage/interface.go
+type ProfileStorage interface {
+	GetProfile(userID int64) (*internalmodels.UserProfile, error)
+	SaveProfile(profile *internalmodels.UserProfile) error
+}
+
 // HandleStart handles the /start command (T086)
 // Sends welcome message with bot introduction and usage instructions
 func (h *EstimateHandler) HandleStart(c telebot.Context) error {
-	welcomeMsg := models.FormatWelcomeMessage()
+	welcomeMsg := models.FormatWelcomeMessage(h.printer(c))
 	_, err := h.sender.Send(c.Sender(), welcomeMsg)
 	if err != nil {
 		log.Printf("[HANDLER ERROR] Failed to send welcome message: %v", err)
@@ -46,12 +72,19 @@ func (h *EstimateHandler) HandleStart(c telebot.Context) error {
 }
 
 // NewEstimateHandler creates a new EstimateHandler instance
-func NewEstimateHandler(sender bot.Sender, sm *services.SessionManager, estimator services.Estimator, storage LogStorage) *EstimateHandler {
+// storage and profiles may be nil when running without shared persistence
+// images may be nil to preprocess uploads with default limits
+func NewEstimateHandler(sender bot.Sender, sm *services.SessionManager, estimator services.Estimator, storage LogStorage, profiles ProfileStorage, images *services.ImagePreprocessor) *EstimateHandler {
+	if images == nil {
+		images = services.NewImagePreprocessor(services.ImageOptions{})
+	}
 	return &EstimateHandler{
 		sender:         sender,
 		sessionManager: sm,
 		estimator:      estimator,
 		storage:        storage,
+		profiles:       profiles,
+		images:         images,
 	}
 }
 
@@ -59,18 +92,19 @@ func NewEstimateHandler(sender bot.Sender, sm *services.SessionManager, estimato
 // Flow: User sends /estimate → Bot prompts for image → State: AwaitingImage
 func (h *EstimateHandler) HandleEstimate(c telebot.Context) error {
 	userID := c.Sender().ID
+	p := h.printer(c)
 
 	// Update session state to AwaitingImage
 	h.sessionManager.UpdateSession(userID, models.StateAwaitingImage)
 
 	// Send prompt message with Cancel button (FR-002, FR-007)
 	markup := &telebot.ReplyMarkup{}
-	btnCancel := markup.Data("Cancel", "cancel")
+	btnCancel := markup.Data(p.T("button.cancel"), "cancel")
 	markup.Inline(
 		markup.Row(btnCancel),
 	)
 
-	msg, err := h.sender.Send(c.Sender(), "📸 Please send a food image for calorie estimation", markup)
+	msg, err := h.sender.Send(c.Sender(), p.T("estimate.prompt"), markup)
 	if err != nil {
 		return fmt.Errorf("failed to send prompt: %w", err)
 	}
@@ -86,83 +120,193 @@ func (h *EstimateHandler) HandleEstimate(c telebot.Context) error {
 func (h *EstimateHandler) HandleDocument(c telebot.Context) error {
 	userID := c.Sender().ID
 
-	// Check session state - only process if AwaitingImage
-	session := h.sessionManager.GetSession(userID)
-	if session.State != models.StateAwaitingImage {
-		return nil
-	}
-
 	// Check if document is an image
 	doc := c.Message().Document
 	if doc == nil {
 		return nil
 	}
 
+	// Outside the photo flow, only image documents get a reply; other files are not ours to handle
+	if !h.acceptsImages(userID) && !isValidImageFormat(doc.MIME) {
+		return nil
+	}
+
 	// Validate image format (T026 - FR-003)
 	if !isValidImageFormat(doc.MIME) {
-		return h.sendError(c, "Unsupported format. Please send JPEG, PNG, or WebP images only.")
+		return h.sendError(c, h.printer(c).T("image.unsupported"))
+	}
+
+	// Reject oversized files before downloading them
+	if doc.FileSize > h.images.MaxBytes() {
+		return h.sendError(c, imageTooLargeMessage(h.printer(c), h.images.MaxBytes()))
 	}
 
-	// Process the document as an image
-	return h.processImage(c, doc.FileID, doc.MIME)
+	return h.routeImage(c, doc.FileID)
 }
 
 // HandlePhoto handles photo uploads (T025)
 // Photos are always JPEG in Telegram (compressed)
 func (h *EstimateHandler) HandlePhoto(c telebot.Context) error {
+	photo := c.Message().Photo
+	if photo == nil {
+		return nil
+	}
+
+	return h.routeImage(c, photo.FileID)
+}
+
+// routeImage sends an incoming photo or image document to the right flow:
+// added to the meal being collected, estimated right away in one-shot mode,
+// or answered with a pointer to /estimate instead of being silently ignored
+func (h *EstimateHandler) routeImage(c telebot.Context, fileID string) error {
 	userID := c.Sender().ID
+	albumID := c.Message().AlbumID
+
+	state := h.sessionManager.GetSession(userID).State
+	switch state {
+	case models.StateCollectingImages:
+		return h.collectImage(c, fileID, false)
+	case models.StateProcessing:
+		return h.sendStillProcessing(c, albumID)
+	}
 
-	// Check session state - only process if AwaitingImage
-	session := h.sessionManager.GetSession(userID)
-	if session.State != models.StateAwaitingImage {
-		return nil
+	oneShot := h.oneShotEnabled(userID)
+	switch {
+	case oneShot && albumID == "":
+		return h.estimateOneShot(c, fileID)
+	case oneShot || state == models.StateAwaitingImage:
+		// Album photos arrive one message each, so they are always collected until Done
+		return h.collectImage(c, fileID, oneShot)
+	default:
+		return h.sendEstimateHint(c, albumID)
 	}
+}
 
-	photo := c.Message().Photo
-	if photo == nil {
+// sendStillProcessing answers a photo sent while the previous meal is still being estimated
+// The photo is not kept, so the user is asked to send it again; an album gets one reply
+func (h *EstimateHandler) sendStillProcessing(c telebot.Context, albumID string) error {
+	userID := c.Sender().ID
+
+	if albumID != "" && h.sessionManager.SeenAlbum(userID, albumID) {
 		return nil
 	}
+	log.Printf("[HANDLER] Photo from user %d while the previous meal is being estimated", userID)
 
-	// Process as JPEG (Telegram default)
-	return h.processImage(c, photo.FileID, "image/jpeg")
+	_, err := h.sender.Send(c.Sender(), h.printer(c).T("photo.busy"))
+	return err
 }
 
-// processImage handles the common image processing logic for both photos and documents
-func (h *EstimateHandler) processImage(c telebot.Context, fileID, mimeType string) error {
+// acceptsImages reports whether the user's session is waiting for or collecting meal photos
+func (h *EstimateHandler) acceptsImages(userID int64) bool {
+	state := h.sessionManager.GetSession(userID).State
+	return state == models.StateAwaitingImage || state == models.StateCollectingImages
+}
+
+// estimateContext carries the user's prompt settings (version assignment, vars) to the estimator
+// and bounds the estimate by the handler's total timeout; the caller must call cancel
+// Item names are requested in the reply language unless it is English; imperial users, the
+// profile locale (regional dishes and portions) and the dietary notes are named too
+func (h *EstimateHandler) estimateContext(userID int64, p *i18n.Printer) (context.Context, context.CancelFunc) {
+	profile := h.userProfile(userID)
+	req := services.PromptRequest{UserID: userID}
+	req.Vars.Locale = profile.Locale
+	req.Vars.DietaryNotes = profile.DietaryNotes
+	if p.Language() != i18n.DefaultLanguage {
+		req.Vars.Language = p.LanguageName()
+	}
+	if p.Units().System == internalmodels.UnitsImperial {
+		req.Vars.Units = string(internalmodels.UnitsImperial)
+	}
+
+	timeout := h.estimateTimeout
+	if timeout <= 0 {
+		timeout = DefaultEstimateTimeout
+	}
+	return context.WithTimeout(services.WithPromptRequest(context.Background(), req), timeout)
+}
 
+// SetEstimateTimeout bounds the total time of one estimate (0 restores DefaultEstimateTimeout)
+func (h *EstimateHandler) SetEstimateTimeout(timeout time.Duration) {
+	h.estimateTimeout = timeout
+}
+
+// collectImage downloads one photo or image document and adds it to the user's meal
+// The meal is estimated once the user taps Done (see HandleDone); startMeal lets a one-shot
+// album start a meal from any state, otherwise the session must still be waiting for photos
+func (h *EstimateHandler) collectImage(c telebot.Context, fileID string, startMeal bool) error {
 	userID := c.Sender().ID
-	ctx := context.Background()
+	p := h.printer(c)
 
-	// Update state to Processing
-	h.sessionManager.UpdateSession(userID, models.StateProcessing)
+	image, err := h.downloadImage(p, userID, fileID)
+	if err != nil {
+		// Keep the session (and any photos collected so far) so the user can send another image
+		return h.sendError(c, err.Error())
+	}
+
+	albumID := c.Message().AlbumID
+	count, result, announce := h.sessionManager.AddImage(userID, image, albumID, startMeal)
+	switch result {
+	case services.ImageLimitReached:
+		return h.sendError(c, p.N("photo.limit", models.MaxMealImages, models.MaxMealImages))
+	case services.ImageNotCollecting:
+		// Done or Cancel was tapped while the photo was downloading
+		log.Printf("[HANDLER] Dropped photo for user %d: session no longer collecting", userID)
+		return nil
+	}
+	log.Printf("[HANDLER] Collected photo %d for user %d (album %q)", count, userID, albumID)
+
+	// Captions like "half portion, no sauce" are passed to the estimator as hints
+	if caption := strings.TrimSpace(c.Message().Caption); caption != "" {
+		h.sessionManager.AddHint(userID, caption)
+	}
+
+	// Album photos arrive as separate messages; acknowledge the album once
+	if !announce {
+		return nil
+	}
+
+	text := p.T("photo.added", count)
+	if albumID != "" {
+		text = p.T("photo.album")
+	}
+
+	markup := &telebot.ReplyMarkup{}
+	btnDone := markup.Data(p.T("button.done"), "done")
+	btnCancel := markup.Data(p.T("button.cancel"), "cancel")
+	markup.Inline(
+		markup.Row(btnDone, btnCancel),
+	)
 
-	// Send processing message
-	processingMsg, err := h.sender.Send(c.Sender(), "⏳ Analyzing your image...")
+	msg, err := h.sender.Send(c.Sender(), text, markup)
 	if err != nil {
-		log.Printf("Failed to send processing message: %v", err)
+		return fmt.Errorf("failed to send photo acknowledgement: %w", err)
 	}
+	h.sessionManager.SetMessageID(userID, msg.ID)
 
-	// Download image from Telegram
+	return nil
+}
+
+// downloadImage fetches a Telegram file and runs it through the image preprocessor
+// Returned errors are user-facing (in the printer's language); details are logged
+func (h *EstimateHandler) downloadImage(p *i18n.Printer, userID int64, fileID string) (models.ImageInput, error) {
 	file, err := h.sender.FileByID(fileID)
 	if err != nil {
-		h.sessionManager.UpdateSession(userID, models.StateIdle)
-		return h.sendError(c, "Failed to download image. Please try again.")
+		log.Printf("[HANDLER ERROR] Failed to get file %s for user %d: %v", fileID, userID, err)
+		return models.ImageInput{}, errors.New(p.T("image.download_failed"))
 	}
 
 	// Fetch file content
 	fileURL := h.sender.GetFileURL(file)
-	log.Printf("fileURL: %s", fileURL)
 	// #nosec G107 - URL is constructed from trusted Telegram Bot API response
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, fileURL, nil)
 	if err != nil {
-		h.sessionManager.UpdateSession(userID, models.StateIdle)
-		return h.sendError(c, "Failed to create request. Please try again.")
+		return models.ImageInput{}, errors.New(p.T("image.request_failed"))
 	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		h.sessionManager.UpdateSession(userID, models.StateIdle)
-		return h.sendError(c, "Failed to fetch image. Please try again.")
+		log.Printf("[HANDLER ERROR] Failed to fetch image for user %d: %v", userID, err)
+		return models.ImageInput{}, errors.New(p.T("image.fetch_failed"))
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -170,81 +314,209 @@ func (h *EstimateHandler) processImage(c telebot.Context, fileID, mimeType strin
 		}
 	}()
 
-	imageBytes, err := io.ReadAll(resp.Body)
+	// Read one byte past the limit so oversized downloads are detected without buffering them whole
+	imageBytes, err := io.ReadAll(io.LimitReader(resp.Body, h.images.MaxBytes()+1))
 	if err != nil {
-		h.sessionManager.UpdateSession(userID, models.StateIdle)
-		return h.sendError(c, "Failed to read image. Please try again.")
+		return models.ImageInput{}, errors.New(p.T("image.read_failed"))
 	}
 
+	// Decode, strip metadata, auto-orient and downsize before any API call
+	processed, mimeType, err := h.images.Process(imageBytes)
+	if err != nil {
+		log.Printf("[HANDLER] Rejected image from user %d: %v", userID, err)
+		if errors.Is(err, services.ErrImageTooLarge) {
+			return models.ImageInput{}, errors.New(imageTooLargeMessage(p, h.images.MaxBytes()))
+		}
+		return models.ImageInput{}, errors.New(p.T("image.invalid"))
+	}
+
+	return models.ImageInput{Data: processed, MimeType: mimeType}, nil
+}
+
+// HandleDone handles the Done button: all collected photos are estimated together as one meal
+func (h *EstimateHandler) HandleDone(c telebot.Context) error {
+	userID := c.Sender().ID
+	log.Printf("[HANDLER] HandleDone called for user %d", userID)
+	p := h.printer(c)
+
+	images, hints := h.sessionManager.TakeMeal(userID)
+	if len(images) == 0 {
+		return c.Respond(&telebot.CallbackResponse{Text: p.T("callback.photo_first")})
+	}
+
+	if err := c.Respond(&telebot.CallbackResponse{Text: p.T("callback.estimating")}); err != nil {
+		log.Printf("[HANDLER ERROR] Failed to respond to done callback for user %d: %v", userID, err)
+	}
+	h.clearButtons(userID, tappedMessage(c), nil)
+
+	return h.estimateImages(c, images, mealNote(hints))
+}
+
+// mealNote joins the user's captions and notes into one hint string, bounded to the log note limit
+func mealNote(hints []string) string {
+	note := []rune(strings.Join(hints, "\n"))
+	if len(note) > internalmodels.MaxNoteLength {
+		note = note[:internalmodels.MaxNoteLength]
+	}
+	return string(note)
+}
+
+// estimateImages sends the meal's photos and the user's note to the estimator in one request and delivers the result
+func (h *EstimateHandler) estimateImages(c telebot.Context, images []models.ImageInput, note string) error {
+	userID := c.Sender().ID
+	p := h.printer(c)
+
+	// Update state to Processing
+	h.sessionManager.UpdateSession(userID, models.StateProcessing)
+
+	// Send processing message (edited into the result once the estimate is ready)
+	text := p.T("processing.image")
+	if len(images) > 1 {
+		text = p.N("processing.photos", len(images), len(images))
+	}
+	h.sendProcessing(c, text)
+
 	// Call Gemini Vision API (T028)
-	result, err := h.estimator.EstimateFromImage(ctx, imageBytes, mimeType)
+	ctx, cancel := h.estimateContext(userID, p)
+	defer cancel()
+	result, err := h.estimator.EstimateFromImages(ctx, images, note)
+
+	// Keep session in AwaitingImage state for potential Re-estimate
+	return h.deliverResult(c, p, result, err, models.StateAwaitingImage, note, p.T("estimate.no_food_image"))
+}
+
+// sendProcessing posts the "Analyzing..." message and records it as the message to edit into the result
+func (h *EstimateHandler) sendProcessing(c telebot.Context, text string) {
+	userID := c.Sender().ID
+
+	msg, err := h.sender.Send(c.Sender(), text)
 	if err != nil {
-		log.Printf("error when call gemini API: %v", err)
+		log.Printf("Failed to send processing message: %v", err)
+		h.sessionManager.SetMessageID(userID, 0)
+		return
+	}
+	h.sessionManager.SetMessageID(userID, msg.ID)
+}
+
+// deliverResult handles the estimator outcome shared by the photo and text flows:
+// error/no-food replies, and the result message with Save/Adjust/Discard buttons,
+// both shown by editing the "Analyzing..." message in place
+// On success the session moves to nextState so Re-estimate knows which input to ask for
+// note is stored on the log entry once saved (the user's caption or notes, may be empty)
+func (h *EstimateHandler) deliverResult(c telebot.Context, p *i18n.Printer, result *models.EstimateResult, estimateErr error, nextState models.SessionState, note, noFoodMessage string) error {
+	userID := c.Sender().ID
+
+	if estimateErr != nil {
+		log.Printf("error when call gemini API: %v", estimateErr)
 		h.sessionManager.UpdateSession(userID, models.StateIdle)
-		// Delete processing message
-		if processingMsg != nil {
-			if delErr := h.sender.Delete(processingMsg); delErr != nil {
-				log.Printf("Failed to delete processing message: %v", delErr)
-			}
-		}
-		return h.sendError(c, "API error. Please try again later.") // T033
+		return h.showMessage(c.Sender(), userID, "❌ "+p.T("estimate.api_error"), nil) // T033
 	}
 
 	// Check if food was detected (T031 - FR-014)
 	if !result.HasFood() {
 		h.sessionManager.UpdateSession(userID, models.StateIdle)
-		// Delete processing message
-		if processingMsg != nil {
-			if delErr := h.sender.Delete(processingMsg); delErr != nil {
-				log.Printf("Failed to delete processing message: %v", delErr)
-			}
-		}
-		return h.sendError(c, "No food detected in image. Please send an image containing food.")
+		return h.showMessage(c.Sender(), userID, "❌ "+noFoodMessage, nil)
 	}
 
-	// Delete processing message
-	if processingMsg != nil {
-		if delErr := h.sender.Delete(processingMsg); delErr != nil {
-			log.Printf("Failed to delete processing message: %v", delErr)
+	h.sessionManager.UpdateSession(userID, nextState)
+
+	// Without shared storage there is nothing to save; show the result with Re-estimate/Cancel only
+	if h.storage == nil {
+		markup := &telebot.ReplyMarkup{}
+		btnReEstimate := markup.Data(p.T("button.re_estimate"), "re_estimate")
+		btnCancel := markup.Data(p.T("button.cancel"), "cancel")
+		markup.Inline(
+			markup.Row(btnReEstimate, btnCancel),
+		)
+		return h.showMessage(c.Sender(), userID, models.FormatResult(p, result), markup)
+	}
+
+	// The log entry is only written once the user taps Save (or auto-save fires)
+	mealType := h.userProfile(userID).MealTypeAt(time.Now())
+	pending, replaced := h.sessionManager.SetPending(userID, result, note, nextState, mealType)
+	if replaced != nil {
+		// The previous result was neither saved nor discarded: retire its buttons and say so
+		h.removeButtons(userID, append([]int{replaced.MessageID}, replaced.MenuIDs...))
+		if _, err := h.sender.Send(c.Sender(), p.T("confirm.replaced")); err != nil {
+			log.Printf("[HANDLER ERROR] Failed to send replaced notice to user %d: %v", userID, err)
 		}
 	}
+	if h.autoSave > 0 {
+		h.scheduleAutoSave(c.Sender(), p, userID, pending.ID)
+	}
+
+	return h.sendPending(c.Sender(), p, userID, pending)
+}
 
-	// Format and send result (T030 - FR-006)
-	formattedResult := models.FormatResult(result)
+// showMessage edits the session's current bot message (e.g. "Analyzing...") into text with markup,
+// falling back to a new message when there is none or the edit fails
+// markup may be nil; editing without one removes the message's inline keyboard
+func (h *EstimateHandler) showMessage(to telebot.Recipient, userID int64, text string, markup *telebot.ReplyMarkup) error {
+	_, err := h.showMessageID(to, userID, text, markup)
+	return err
+}
 
-	// Create inline keyboard with Re-estimate and Cancel buttons (T029 - FR-008, FR-009)
-	markup := &telebot.ReplyMarkup{}
-	btnReEstimate := markup.Data("Re-estimate", "re_estimate")
-	btnCancel := markup.Data("Cancel", "cancel")
-	markup.Inline(
-		markup.Row(btnReEstimate, btnCancel),
-	)
+// showMessageID is showMessage returning the ID of the message that now shows text
+func (h *EstimateHandler) showMessageID(to telebot.Recipient, userID int64, text string, markup *telebot.ReplyMarkup) (int, error) {
+	var opts []interface{}
+	if markup != nil {
+		opts = append(opts, markup)
+	}
+
+	if msgID := h.sessionManager.GetSession(userID).MessageID; msgID != 0 {
+		_, err := h.sender.Edit(storedMessage(userID, msgID), text, opts...)
+		if err == nil {
+			return msgID, nil
+		}
+		log.Printf("[HANDLER ERROR] Failed to edit message %d for user %d, sending a new one: %v", msgID, userID, err)
+	}
 
-	_, err = h.sender.Send(c.Sender(), formattedResult, markup)
+	msg, err := h.sender.Send(to, text, opts...)
 	if err != nil {
-		return fmt.Errorf("failed to send result: %w", err)
+		return 0, fmt.Errorf("failed to send message: %w", err)
 	}
+	h.sessionManager.SetMessageID(userID, msg.ID)
+	return msg.ID, nil
+}
 
-	// Store the log entry in shared storage (visible in miniapp)
-	if h.storage != nil {
-		logEntry := &internalmodels.Log{
-			FoodItems:  result.FoodItems,
-			Calories:   result.Calories,
-			Confidence: internalmodels.ConfidenceLevel(result.Confidence),
-			Timestamp:  time.Now(),
-		}
+// clearButtons removes the inline keyboard from the session's current bot message, from the
+// tapped message (may be nil) and from the messages offering pending (may be nil), so stale
+// buttons cannot be pressed again
+func (h *EstimateHandler) clearButtons(userID int64, tapped *telebot.Message, pending *models.PendingEstimate) {
+	messageIDs := []int{h.sessionManager.TakeMessageID(userID)}
+	if tapped != nil {
+		messageIDs = append(messageIDs, tapped.ID)
+	}
+	if pending != nil {
+		messageIDs = append(append(messageIDs, pending.MessageID), pending.MenuIDs...)
+	}
+	h.removeButtons(userID, messageIDs)
+}
 
-		if err := h.storage.CreateLog(userID, logEntry); err != nil {
-			// Log error but don't fail the user's request
-			log.Printf("[HANDLER ERROR] Failed to save log entry for user %d: %v", userID, err)
-		} else {
-			log.Printf("[HANDLER] ✓ Log entry saved for user %d: %d kcal, %d items", userID, result.Calories, len(result.FoodItems))
+// removeButtons removes the inline keyboard from each bot message once, skipping zero IDs
+func (h *EstimateHandler) removeButtons(userID int64, messageIDs []int) {
+	done := map[int]bool{0: true}
+	for _, msgID := range messageIDs {
+		if done[msgID] {
+			continue
+		}
+		done[msgID] = true
+		if _, err := h.sender.EditReplyMarkup(storedMessage(userID, msgID), nil); err != nil {
+			log.Printf("[HANDLER ERROR] Failed to remove buttons from message %d for user %d: %v", msgID, userID, err)
 		}
 	}
+}
 
-	// Keep session in AwaitingImage state for potential Re-estimate
-	h.sessionManager.UpdateSession(userID, models.StateAwaitingImage)
+// storedMessage addresses a bot message by ID; the bot talks in private chats, where the chat ID is the user ID
+func storedMessage(userID int64, messageID int) telebot.StoredMessage {
+	return telebot.StoredMessage{MessageID: strconv.Itoa(messageID), ChatID: userID}
+}
 
+// tappedMessage returns the message whose inline button was pressed (nil outside callbacks)
+func tappedMessage(c telebot.Context) *telebot.Message {
+	if cb := c.Callback(); cb != nil {
+		return cb.Message
+	}
 	return nil
 }
 
@@ -253,9 +525,16 @@ func (h *EstimateHandler) processImage(c telebot.Context, fileID, mimeType strin
 func (h *EstimateHandler) HandleReEstimate(c telebot.Context) error {
 	userID := c.Sender().ID
 	log.Printf("[HANDLER] HandleReEstimate called for user %d", userID)
+	p := h.printer(c)
+
+	// Re-estimate on an older result must not drop the estimate shown since
+	pendingID := tappedPendingID(c)
+	if pendingID != 0 && !h.sessionManager.HasPending(userID, pendingID) {
+		return h.rejectStaleTap(c, p)
+	}
 
 	// Update callback to show feedback
-	if err := c.Respond(&telebot.CallbackResponse{Text: "Send another image"}); err != nil {
+	if err := c.Respond(&telebot.CallbackResponse{Text: p.T("callback.send_another")}); err != nil {
 		log.Printf("[HANDLER ERROR] Failed to respond to re_estimate callback for user %d: %v", userID, err)
 	}
 
@@ -263,17 +542,31 @@ func (h *EstimateHandler) HandleReEstimate(c telebot.Context) error {
 	// Previous implementation deleted the message with c.Delete()
 	// Now we preserve conversation history
 
-	// Update state to AwaitingImage
-	h.sessionManager.UpdateSession(userID, models.StateAwaitingImage)
+	// The estimate was wrong: drop it unsaved and retire its buttons
+	previous := h.sessionManager.GetSession(userID).State
+	pending := h.sessionManager.TakePending(userID, pendingID)
+	if pending != nil {
+		previous = pending.NextState
+	}
+	h.clearButtons(userID, tappedMessage(c), pending)
+
+	// Ask for the same kind of input as the previous estimate (image or description)
+	nextState := models.StateAwaitingImage
+	prompt := p.T("reestimate.image")
+	if previous == models.StateAwaitingDescription {
+		nextState = models.StateAwaitingDescription
+		prompt = p.T("reestimate.description")
+	}
+	h.sessionManager.UpdateSession(userID, nextState)
 
 	// Send new prompt
 	markup := &telebot.ReplyMarkup{}
-	btnCancel := markup.Data("Cancel", "cancel")
+	btnCancel := markup.Data(p.T("button.cancel"), "cancel")
 	markup.Inline(
 		markup.Row(btnCancel),
 	)
 
-	msg, err := h.sender.Send(c.Sender(), "📸 Please send another food image", markup)
+	msg, err := h.sender.Send(c.Sender(), prompt, markup)
 	if err != nil {
 		log.Printf("[HANDLER ERROR] Failed to send re-estimate prompt for user %d: %v", userID, err)
 		return fmt.Errorf("failed to send re-estimate prompt: %w", err)
@@ -290,9 +583,10 @@ func (h *EstimateHandler) HandleReEstimate(c telebot.Context) error {
 func (h *EstimateHandler) HandleCancel(c telebot.Context) error {
 	userID := c.Sender().ID
 	log.Printf("[HANDLER] HandleCancel called for user %d", userID)
+	p := h.printer(c)
 
 	// Update callback to show feedback
-	if err := c.Respond(&telebot.CallbackResponse{Text: "Estimation canceled"}); err != nil {
+	if err := c.Respond(&telebot.CallbackResponse{Text: p.T("callback.canceled")}); err != nil {
 		log.Printf("[HANDLER ERROR] Failed to respond to cancel callback for user %d: %v", userID, err)
 	}
 
@@ -300,11 +594,12 @@ func (h *EstimateHandler) HandleCancel(c telebot.Context) error {
 	// Previous implementation deleted the message with c.Delete()
 	// Now we preserve conversation history
 
-	// Clean up session
+	// Strip the now-stale inline buttons (including an unsaved estimate's), then clean up session
+	h.clearButtons(userID, tappedMessage(c), h.sessionManager.TakePending(userID, 0))
 	h.sessionManager.DeleteSession(userID)
 
 	// Send cancellation confirmation (FR-013)
-	_, err := h.sender.Send(c.Sender(), "Estimation canceled. Use /estimate to start again.")
+	_, err := h.sender.Send(c.Sender(), p.T("cancel.done"))
 	if err != nil {
 		log.Printf("[HANDLER ERROR] Failed to send cancellation message for user %d: %v", userID, err)
 		return fmt.Errorf("failed to send cancellation message: %w", err)
@@ -314,6 +609,25 @@ func (h *EstimateHandler) HandleCancel(c telebot.Context) error {
 	return nil
 }
 
+// HandleUnknownCallback answers a button tap the bot does not recognize (e.g. from an old release)
+func (h *EstimateHandler) HandleUnknownCallback(c telebot.Context) error {
+	return c.Respond(&telebot.CallbackResponse{Text: h.printer(c).T("callback.unknown")})
+}
+
+// toLogMacros converts estimator macros into the shared log model (nil-safe)
+func toLogMacros(m *models.Macros) *internalmodels.Macros {
+	if m == nil {
+		return nil
+	}
+	return &internalmodels.Macros{
+		Protein: m.Protein,
+		Carbs:   m.Carbs,
+		Fat:     m.Fat,
+		Fiber:   m.Fiber,
+		Sugar:   m.Sugar,
+	}
+}
+
 // isValidImageFormat validates image MIME type (T026 - FR-003)
 // Accepts: image/jpeg, image/png, image/webp
 func isValidImageFormat(mimeType string) bool {
@@ -326,6 +640,11 @@ func isValidImageFormat(mimeType string) bool {
 	return false
 }
 
+// imageTooLargeMessage explains the upload limit in megabytes
+func imageTooLargeMessage(p *i18n.Printer, maxBytes int64) string {
+	return p.T("image.too_large", maxBytes>>20)
+}
+
 // sendError sends an error message to the user
 // Helper for T031, T032, T033 error handling
 func (h *EstimateHandler) sendError(c telebot.Context, message string) error {
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"bytes"
+	"log"
+	"time"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	telebot "gopkg.in/telebot.v3"
+)
+
+// HandleExport handles the /export command
+// Usage: /export [csv|ndjson] [from] [to] with YYYY-MM-DD dates in the user's timezone
+// Sends the matching log history (whole history by default) as a document
+func (h *EstimateHandler) HandleExport(c telebot.Context) error {
+	userID := c.Sender().ID
+	p := h.printer(c)
+
+	if h.storage == nil {
+		return h.sendError(c, p.T("export.unavailable"))
+	}
+
+	profile := h.userProfile(userID)
+	loc := profile.Location()
+	format, from, to, ok := parseExportArgs(c.Args(), loc)
+	if !ok {
+		return h.sendError(c, p.T("export.usage"))
+	}
+
+	logs, err := h.storage.ListLogs(userID)
+	if err != nil {
+		log.Printf("[HANDLER ERROR] Failed to load logs for user %d: %v", userID, err)
+		return h.sendError(c, p.T("export.failed"))
+	}
+	logs = internalmodels.ExportLogs(logs, from, to)
+	if len(logs) == 0 {
+		return h.sendError(c, p.T("export.empty"))
+	}
+
+	var buf bytes.Buffer
+	if err := internalmodels.WriteExport(&buf, format, logs, loc); err != nil {
+		log.Printf("[HANDLER ERROR] Failed to write %s export for user %d: %v", format, userID, err)
+		return h.sendError(c, p.T("export.failed"))
+	}
+
+	document := &telebot.Document{
+		File:     telebot.FromReader(&buf),
+		FileName: format.FileName(time.Now().In(loc)),
+		MIME:     format.MIME(),
+		Caption:  p.N("export.caption", len(logs), len(logs)),
+	}
+	if _, err := h.sender.Send(c.Sender(), document); err != nil {
+		log.Printf("[HANDLER ERROR] Failed to send export to user %d: %v", userID, err)
+		return err
+	}
+
+	log.Printf("[HANDLER] Sent %s export to user %d (%d entries)", format, userID, len(logs))
+	return nil
+}
+
+// parseExportArgs reads [format] [from] [to]; reports false for anything else
+// The to date is inclusive (the range ends at the end of that day)
+func parseExportArgs(args []string, loc *time.Location) (internalmodels.ExportFormat, *time.Time, *time.Time, bool) {
+	format := internalmodels.ExportCSV
+	if len(args) > 0 {
+		if parsed, err := internalmodels.ParseExportFormat(args[0]); err == nil {
+			format, args = parsed, args[1:]
+		}
+	}
+	if len(args) > 2 {
+		return "", nil, nil, false
+	}
+
+	var bounds [2]*time.Time
+	for i, raw := range args {
+		t, err := internalmodels.ParseRangeBound(raw, loc, i == 1)
+		if err != nil {
+			return "", nil, nil, false
+		}
+		bounds[i] = &t
+	}
+	if bounds[0] != nil && bounds[1] != nil && !bounds[0].Before(*bounds[1]) {
+		return "", nil, nil, false
+	}
+	return format, bounds[0], bounds[1], true
+}
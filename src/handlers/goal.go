@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/src/i18n"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	telebot "gopkg.in/telebot.v3"
+)
+
+// HandleGoal handles the /goal command
+// Usage: /goal (show), /goal <kcal> [timezone] (set), /goal off (clear)
+func (h *EstimateHandler) HandleGoal(c telebot.Context) error {
+	userID := c.Sender().ID
+	p := h.printer(c)
+
+	if h.profiles == nil {
+		return h.sendError(c, p.T("goal.unavailable"))
+	}
+
+	profile, err := h.profiles.GetProfile(userID)
+	if err != nil {
+		log.Printf("[HANDLER ERROR] Failed to load profile for user %d: %v", userID, err)
+		return h.sendError(c, p.T("goal.load_failed"))
+	}
+
+	args := c.Args()
+	if len(args) == 0 {
+		_, err := h.sender.Send(c.Sender(), models.FormatGoalStatus(p, profile.DailyGoal, profile.Timezone))
+		return err
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "off", "clear", "0":
+		profile.DailyGoal = 0
+	default:
+		goal, err := strconv.Atoi(args[0])
+		if err != nil || goal <= 0 || goal > internalmodels.MaxDailyGoal {
+			return h.sendError(c, p.T("goal.range", p.Number(internalmodels.MaxDailyGoal)))
+		}
+		profile.DailyGoal = goal
+	}
+
+	if len(args) > 1 {
+		if _, err := time.LoadLocation(args[1]); err != nil {
+			return h.sendError(c, p.T("timezone.unknown"))
+		}
+		profile.Timezone = args[1]
+	}
+
+	if err := h.profiles.SaveProfile(profile); err != nil {
+		log.Printf("[HANDLER ERROR] Failed to save profile for user %d: %v", userID, err)
+		return h.sendError(c, p.T("goal.save_failed"))
+	}
+
+	log.Printf("[HANDLER] User %d set daily goal to %d kcal (timezone %q)", userID, profile.DailyGoal, profile.Timezone)
+
+	reply := models.FormatGoalStatus(p, profile.DailyGoal, profile.Timezone)
+	if progress := h.goalProgress(p, userID); progress != "" {
+		reply += "\n\n" + progress
+	}
+	_, err = h.sender.Send(c.Sender(), reply)
+	return err
+}
+
+// goalProgress returns today's consumed-vs-goal summary for the user
+// Returns an empty string if no goal is set or storage is unavailable
+func (h *EstimateHandler) goalProgress(p *i18n.Printer, userID int64) string {
+	if h.profiles == nil || h.storage == nil {
+		return ""
+	}
+
+	profile, err := h.profiles.GetProfile(userID)
+	if err != nil {
+		log.Printf("[HANDLER ERROR] Failed to load profile for user %d: %v", userID, err)
+		return ""
+	}
+	if profile.DailyGoal == 0 {
+		return ""
+	}
+
+	logs, err := h.storage.ListLogs(userID)
+	if err != nil {
+		log.Printf("[HANDLER ERROR] Failed to load logs for user %d: %v", userID, err)
+		return ""
+	}
+
+	consumed := internalmodels.CaloriesOnDay(logs, time.Now(), profile.Location())
+	return models.FormatGoalProgress(p, consumed, profile.DailyGoal)
+}
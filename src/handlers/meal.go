@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"log"
+	"strings"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	telebot "gopkg.in/telebot.v3"
+)
+
+// mealCallbackPrefix prefixes the meal type buttons' callback data, e.g. "meal_lunch"
+const mealCallbackPrefix = "meal_"
+
+// HandleMeals handles the /meals command
+// Usage: /meals (show), /meals breakfast=06:00-10:00,lunch=12:00-15:00,dinner=18:00-22:00 (set), /meals reset
+func (h *EstimateHandler) HandleMeals(c telebot.Context) error {
+	userID := c.Sender().ID
+	p := h.printer(c)
+
+	if h.profiles == nil {
+		return h.sendError(c, p.T("meals.unavailable"))
+	}
+
+	profile, err := h.profiles.GetProfile(userID)
+	if err != nil {
+		log.Printf("[HANDLER ERROR] Failed to load profile for user %d: %v", userID, err)
+		return h.sendError(c, p.T("settings.load_failed"))
+	}
+
+	args := c.Args()
+	if len(args) == 0 {
+		_, err := h.sender.Send(c.Sender(), models.FormatMealWindowsStatus(p, profile.MealWindowList(), profile.MealWindows == ""))
+		return err
+	}
+
+	if len(args) == 1 && strings.EqualFold(args[0], "reset") {
+		profile.MealWindows = ""
+	} else {
+		windows, err := internalmodels.ParseMealWindows(strings.Join(args, ","))
+		if err != nil {
+			return h.sendError(c, p.T("meals.invalid", err.Error()))
+		}
+		profile.MealWindows = internalmodels.FormatMealWindows(windows)
+	}
+
+	if err := h.profiles.SaveProfile(profile); err != nil {
+		log.Printf("[HANDLER ERROR] Failed to save profile for user %d: %v", userID, err)
+		return h.sendError(c, p.T("settings.save_failed"))
+	}
+
+	log.Printf("[HANDLER] User %d set meal windows to %q", userID, profile.MealWindows)
+
+	_, err = h.sender.Send(c.Sender(), models.FormatMealWindowsStatus(p, profile.MealWindowList(), profile.MealWindows == ""))
+	return err
+}
+
+// HandleMealType handles a meal type button on the result (meal_breakfast, meal_lunch, ...)
+// Overrides the inferred meal type of the pending estimate and updates the result in place
+func (h *EstimateHandler) HandleMealType(c telebot.Context) error {
+	userID := c.Sender().ID
+	p := h.printer(c)
+
+	action, pendingID := ParseCallbackData(c.Callback().Data)
+	mealType := internalmodels.MealType(strings.TrimPrefix(action, mealCallbackPrefix))
+	if !mealType.Valid() {
+		return c.Respond(&telebot.CallbackResponse{Text: p.T("callback.unknown_meal")})
+	}
+
+	pending := h.sessionManager.SetPendingMealType(userID, pendingID, mealType)
+	if pending == nil {
+		return h.answerNoPending(c, p, pendingID, "callback.nothing_change")
+	}
+	if err := c.Respond(&telebot.CallbackResponse{Text: models.FormatMealLabel(p, mealType)}); err != nil {
+		log.Printf("[HANDLER ERROR] Failed to respond to meal type callback for user %d: %v", userID, err)
+	}
+	log.Printf("[HANDLER] User %d set pending meal type to %s", userID, mealType)
+
+	return h.sendPending(c.Sender(), p, userID, pending)
+}
+
+// userProfile loads the user's profile, falling back to defaults (server time, no goal)
+// when profiles are unavailable or fail to load
+func (h *EstimateHandler) userProfile(userID int64) *internalmodels.UserProfile {
+	if h.profiles != nil {
+		profile, err := h.profiles.GetProfile(userID)
+		if err == nil {
+			return profile
+		}
+		log.Printf("[HANDLER ERROR] Failed to load profile for user %d: %v", userID, err)
+	}
+	return &internalmodels.UserProfile{UserID: userID}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"log"
+	"strings"
+
+	"github.com/freezind/telegram-calories-bot/src/models"
+	telebot "gopkg.in/telebot.v3"
+)
+
+// HandleOneShot handles the /oneshot command
+// Usage: /oneshot (show), /oneshot on, /oneshot off
+func (h *EstimateHandler) HandleOneShot(c telebot.Context) error {
+	userID := c.Sender().ID
+	p := h.printer(c)
+
+	if h.profiles == nil {
+		return h.sendError(c, p.T("oneshot.unavailable"))
+	}
+
+	profile, err := h.profiles.GetProfile(userID)
+	if err != nil {
+		log.Printf("[HANDLER ERROR] Failed to load profile for user %d: %v", userID, err)
+		return h.sendError(c, p.T("settings.load_failed"))
+	}
+
+	args := c.Args()
+	if len(args) == 0 {
+		_, err := h.sender.Send(c.Sender(), models.FormatOneShotStatus(p, profile.OneShot))
+		return err
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "on", "yes", "1":
+		profile.OneShot = true
+	case "off", "no", "0":
+		profile.OneShot = false
+	default:
+		return h.sendError(c, p.T("oneshot.usage"))
+	}
+
+	if err := h.profiles.SaveProfile(profile); err != nil {
+		log.Printf("[HANDLER ERROR] Failed to save profile for user %d: %v", userID, err)
+		return h.sendError(c, p.T("settings.save_failed"))
+	}
+
+	log.Printf("[HANDLER] User %d set one-shot mode to %v", userID, profile.OneShot)
+
+	_, err = h.sender.Send(c.Sender(), models.FormatOneShotStatus(p, profile.OneShot))
+	return err
+}
+
+// oneShotEnabled reports whether the user estimates photos without /estimate
+// Off when profiles are unavailable or fail to load
+func (h *EstimateHandler) oneShotEnabled(userID int64) bool {
+	if h.profiles == nil {
+		return false
+	}
+
+	profile, err := h.profiles.GetProfile(userID)
+	if err != nil {
+		log.Printf("[HANDLER ERROR] Failed to load profile for user %d: %v", userID, err)
+		return false
+	}
+	return profile.OneShot
+}
+
+// estimateOneShot downloads a single photo and estimates it right away
+// Notes sent after /estimate and the photo's caption are used as hints
+func (h *EstimateHandler) estimateOneShot(c telebot.Context, fileID string) error {
+	userID := c.Sender().ID
+
+	image, err := h.downloadImage(h.printer(c), userID, fileID)
+	if err != nil {
+		return h.sendError(c, err.Error())
+	}
+	log.Printf("[HANDLER] One-shot estimate for user %d", userID)
+
+	if caption := strings.TrimSpace(c.Message().Caption); caption != "" {
+		h.sessionManager.AddHint(userID, caption)
+	}
+	_, hints := h.sessionManager.TakeMeal(userID)
+
+	return h.estimateImages(c, []models.ImageInput{image}, mealNote(hints))
+}
+
+// sendEstimateHint explains how to get an estimate when a photo arrives outside the /estimate flow
+// Albums are answered once rather than once per photo
+func (h *EstimateHandler) sendEstimateHint(c telebot.Context, albumID string) error {
+	userID := c.Sender().ID
+
+	if albumID != "" && h.sessionManager.SeenAlbum(userID, albumID) {
+		return nil
+	}
+	log.Printf("[HANDLER] Photo from user %d outside the /estimate flow", userID)
+
+	p := h.printer(c)
+	text := p.T("photo.outside_flow")
+	if h.profiles != nil {
+		text += "\n\n" + p.T("photo.oneshot_tip")
+	}
+	_, err := h.sender.Send(c.Sender(), text)
+	return err
+}
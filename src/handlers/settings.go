@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/src/i18n"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	telebot "gopkg.in/telebot.v3"
+)
+
+// DetectLanguage is bot middleware that fills a new user's language and locale from Telegram's
+// language_code; each user's profile is checked at most once per process
+func (h *EstimateHandler) DetectLanguage(next telebot.HandlerFunc) telebot.HandlerFunc {
+	return func(c telebot.Context) error {
+		if sender := c.Sender(); sender != nil && h.profiles != nil {
+			if _, checked := h.languageChecked.LoadOrStore(sender.ID, true); !checked {
+				h.applyLanguageCode(sender.ID, sender.LanguageCode)
+			}
+		}
+		return next(c)
+	}
+}
+
+// applyLanguageCode saves the Telegram client language on a profile that has none yet
+func (h *EstimateHandler) applyLanguageCode(userID int64, code string) {
+	profile, err := h.profiles.GetProfile(userID)
+	if err != nil {
+		log.Printf("[HANDLER ERROR] Failed to load profile for user %d: %v", userID, err)
+		return
+	}
+	if !profile.ApplyLanguageCode(code) {
+		return
+	}
+	if err := h.profiles.SaveProfile(profile); err != nil {
+		log.Printf("[HANDLER ERROR] Failed to save detected language for user %d: %v", userID, err)
+		return
+	}
+	log.Printf("[HANDLER] User %d language detected as %q (locale %q)", userID, profile.Language, profile.Locale)
+}
+
+// printer returns the message printer for the user who sent the update
+func (h *EstimateHandler) printer(c telebot.Context) *i18n.Printer {
+	sender := c.Sender()
+	return profilePrinter(h.userProfile(sender.ID), sender.LanguageCode)
+}
+
+// profilePrinter picks the reply language: the profile's language, then the Telegram
+// client's language_code (when profiles are unavailable or not yet filled), then English
+// Energy and portion sizes use the profile's display units
+func profilePrinter(profile *internalmodels.UserProfile, languageCode string) *i18n.Printer {
+	if profile.Language == "" {
+		detected := &internalmodels.UserProfile{}
+		if detected.ApplyLanguageCode(languageCode) {
+			return i18n.New(detected.Language, detected.Locale).WithUnits(profile.DisplayUnits())
+		}
+	}
+	return i18n.New(profile.LanguageOrDefault(), profile.Locale).WithUnits(profile.DisplayUnits())
+}
+
+// HandleSettings handles the /settings command
+// Usage: /settings (show), /settings timezone|language|locale|units|energy <value>,
+// /settings diet <notes> (several words; "none" clears them)
+func (h *EstimateHandler) HandleSettings(c telebot.Context) error {
+	userID := c.Sender().ID
+	p := h.printer(c)
+
+	if h.profiles == nil {
+		return h.sendError(c, p.T("settings.unavailable"))
+	}
+
+	profile, err := h.profiles.GetProfile(userID)
+	if err != nil {
+		log.Printf("[HANDLER ERROR] Failed to load profile for user %d: %v", userID, err)
+		return h.sendError(c, p.T("settings.load_failed"))
+	}
+
+	args := c.Args()
+	if len(args) == 0 {
+		_, err := h.sender.Send(c.Sender(), models.FormatSettings(p, profile)+"\n\n"+p.T("settings.usage"))
+		return err
+	}
+	key := strings.ToLower(args[0])
+	if len(args) < 2 || (len(args) > 2 && key != "diet") {
+		return h.sendError(c, p.T("settings.usage"))
+	}
+
+	value := args[1]
+	switch key {
+	case "timezone", "tz":
+		if _, err := time.LoadLocation(value); err != nil {
+			return h.sendError(c, p.T("timezone.unknown"))
+		}
+		profile.Timezone = value
+	case "language", "lang":
+		locale, err := internalmodels.NormalizeLocale(value)
+		if err != nil || locale != internalmodels.LanguageOf(locale) {
+			return h.sendError(c, p.T("settings.unknown_language"))
+		}
+		profile.SetLanguage(locale)
+	case "locale":
+		if err := profile.SetLocale(value); err != nil {
+			return h.sendError(c, p.T("settings.unknown_locale"))
+		}
+	case "units":
+		units, err := internalmodels.ParseUnitSystem(value)
+		if err != nil {
+			return h.sendError(c, p.T("settings.invalid_units"))
+		}
+		profile.Units = units
+	case "energy":
+		energy, err := internalmodels.ParseEnergyUnit(value)
+		if err != nil {
+			return h.sendError(c, p.T("settings.invalid_energy"))
+		}
+		profile.Energy = energy
+	case "diet":
+		notes := strings.Join(args[1:], " ")
+		if strings.EqualFold(notes, "none") {
+			notes = ""
+		}
+		if utf8.RuneCountInString(notes) > internalmodels.MaxDietaryNotesLength {
+			return h.sendError(c, p.T("settings.invalid_diet", internalmodels.MaxDietaryNotesLength))
+		}
+		profile.DietaryNotes = notes
+	default:
+		return h.sendError(c, p.T("settings.usage"))
+	}
+
+	if err := h.profiles.SaveProfile(profile); err != nil {
+		log.Printf("[HANDLER ERROR] Failed to save profile for user %d: %v", userID, err)
+		return h.sendError(c, p.T("settings.save_failed"))
+	}
+
+	log.Printf("[HANDLER] User %d updated settings: timezone=%q language=%q locale=%q units=%q energy=%q diet=%d chars",
+		userID, profile.Timezone, profile.Language, profile.Locale, profile.Units, profile.Energy, utf8.RuneCountInString(profile.DietaryNotes))
+
+	// A new language or unit applies to this reply already
+	p = profilePrinter(profile, c.Sender().LanguageCode)
+	_, err = h.sender.Send(c.Sender(), p.T("settings.saved")+"\n\n"+models.FormatSettings(p, profile))
+	return err
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	telebot "gopkg.in/telebot.v3"
+)
+
+// HandleToday handles the /today command (today's totals vs. goal)
+func (h *EstimateHandler) HandleToday(c telebot.Context) error {
+	return h.sendSummary(c, "summary.today", 1)
+}
+
+// HandleWeek handles the /week command (last 7 days including today)
+func (h *EstimateHandler) HandleWeek(c telebot.Context) error {
+	return h.sendSummary(c, "summary.week", 7)
+}
+
+// HandleMonth handles the /month command (last 30 days including today)
+func (h *EstimateHandler) HandleMonth(c telebot.Context) error {
+	return h.sendSummary(c, "summary.month", 30)
+}
+
+// sendSummary aggregates the user's logs over the last N days and sends the report
+// titleKey is the catalog key of the report title
+func (h *EstimateHandler) sendSummary(c telebot.Context, titleKey string, days int) error {
+	userID := c.Sender().ID
+	p := h.printer(c)
+
+	if h.storage == nil {
+		return h.sendError(c, p.T("summary.unavailable"))
+	}
+
+	logs, err := h.storage.ListLogs(userID)
+	if err != nil {
+		log.Printf("[HANDLER ERROR] Failed to load logs for user %d: %v", userID, err)
+		return h.sendError(c, p.T("summary.load_failed"))
+	}
+
+	profile := h.userProfile(userID)
+	summary := internalmodels.SummarizeDays(logs, time.Now(), days, profile.Location(), profile.MealWindowList(), profile.DailyGoal)
+	_, err = h.sender.Send(c.Sender(), models.FormatSummary(p, p.T(titleKey), &summary))
+	if err != nil {
+		log.Printf("[HANDLER ERROR] Failed to send summary to user %d: %v", userID, err)
+		return err
+	}
+
+	log.Printf("[HANDLER] Sent %s summary to user %d (%d entries)", titleKey, userID, summary.Entries)
+	return nil
+}
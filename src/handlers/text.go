@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/freezind/telegram-calories-bot/src/models"
+	telebot "gopkg.in/telebot.v3"
+)
+
+// maxDescriptionLength limits text meal descriptions sent to the estimator
+const maxDescriptionLength = 500
+
+// HandleLog handles the /log command (text-based meal logging)
+// Flow: /log <description> estimates immediately; bare /log prompts → State: AwaitingDescription
+func (h *EstimateHandler) HandleLog(c telebot.Context) error {
+	userID := c.Sender().ID
+
+	if description := strings.TrimSpace(strings.Join(c.Args(), " ")); description != "" {
+		return h.processDescription(c, description)
+	}
+
+	h.sessionManager.UpdateSession(userID, models.StateAwaitingDescription)
+
+	p := h.printer(c)
+	markup := &telebot.ReplyMarkup{}
+	btnCancel := markup.Data(p.T("button.cancel"), "cancel")
+	markup.Inline(
+		markup.Row(btnCancel),
+	)
+
+	msg, err := h.sender.Send(c.Sender(), p.T("log.prompt"), markup)
+	if err != nil {
+		return fmt.Errorf("failed to send prompt: %w", err)
+	}
+
+	h.sessionManager.SetMessageID(userID, msg.ID)
+
+	return nil
+}
+
+// HandleText handles plain text messages
+// Meal descriptions are processed while AwaitingDescription; while awaiting or collecting
+// photos, text is kept as a note for the photo estimate (e.g. "half portion, no sauce");
+// while AwaitingCalories, text is the manual kcal total for the pending estimate
+func (h *EstimateHandler) HandleText(c telebot.Context) error {
+	userID := c.Sender().ID
+
+	text := strings.TrimSpace(c.Text())
+	if text == "" || strings.HasPrefix(text, "/") {
+		return nil
+	}
+
+	switch h.sessionManager.GetSession(userID).State {
+	case models.StateAwaitingDescription:
+		return h.processDescription(c, text)
+	case models.StateAwaitingImage, models.StateCollectingImages:
+		return h.addMealNote(c, text)
+	case models.StateAwaitingCalories:
+		return h.processManualCalories(c, text)
+	default:
+		return nil
+	}
+}
+
+// addMealNote stores text sent during the photo flow as a hint for the upcoming estimate
+func (h *EstimateHandler) addMealNote(c telebot.Context, text string) error {
+	userID := c.Sender().ID
+	p := h.printer(c)
+
+	if utf8.RuneCountInString(text) > maxDescriptionLength {
+		return h.sendError(c, p.N("note.too_long", maxDescriptionLength, maxDescriptionLength))
+	}
+	h.sessionManager.AddHint(userID, text)
+	log.Printf("[HANDLER] Added meal note for user %d (%d chars)", userID, len(text))
+
+	reply := p.T("note.added")
+	if h.sessionManager.GetSession(userID).State == models.StateCollectingImages {
+		reply = p.T("note.added_collecting")
+	}
+	_, err := h.sender.Send(c.Sender(), reply)
+	return err
+}
+
+// processDescription estimates calories from a meal description and delivers the result
+func (h *EstimateHandler) processDescription(c telebot.Context, description string) error {
+	userID := c.Sender().ID
+	p := h.printer(c)
+
+	if utf8.RuneCountInString(description) > maxDescriptionLength {
+		return h.sendError(c, p.N("description.too_long", maxDescriptionLength, maxDescriptionLength))
+	}
+
+	h.sessionManager.UpdateSession(userID, models.StateProcessing)
+
+	h.sendProcessing(c, p.T("processing.description"))
+
+	log.Printf("[HANDLER] Estimating from description for user %d (%d chars)", userID, len(description))
+	ctx, cancel := h.estimateContext(userID, p)
+	defer cancel()
+	result, err := h.estimator.EstimateFromText(ctx, description)
+
+	// Stay in AwaitingDescription so Re-estimate asks for another description
+	return h.deliverResult(c, p, result, err, models.StateAwaitingDescription, "", p.T("estimate.no_food_text"))
+}
@@ -0,0 +1,221 @@
+package i18n
+
+// en is the English catalog; every key used by the bot must be defined here
+// Numbers are passed pre-formatted (Printer.Number, Printer.Decimal) as %s
+var en = Messages{
+	// /start
+	"welcome": `👋 Welcome to Calorie Estimation Bot!
+
+I help you estimate the calories in your food by analyzing images.
+
+**How to use:**
+1. Send /estimate command
+2. Upload one or more photos of your meal, then tap Done
+3. Receive calorie estimate with confidence indicator
+4. Tap Save to add it to your log, or Adjust the portion first
+
+No photo? Send /log with a description, e.g. /log 2 eggs and a slice of toast
+
+**Features:**
+• 🍽️ Instant calorie estimation
+• 📊 Confidence indicators (Low/Medium/High)
+• 🔄 Re-estimate with different images
+• ⚡ One-shot mode: skip /estimate and just send photos (/oneshot on)
+• 🎯 Daily calorie goal tracking (/goal 2000)
+• 📊 Summaries with /today, /week and /month, grouped by meal
+• 📤 Export your log as CSV or JSON with /export
+• 🍳 Set your breakfast, lunch and dinner times with /meals
+• ⚙️ Timezone, language and units with /settings
+• ❌ Cancel anytime
+
+Ready to start? Send /estimate to begin!`,
+
+	// Buttons
+	"button.cancel":      "Cancel",
+	"button.done":        "Done",
+	"button.re_estimate": "Re-estimate",
+	"button.save":        "✅ Save",
+	"button.adjust":      "✏️ Adjust",
+	"button.discard":     "🗑 Discard",
+	"button.manual_kcal": "Enter kcal",
+
+	// Photo and text flows
+	"estimate.prompt":            "📸 Please send a food image for calorie estimation\n\nSeveral photos of one meal (or an album)? Send them all, then tap Done.",
+	"photo.added":                "📸 Photo %d added. Send more photos of this meal, or tap Done to estimate.",
+	"photo.album":                "📸 Album received. Send more photos of this meal, or tap Done to estimate.",
+	"photo.limit.one":            "You can add up to %d photo per meal. Tap Done to estimate.",
+	"photo.limit.other":          "You can add up to %d photos per meal. Tap Done to estimate.",
+	"photo.outside_flow":         "📸 To estimate calories from a photo, send /estimate first and then the photo.",
+	"photo.busy":                 "⏳ Still working on your previous meal. Send this photo again once its estimate arrives.",
+	"photo.oneshot_tip":          "Tip: turn on one-shot mode with /oneshot on and any food photo you send is estimated right away.",
+	"log.prompt":                 "✍️ Describe what you ate, e.g. \"2 eggs and a slice of toast\"",
+	"note.added":                 "📝 Noted. Now send a photo of your meal.",
+	"note.added_collecting":      "📝 Noted. Send more photos, or tap Done to estimate.",
+	"note.too_long.one":          "Note is too long. Please keep it under %d character.",
+	"note.too_long.other":        "Note is too long. Please keep it under %d characters.",
+	"description.too_long.one":   "Description is too long. Please keep it under %d character.",
+	"description.too_long.other": "Description is too long. Please keep it under %d characters.",
+	"processing.image":           "⏳ Analyzing your image...",
+	"processing.photos.one":      "⏳ Analyzing your %d photo...",
+	"processing.photos.other":    "⏳ Analyzing your %d photos...",
+	"processing.description":     "⏳ Analyzing your meal...",
+	"reestimate.image":           "📸 Please send another food image",
+	"reestimate.description":     "✍️ Please send another meal description",
+	"cancel.done":                "Estimation canceled. Use /estimate to start again.",
+
+	// Image errors
+	"image.unsupported":     "Unsupported format. Please send JPEG, PNG, or WebP images only.",
+	"image.too_large":       "Image is too large. Please send an image under %d MB.",
+	"image.download_failed": "Failed to download image. Please try again.",
+	"image.request_failed":  "Failed to create request. Please try again.",
+	"image.fetch_failed":    "Failed to fetch image. Please try again.",
+	"image.read_failed":     "Failed to read image. Please try again.",
+	"image.invalid":         "Could not read this image. Please send a JPEG, PNG, or WebP photo.",
+
+	// Estimate results
+	"estimate.api_error":     "API error. Please try again later.",
+	"estimate.no_food_image": "No food detected in image. Please send an image containing food.",
+	"estimate.no_food_text":  "Couldn't recognize any food in that description. Please describe what you ate.",
+	"result.title":           "🍽️ Calorie Estimate",
+	"result.calories":        "Estimated Calories: %s",
+	"result.macros":          "Macros: %s",
+	"result.confidence":      "Confidence: %s",
+	"result.items":           "Detected Items: %s",
+	"result.no_items":        "None detected",
+	"confidence.low":         "Low",
+	"confidence.medium":      "Medium",
+	"confidence.high":        "High",
+	"item.portion":           " (%s)",
+	"item.calories":          " — %s",
+	"macros":                 "P %sg · C %sg · F %sg",
+	"macros.fiber":           " · Fiber %sg",
+	"macros.sugar":           " · Sugar %sg",
+
+	// Confirmation (Save / Adjust / Discard)
+	"confirm.question":               "Save this meal to your log?",
+	"confirm.autosave_minutes.one":   " (saved automatically in %d minute)",
+	"confirm.autosave_minutes.other": " (saved automatically in %d minutes)",
+	"confirm.autosave_seconds.one":   " (saved automatically in %d second)",
+	"confirm.autosave_seconds.other": " (saved automatically in %d seconds)",
+	"confirm.saved":                  "✅ Saved",
+	"confirm.autosaved":              "💾 Saved automatically",
+	"confirm.saved_calories":         "%s: %s",
+	"confirm.save_failed":            "Failed to save this meal. Please try again later.",
+	"confirm.discarded":              "🗑 Estimate discarded. Nothing was saved.",
+	"confirm.replaced":               "↩️ Your previous estimate was replaced without saving.",
+	"adjust.prompt":                  "✏️ Adjust the portion relative to the original estimate, or enter the total kcal yourself",
+	"adjust.manual_prompt":           "✍️ Send the total calories for this meal, e.g. 450",
+	"adjust.manual_range":            "Please send a number between 1 and %s, e.g. 450",
+	"adjust.expired":                 "This estimate is no longer available. Use /estimate to start again.",
+	"callback.estimating":            "Estimating...",
+	"callback.photo_first":           "Send a photo of your meal first",
+	"callback.send_another":          "Send another image",
+	"callback.canceled":              "Estimation canceled",
+	"callback.saved":                 "Saved",
+	"callback.discarded":             "Discarded",
+	"callback.nothing_save":          "Nothing to save",
+	"callback.nothing_discard":       "Nothing to discard",
+	"callback.nothing_adjust":        "Nothing to adjust",
+	"callback.nothing_change":        "Nothing to change",
+	"callback.unknown_adjust":        "Unknown adjustment",
+	"callback.unknown":               "Unknown action",
+	"callback.outdated":              "This estimate is no longer active",
+	"callback.unknown_meal":          "Unknown meal",
+	"callback.portion":               "Portion ×%s",
+
+	// Meals
+	"meal.breakfast":      "Breakfast",
+	"meal.lunch":          "Lunch",
+	"meal.dinner":         "Dinner",
+	"meal.snack":          "Snack",
+	"meal.current":        "Meal: %s (tap below to change)",
+	"meals.title_default": "🍽️ Meal times (default):",
+	"meals.title":         "🍽️ Your meal times:",
+	"meals.snack":         "• Snack: any other time",
+	"meals.help":          "Meals are classified in your timezone (/goal). Change the times with e.g. /meals breakfast=06:00-10:00,lunch=12:00-15:00,dinner=18:00-22:00",
+	"meals.reset_hint":    " or go back to the defaults with /meals reset",
+	"meals.invalid":       "Invalid meal windows: %s\n\nExample: /meals breakfast=06:00-10:00,lunch=12:00-15:00,dinner=18:00-22:00",
+	"meals.unavailable":   "Meal windows are not available on this bot.",
+
+	// Goals
+	"goal.today":       "🎯 Today: %s / %s",
+	"goal.remaining":   "Remaining: %s",
+	"goal.over":        "Over goal by %s",
+	"goal.none":        "🎯 No daily goal set (timezone: %s)\n\nSet one with /goal <kcal>, e.g. /goal 2000\nOptionally add your timezone: /goal 2000 Europe/Madrid",
+	"goal.status":      "🎯 Daily goal: %s (timezone: %s)\n\nChange it with /goal <kcal> or remove it with /goal off",
+	"goal.range":       "Please enter a goal between 1 and %s kcal, e.g. /goal 2000",
+	"goal.unavailable": "Daily goals are not available on this bot.",
+	"goal.load_failed": "Failed to load your goal. Please try again.",
+	"goal.save_failed": "Failed to save your goal. Please try again.",
+	"timezone.server":  "server time",
+	"timezone.unknown": "Unknown timezone. Use an IANA name like Europe/Madrid or America/New_York.",
+
+	// One-shot mode
+	"oneshot.on":          "⚡ One-shot mode is on: any food photo you send is estimated right away.\n\nSeveral photos of one meal? Send them as an album and tap Done.\nTurn it off with /oneshot off",
+	"oneshot.off":         "📸 One-shot mode is off: send /estimate before your photos.\n\nTurn it on with /oneshot on to estimate any food photo right away.",
+	"oneshot.usage":       "Usage: /oneshot on or /oneshot off",
+	"oneshot.unavailable": "One-shot mode is not available on this bot.",
+
+	// Summaries
+	"summary.today":         "Today",
+	"summary.week":          "Last 7 days",
+	"summary.month":         "Last 30 days",
+	"summary.total":         "Total: %s",
+	"summary.entries":       "Entries: %d",
+	"summary.average.one":   "Average: %s/day (%d of %d day logged)",
+	"summary.average.other": "Average: %s/day (%d of %d days logged)",
+	"summary.goal.one":      "Goal: %s — within goal on %d/%d logged day",
+	"summary.goal.other":    "Goal: %s — within goal on %d/%d logged days",
+	"summary.empty":         "No meals logged in this period. Use /estimate or /log to add one.",
+	"summary.by_meal":       "By meal:",
+	"summary.per_day":       "Per day:",
+	"summary.line":          "• %s: %s (%d)",
+	"summary.unavailable":   "Summaries are not available on this bot.",
+	"summary.load_failed":   "Failed to load your logs. Please try again.",
+
+	// Export
+	"export.caption.one":   "📤 Your calorie log: %d entry",
+	"export.caption.other": "📤 Your calorie log: %d entries",
+	"export.empty":         "No entries to export for this period.",
+	"export.usage":         "Usage: /export [csv|ndjson] [from] [to]\nDates are YYYY-MM-DD, e.g. /export csv 2024-05-01 2024-05-31",
+	"export.unavailable":   "Export is not available on this bot.",
+	"export.failed":        "Failed to export your log. Please try again.",
+
+	// Settings
+	"settings.title":            "⚙️ Settings",
+	"settings.timezone":         "Timezone: %s",
+	"settings.language":         "Language: %s",
+	"settings.language_default": "%s (default)",
+	"settings.locale":           "Locale: %s",
+	"settings.locale_default":   "%s (from language)",
+	"settings.units":            "Units: %s",
+	"settings.energy":           "Energy: %s",
+	"settings.goal":             "Daily goal: %s",
+	"settings.goal_unset":       "Daily goal: not set (/goal)",
+	"settings.oneshot_on":       "One-shot mode: on",
+	"settings.oneshot_off":      "One-shot mode: off",
+	"settings.meals_default":    "Meal times: default (/meals)",
+	"settings.meals":            "Meal times: %s",
+	"settings.diet":             "Dietary notes: %s",
+	"settings.diet_unset":       "Dietary notes: none",
+	"settings.saved":            "✅ Settings saved",
+	"settings.usage":            "Usage:\n/settings timezone Europe/Madrid\n/settings language es\n/settings locale es-MX\n/settings units metric|imperial\n/settings energy kcal|kj\n/settings diet vegetarian, no nuts (none to clear)",
+	"settings.unknown_language": "Unknown language. Use a two-letter code like en, es, ru or zh.",
+	"settings.unknown_locale":   "Unknown locale. Use a tag like en-US, es-MX or pt-BR.",
+	"settings.invalid_units":    "Units must be metric or imperial.",
+	"settings.invalid_energy":   "Energy must be kcal or kj.",
+	"settings.invalid_diet":     "Dietary notes can be at most %d characters.",
+	"settings.unavailable":      "Settings are not available on this bot.",
+	"settings.load_failed":      "Failed to load your settings. Please try again.",
+	"settings.save_failed":      "Failed to save your settings. Please try again.",
+	"units.metric":              "metric",
+	"units.imperial":            "imperial",
+	"energy_unit.kcal":          "kcal",
+	"energy_unit.kj":            "kJ",
+
+	// Energy and portion sizes in the user's units
+	"energy.kcal":    "%s kcal",
+	"energy.kj":      "%s kJ",
+	"portion.grams":  "%sg",
+	"portion.ounces": "%s oz",
+}
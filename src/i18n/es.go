@@ -0,0 +1,226 @@
+package i18n
+
+// es is the Spanish catalog
+var es = Messages{
+	// /start
+	"welcome": `👋 ¡Bienvenido a Calorie Estimation Bot!
+
+Te ayudo a estimar las calorías de tu comida analizando fotos.
+
+**Cómo usarlo:**
+1. Envía el comando /estimate
+2. Sube una o más fotos de tu comida y pulsa Listo
+3. Recibe la estimación de calorías con su nivel de confianza
+4. Pulsa Guardar para añadirla a tu registro, o Ajustar la porción antes
+
+¿Sin foto? Envía /log con una descripción, p. ej. /log 2 huevos y una tostada
+
+**Funciones:**
+• 🍽️ Estimación de calorías al instante
+• 📊 Nivel de confianza (Baja/Media/Alta)
+• 🔄 Nueva estimación con otras fotos
+• ⚡ Modo directo: sin /estimate, solo envía fotos (/oneshot on)
+• 🎯 Objetivo diario de calorías (/goal 2000)
+• 📊 Resúmenes con /today, /week y /month, agrupados por comida
+• 📤 Exporta tu registro en CSV o JSON con /export
+• 🍳 Define tus horas de desayuno, almuerzo y cena con /meals
+• ⚙️ Zona horaria, idioma y unidades con /settings
+• ❌ Cancela cuando quieras
+
+¿Listo? ¡Envía /estimate para empezar!`,
+
+	// Buttons
+	"button.cancel":      "Cancelar",
+	"button.done":        "Listo",
+	"button.re_estimate": "Volver a estimar",
+	"button.save":        "✅ Guardar",
+	"button.adjust":      "✏️ Ajustar",
+	"button.discard":     "🗑 Descartar",
+	"button.manual_kcal": "Introducir kcal",
+
+	// Photo and text flows
+	"estimate.prompt":            "📸 Envía una foto de comida para estimar sus calorías\n\n¿Varias fotos de una misma comida (o un álbum)? Envíalas todas y pulsa Listo.",
+	"photo.added":                "📸 Foto %d añadida. Envía más fotos de esta comida o pulsa Listo para estimar.",
+	"photo.album":                "📸 Álbum recibido. Envía más fotos de esta comida o pulsa Listo para estimar.",
+	"photo.limit.one":            "Puedes añadir hasta %d foto por comida. Pulsa Listo para estimar.",
+	"photo.limit.other":          "Puedes añadir hasta %d fotos por comida. Pulsa Listo para estimar.",
+	"photo.outside_flow":         "📸 Para estimar las calorías de una foto, envía primero /estimate y después la foto.",
+	"photo.busy":                 "⏳ Todavía estoy analizando tu comida anterior. Vuelve a enviar esta foto cuando llegue su estimación.",
+	"photo.oneshot_tip":          "Consejo: activa el modo directo con /oneshot on y cualquier foto de comida que envíes se estimará al momento.",
+	"log.prompt":                 "✍️ Describe lo que comiste, p. ej. \"2 huevos y una tostada\"",
+	"note.added":                 "📝 Anotado. Ahora envía una foto de tu comida.",
+	"note.added_collecting":      "📝 Anotado. Envía más fotos o pulsa Listo para estimar.",
+	"note.too_long.one":          "La nota es demasiado larga. Usa menos de %d carácter.",
+	"note.too_long.other":        "La nota es demasiado larga. Usa menos de %d caracteres.",
+	"description.too_long.one":   "La descripción es demasiado larga. Usa menos de %d carácter.",
+	"description.too_long.other": "La descripción es demasiado larga. Usa menos de %d caracteres.",
+	"processing.image":           "⏳ Analizando tu foto...",
+	"processing.photos.one":      "⏳ Analizando tu %d foto...",
+	"processing.photos.other":    "⏳ Analizando tus %d fotos...",
+	"processing.description":     "⏳ Analizando tu comida...",
+	"reestimate.image":           "📸 Envía otra foto de comida",
+	"reestimate.description":     "✍️ Envía otra descripción de la comida",
+	"cancel.done":                "Estimación cancelada. Usa /estimate para empezar de nuevo.",
+
+	// Image errors
+	"image.unsupported":     "Formato no compatible. Envía solo imágenes JPEG, PNG o WebP.",
+	"image.too_large":       "La imagen es demasiado grande. Envía una imagen de menos de %d MB.",
+	"image.download_failed": "No se pudo descargar la imagen. Inténtalo de nuevo.",
+	"image.request_failed":  "No se pudo crear la solicitud. Inténtalo de nuevo.",
+	"image.fetch_failed":    "No se pudo obtener la imagen. Inténtalo de nuevo.",
+	"image.read_failed":     "No se pudo leer la imagen. Inténtalo de nuevo.",
+	"image.invalid":         "No se puede leer esta imagen. Envía una foto JPEG, PNG o WebP.",
+
+	// Estimate results
+	"estimate.api_error":     "Error de la API. Inténtalo más tarde.",
+	"estimate.no_food_image": "No se detectó comida en la imagen. Envía una imagen que contenga comida.",
+	"estimate.no_food_text":  "No se reconoció ninguna comida en esa descripción. Describe lo que comiste.",
+	"result.title":           "🍽️ Estimación de calorías",
+	"result.calories":        "Calorías estimadas: %s",
+	"result.macros":          "Macros: %s",
+	"result.confidence":      "Confianza: %s",
+	"result.items":           "Alimentos detectados: %s",
+	"result.no_items":        "Ninguno",
+	"confidence.low":         "Baja",
+	"confidence.medium":      "Media",
+	"confidence.high":        "Alta",
+	"item.portion":           " (%s)",
+	"item.calories":          " — %s",
+	"macros":                 "P %s g · HC %s g · G %s g",
+	"macros.fiber":           " · Fibra %s g",
+	"macros.sugar":           " · Azúcar %s g",
+
+	// Confirmation (Save / Adjust / Discard)
+	"confirm.question":               "¿Guardar esta comida en tu registro?",
+	"confirm.autosave_minutes.one":   " (se guardará automáticamente en %d minuto)",
+	"confirm.autosave_minutes.other": " (se guardará automáticamente en %d minutos)",
+	"confirm.autosave_seconds.one":   " (se guardará automáticamente en %d segundo)",
+	"confirm.autosave_seconds.other": " (se guardará automáticamente en %d segundos)",
+	"confirm.saved":                  "✅ Guardado",
+	"confirm.autosaved":              "💾 Guardado automáticamente",
+	"confirm.saved_calories":         "%s: %s",
+	"confirm.save_failed":            "No se pudo guardar esta comida. Inténtalo más tarde.",
+	"confirm.discarded":              "🗑 Estimación descartada. No se guardó nada.",
+	"confirm.replaced":               "↩️ Tu estimación anterior se reemplazó sin guardarse.",
+	"adjust.prompt":                  "✏️ Ajusta la porción respecto a la estimación original o introduce tú el total de kcal",
+	"adjust.manual_prompt":           "✍️ Envía el total de calorías de esta comida, p. ej. 450",
+	"adjust.manual_range":            "Envía un número entre 1 y %s, p. ej. 450",
+	"adjust.expired":                 "Esta estimación ya no está disponible. Usa /estimate para empezar de nuevo.",
+	"callback.estimating":            "Estimando...",
+	"callback.photo_first":           "Primero envía una foto de tu comida",
+	"callback.send_another":          "Envía otra imagen",
+	"callback.canceled":              "Estimación cancelada",
+	"callback.saved":                 "Guardado",
+	"callback.discarded":             "Descartado",
+	"callback.nothing_save":          "Nada que guardar",
+	"callback.nothing_discard":       "Nada que descartar",
+	"callback.nothing_adjust":        "Nada que ajustar",
+	"callback.nothing_change":        "Nada que cambiar",
+	"callback.unknown_adjust":        "Ajuste desconocido",
+	"callback.unknown":               "Acción desconocida",
+	"callback.outdated":              "Esta estimación ya no está activa",
+	"callback.unknown_meal":          "Comida desconocida",
+	"callback.portion":               "Porción ×%s",
+
+	// Meals
+	"meal.breakfast":      "Desayuno",
+	"meal.lunch":          "Almuerzo",
+	"meal.dinner":         "Cena",
+	"meal.snack":          "Tentempié",
+	"meal.current":        "Comida: %s (pulsa abajo para cambiarla)",
+	"meals.title_default": "🍽️ Horas de las comidas (predeterminadas):",
+	"meals.title":         "🍽️ Tus horas de las comidas:",
+	"meals.snack":         "• Tentempié: cualquier otra hora",
+	"meals.help":          "Las comidas se clasifican según tu zona horaria (/goal). Cambia las horas con p. ej. /meals breakfast=06:00-10:00,lunch=12:00-15:00,dinner=18:00-22:00",
+	"meals.reset_hint":    " o vuelve a las predeterminadas con /meals reset",
+	"meals.invalid":       "Horas de comida no válidas: %s\n\nEjemplo: /meals breakfast=06:00-10:00,lunch=12:00-15:00,dinner=18:00-22:00",
+	"meals.unavailable":   "Las horas de las comidas no están disponibles en este bot.",
+
+	// Goals
+	"goal.today":       "🎯 Hoy: %s / %s",
+	"goal.remaining":   "Restante: %s",
+	"goal.over":        "Superaste el objetivo en %s",
+	"goal.none":        "🎯 Sin objetivo diario (zona horaria: %s)\n\nDefine uno con /goal <kcal>, p. ej. /goal 2000\nOpcionalmente añade tu zona horaria: /goal 2000 Europe/Madrid",
+	"goal.status":      "🎯 Objetivo diario: %s (zona horaria: %s)\n\nCámbialo con /goal <kcal> o elimínalo con /goal off",
+	"goal.range":       "Introduce un objetivo entre 1 y %s kcal, p. ej. /goal 2000",
+	"goal.unavailable": "Los objetivos diarios no están disponibles en este bot.",
+	"goal.load_failed": "No se pudo cargar tu objetivo. Inténtalo de nuevo.",
+	"goal.save_failed": "No se pudo guardar tu objetivo. Inténtalo de nuevo.",
+	"timezone.server":  "hora del servidor",
+	"timezone.unknown": "Zona horaria desconocida. Usa un nombre IANA como Europe/Madrid o America/New_York.",
+
+	// One-shot mode
+	"oneshot.on":          "⚡ El modo directo está activado: cualquier foto de comida que envíes se estima al momento.\n\n¿Varias fotos de una comida? Envíalas como álbum y pulsa Listo.\nDesactívalo con /oneshot off",
+	"oneshot.off":         "📸 El modo directo está desactivado: envía /estimate antes de tus fotos.\n\nActívalo con /oneshot on para estimar cualquier foto de comida al momento.",
+	"oneshot.usage":       "Uso: /oneshot on o /oneshot off",
+	"oneshot.unavailable": "El modo directo no está disponible en este bot.",
+
+	// Summaries
+	"summary.today":         "Hoy",
+	"summary.week":          "Últimos 7 días",
+	"summary.month":         "Últimos 30 días",
+	"summary.total":         "Total: %s",
+	"summary.entries":       "Registros: %d",
+	"summary.average.one":   "Media: %s/día (%d de %d día registrado)",
+	"summary.average.other": "Media: %s/día (%d de %d días registrados)",
+	"summary.goal.one":      "Objetivo: %s — cumplido en %d/%d día registrado",
+	"summary.goal.other":    "Objetivo: %s — cumplido en %d/%d días registrados",
+	"summary.empty":         "No hay comidas registradas en este periodo. Usa /estimate o /log para añadir una.",
+	"summary.by_meal":       "Por comida:",
+	"summary.per_day":       "Por día:",
+	"summary.line":          "• %s: %s (%d)",
+	"summary.unavailable":   "Los resúmenes no están disponibles en este bot.",
+	"summary.load_failed":   "No se pudo cargar tu registro. Inténtalo de nuevo.",
+
+	// Export
+	"export.caption.one":   "📤 Tu registro de calorías: %d entrada",
+	"export.caption.other": "📤 Tu registro de calorías: %d entradas",
+	"export.empty":         "No hay entradas que exportar en este periodo.",
+	"export.usage":         "Uso: /export [csv|ndjson] [desde] [hasta]\nLas fechas son AAAA-MM-DD, p. ej. /export csv 2024-05-01 2024-05-31",
+	"export.unavailable":   "La exportación no está disponible en este bot.",
+	"export.failed":        "No se pudo exportar tu registro. Inténtalo de nuevo.",
+
+	// Settings
+	"settings.title":            "⚙️ Ajustes",
+	"settings.timezone":         "Zona horaria: %s",
+	"settings.language":         "Idioma: %s",
+	"settings.language_default": "%s (predeterminado)",
+	"settings.locale":           "Configuración regional: %s",
+	"settings.locale_default":   "%s (según el idioma)",
+	"settings.units":            "Unidades: %s",
+	"settings.energy":           "Energía: %s",
+	"settings.goal":             "Objetivo diario: %s",
+	"settings.goal_unset":       "Objetivo diario: sin definir (/goal)",
+	"settings.oneshot_on":       "Modo directo: activado",
+	"settings.oneshot_off":      "Modo directo: desactivado",
+	"settings.meals_default":    "Horas de las comidas: predeterminadas (/meals)",
+	"settings.meals":            "Horas de las comidas: %s",
+	"settings.diet":             "Notas dietéticas: %s",
+	"settings.diet_unset":       "Notas dietéticas: ninguna",
+	"settings.saved":            "✅ Ajustes guardados",
+	"settings.usage":            "Uso:\n/settings timezone Europe/Madrid\n/settings language es\n/settings locale es-MX\n/settings units metric|imperial\n/settings energy kcal|kj\n/settings diet vegetariano, sin frutos secos (none para borrar)",
+	"settings.unknown_language": "Idioma desconocido. Usa un código de dos letras como en, es, ru o zh.",
+	"settings.unknown_locale":   "Configuración regional desconocida. Usa una etiqueta como en-US, es-MX o pt-BR.",
+	"settings.invalid_units":    "Las unidades deben ser metric o imperial.",
+	"settings.invalid_energy":   "La energía debe ser kcal o kj.",
+	"settings.invalid_diet":     "Las notas dietéticas admiten como máximo %d caracteres.",
+	"settings.unavailable":      "Los ajustes no están disponibles en este bot.",
+	"settings.load_failed":      "No se pudieron cargar tus ajustes. Inténtalo de nuevo.",
+	"settings.save_failed":      "No se pudieron guardar tus ajustes. Inténtalo de nuevo.",
+	"units.metric":              "métrico",
+	"units.imperial":            "imperial",
+	"energy_unit.kcal":          "kcal",
+	"energy_unit.kj":            "kJ",
+
+	// Energy and portion sizes in the user's units
+	"energy.kcal":    "%s kcal",
+	"energy.kj":      "%s kJ",
+	"portion.grams":  "%s g",
+	"portion.ounces": "%s oz",
+}
+
+// esWeekdays and esMonths are the CLDR abbreviated names used by ShortDate
+var (
+	esWeekdays = [...]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"}
+	esMonths   = [...]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"}
+)
@@ -0,0 +1,298 @@
+// Package i18n provides the bot's message catalogs with plural rules and locale-aware number formatting
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+)
+
+// DefaultLanguage is used for users whose language has no catalog
+const DefaultLanguage = "en"
+
+// Messages maps message keys to fmt format strings
+// Plural messages use one key per CLDR category, e.g. "photos.one", "photos.few", "photos.other"
+type Messages map[string]string
+
+// language holds a catalog with the rules for formatting its plurals, numbers and dates
+type language struct {
+	name     string // English name, used in estimation prompts
+	messages Messages
+	plural   func(n int) string
+	forms    []string // Plural categories plural returns
+
+	decimal     string // Decimal separator
+	group       string // Thousands separator
+	minGrouping int    // Smallest number of integer digits that gets grouped (CLDR minimumGroupingDigits + 3)
+
+	shortDate func(t time.Time) string // Day heading in summaries
+}
+
+// languages are the supported catalogs keyed by language subtag
+var languages = map[string]*language{
+	"en": {
+		name:        "English",
+		messages:    en,
+		plural:      pluralOneOther,
+		forms:       []string{"one", "other"},
+		decimal:     ".",
+		group:       ",",
+		minGrouping: 4,
+		shortDate:   func(t time.Time) string { return t.Format("Mon Jan 2") },
+	},
+	"es": {
+		name:        "Spanish",
+		messages:    es,
+		plural:      pluralOneOther,
+		forms:       []string{"one", "other"},
+		decimal:     ",",
+		group:       ".",
+		minGrouping: 5,
+		shortDate: func(t time.Time) string {
+			return fmt.Sprintf("%s, %d %s", esWeekdays[t.Weekday()], t.Day(), esMonths[t.Month()-1])
+		},
+	},
+	"ru": {
+		name:        "Russian",
+		messages:    ru,
+		plural:      pluralRussian,
+		forms:       []string{"one", "few", "many"},
+		decimal:     ",",
+		group:       "\u00a0",
+		minGrouping: 4,
+		shortDate: func(t time.Time) string {
+			return fmt.Sprintf("%s, %d %s", ruWeekdays[t.Weekday()], t.Day(), ruMonths[t.Month()-1])
+		},
+	},
+	"zh": {
+		name:        "Chinese",
+		messages:    zh,
+		plural:      pluralOther,
+		forms:       []string{"other"},
+		decimal:     ".",
+		group:       ",",
+		minGrouping: 4,
+		shortDate: func(t time.Time) string {
+			return fmt.Sprintf("%d月%d日%s", t.Month(), t.Day(), zhWeekdays[t.Weekday()])
+		},
+	},
+}
+
+// latinAmericanSpanish lists es regions that write numbers like English (1,234.5)
+var latinAmericanSpanish = map[string]bool{"MX": true, "US": true, "419": true}
+
+// Printer formats messages, plurals, numbers and units for one user's language and locale
+type Printer struct {
+	code string
+	lang *language
+
+	decimal string
+	group   string
+
+	units internalmodels.DisplayUnits // Energy and portion units (zero value is kcal and grams)
+}
+
+// New returns a Printer for a language subtag (e.g. "es") and optional locale (e.g. "es-MX")
+// Languages without a catalog fall back to English
+func New(languageCode, locale string) *Printer {
+	code := strings.ToLower(languageCode)
+	lang, ok := languages[code]
+	if !ok {
+		code, lang = DefaultLanguage, languages[DefaultLanguage]
+	}
+
+	p := &Printer{code: code, lang: lang, decimal: lang.decimal, group: lang.group}
+	if _, region, _ := strings.Cut(locale, "-"); code == "es" && latinAmericanSpanish[region] {
+		p.decimal, p.group = ".", ","
+	}
+	return p
+}
+
+// Missing lists the English catalog keys a language lacks, including plural forms its rules need
+func Missing(languageCode string) []string {
+	lang, ok := languages[strings.ToLower(languageCode)]
+	if !ok {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	var missing []string
+	for key := range en {
+		keys := []string{key}
+		if base, form := pluralBase(key); form != "" {
+			keys = keys[:0]
+			for _, form := range lang.forms {
+				keys = append(keys, base+"."+form)
+			}
+		}
+		for _, k := range keys {
+			if _, ok := lang.messages[k]; !ok && !seen[k] {
+				seen[k] = true
+				missing = append(missing, k)
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// pluralBase splits a plural message key into its base and CLDR category ("" if not plural)
+func pluralBase(key string) (string, string) {
+	i := strings.LastIndex(key, ".")
+	if i < 0 {
+		return key, ""
+	}
+	switch form := key[i+1:]; form {
+	case "zero", "one", "two", "few", "many", "other":
+		return key[:i], form
+	}
+	return key, ""
+}
+
+// Language returns the catalog language in use, e.g. "es" (never empty)
+func (p *Printer) Language() string {
+	return p.code
+}
+
+// LanguageName returns the English name of the catalog language, e.g. "Spanish"
+func (p *Printer) LanguageName() string {
+	return p.lang.name
+}
+
+// T formats the message for key with args, falling back to English and then to the key itself
+func (p *Printer) T(key string, args ...interface{}) string {
+	format, ok := p.lang.messages[key]
+	if !ok {
+		format, ok = en[key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// N formats the plural form of key selected by count, e.g. N("photos", 3, 3)
+// count only picks the form; pass it in args as well to print it
+func (p *Printer) N(key string, count int, args ...interface{}) string {
+	if plural := key + "." + p.lang.plural(count); p.has(plural) {
+		return p.T(plural, args...)
+	}
+	return p.T(key+".other", args...)
+}
+
+// has reports whether key exists in the printer's catalog
+func (p *Printer) has(key string) bool {
+	_, ok := p.lang.messages[key]
+	return ok
+}
+
+// Number formats an integer with the locale's thousands separator, e.g. "1,234" in English or "1 234" in Russian
+func (p *Printer) Number(n int) string {
+	return p.group3(strconv.Itoa(n))
+}
+
+// Decimal formats f with the locale's separators and the given number of fraction digits
+// Negative digits use the fewest digits needed, e.g. Decimal(1.5, -1) is "1,5" in Spanish
+func (p *Printer) Decimal(f float64, digits int) string {
+	integer, fraction, hasFraction := strings.Cut(strconv.FormatFloat(f, 'f', digits, 64), ".")
+	integer = p.group3(integer)
+	if !hasFraction {
+		return integer
+	}
+	return integer + p.decimal + fraction
+}
+
+// group3 inserts the thousands separator into a run of digits with an optional minus sign
+func (p *Printer) group3(digits string) string {
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	if len(digits) < p.lang.minGrouping {
+		return sign + digits
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(p.group)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// WithUnits returns a copy of the printer that shows energy and portion sizes in units
+func (p *Printer) WithUnits(units internalmodels.DisplayUnits) *Printer {
+	c := *p
+	c.units = units
+	return &c
+}
+
+// Units returns the units the printer shows energy and portion sizes in
+func (p *Printer) Units() internalmodels.DisplayUnits {
+	return p.units
+}
+
+// Energy formats a kcal amount with the printer's energy unit, e.g. "2,000 kcal" or "8,368 kJ"
+func (p *Printer) Energy(kcal int) string {
+	if p.units.Energy == internalmodels.EnergyKilojoules {
+		return p.T("energy.kj", p.EnergyValue(kcal))
+	}
+	return p.T("energy.kcal", p.EnergyValue(kcal))
+}
+
+// EnergyValue formats a kcal amount converted to the printer's energy unit, without the unit
+func (p *Printer) EnergyValue(kcal int) string {
+	return p.Number(p.units.ConvertEnergy(kcal))
+}
+
+// Portion formats a weight in grams in the printer's unit system, e.g. "200g" or "7.1 oz"
+func (p *Printer) Portion(grams float64) string {
+	amount, unit := p.units.ConvertPortion(grams)
+	if unit == "oz" {
+		return p.T("portion.ounces", p.Decimal(amount, -1))
+	}
+	return p.T("portion.grams", p.Decimal(amount, 0))
+}
+
+// ShortDate formats a day heading, e.g. "Mon Jan 2", "lun, 2 ene", "пн, 2 янв." or "1月2日周一"
+func (p *Printer) ShortDate(t time.Time) string {
+	return p.lang.shortDate(t)
+}
+
+// pluralOneOther is the CLDR rule for English and Spanish integers
+func pluralOneOther(n int) string {
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+// pluralOther is the CLDR rule for languages without plural forms (Chinese)
+func pluralOther(int) string {
+	return "other"
+}
+
+// pluralRussian is the CLDR rule for Russian integers: 1, 21 → one; 2-4, 22-24 → few; 0, 5-20, 25 → many
+func pluralRussian(n int) string {
+	if n < 0 {
+		n = -n
+	}
+	switch mod10, mod100 := n%10, n%100; {
+	case mod10 == 1 && mod100 != 11:
+		return "one"
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return "few"
+	default:
+		return "many"
+	}
+}
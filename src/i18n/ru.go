@@ -0,0 +1,235 @@
+package i18n
+
+// ru is the Russian catalog
+var ru = Messages{
+	// /start
+	"welcome": `👋 Добро пожаловать в Calorie Estimation Bot!
+
+Я помогаю оценить калорийность еды по фотографиям.
+
+**Как пользоваться:**
+1. Отправьте команду /estimate
+2. Загрузите одно или несколько фото блюда и нажмите «Готово»
+3. Получите оценку калорий с уровнем уверенности
+4. Нажмите «Сохранить», чтобы добавить её в дневник, или сначала «Изменить» порцию
+
+Нет фото? Отправьте /log с описанием, например /log 2 яйца и тост
+
+**Возможности:**
+• 🍽️ Мгновенная оценка калорий
+• 📊 Уровень уверенности (низкая/средняя/высокая)
+• 🔄 Повторная оценка по другим фото
+• ⚡ Быстрый режим: без /estimate, просто присылайте фото (/oneshot on)
+• 🎯 Дневная цель по калориям (/goal 2000)
+• 📊 Сводки /today, /week и /month с разбивкой по приёмам пищи
+• 📤 Экспорт журнала в CSV или JSON командой /export
+• 🍳 Время завтрака, обеда и ужина задаётся командой /meals
+• ⚙️ Часовой пояс, язык и единицы измерения в /settings
+• ❌ Отмена в любой момент
+
+Готовы? Отправьте /estimate, чтобы начать!`,
+
+	// Buttons
+	"button.cancel":      "Отмена",
+	"button.done":        "Готово",
+	"button.re_estimate": "Оценить заново",
+	"button.save":        "✅ Сохранить",
+	"button.adjust":      "✏️ Изменить",
+	"button.discard":     "🗑 Удалить",
+	"button.manual_kcal": "Ввести ккал",
+
+	// Photo and text flows
+	"estimate.prompt":           "📸 Отправьте фото еды для оценки калорий\n\nНесколько фото одного блюда (или альбом)? Отправьте их все и нажмите «Готово».",
+	"photo.added":               "📸 Фото %d добавлено. Отправьте ещё фото этого блюда или нажмите «Готово» для оценки.",
+	"photo.album":               "📸 Альбом получен. Отправьте ещё фото этого блюда или нажмите «Готово» для оценки.",
+	"photo.limit.one":           "Можно добавить не больше %d фото на приём пищи. Нажмите «Готово» для оценки.",
+	"photo.limit.few":           "Можно добавить не больше %d фото на приём пищи. Нажмите «Готово» для оценки.",
+	"photo.limit.many":          "Можно добавить не больше %d фото на приём пищи. Нажмите «Готово» для оценки.",
+	"photo.outside_flow":        "📸 Чтобы оценить калории по фото, сначала отправьте /estimate, а затем фото.",
+	"photo.busy":                "⏳ Ещё анализирую предыдущий приём пищи. Отправьте это фото снова, когда придёт оценка.",
+	"photo.oneshot_tip":         "Совет: включите быстрый режим командой /oneshot on, и любое фото еды будет оцениваться сразу.",
+	"log.prompt":                "✍️ Опишите, что вы съели, например «2 яйца и тост»",
+	"note.added":                "📝 Записал. Теперь отправьте фото блюда.",
+	"note.added_collecting":     "📝 Записал. Отправьте ещё фото или нажмите «Готово» для оценки.",
+	"note.too_long.one":         "Заметка слишком длинная. Уложитесь в %d символ.",
+	"note.too_long.few":         "Заметка слишком длинная. Уложитесь в %d символа.",
+	"note.too_long.many":        "Заметка слишком длинная. Уложитесь в %d символов.",
+	"description.too_long.one":  "Описание слишком длинное. Уложитесь в %d символ.",
+	"description.too_long.few":  "Описание слишком длинное. Уложитесь в %d символа.",
+	"description.too_long.many": "Описание слишком длинное. Уложитесь в %d символов.",
+	"processing.image":          "⏳ Анализирую фото...",
+	"processing.photos.one":     "⏳ Анализирую %d фотографию...",
+	"processing.photos.few":     "⏳ Анализирую %d фотографии...",
+	"processing.photos.many":    "⏳ Анализирую %d фотографий...",
+	"processing.description":    "⏳ Анализирую описание...",
+	"reestimate.image":          "📸 Отправьте другое фото еды",
+	"reestimate.description":    "✍️ Отправьте другое описание блюда",
+	"cancel.done":               "Оценка отменена. Отправьте /estimate, чтобы начать заново.",
+
+	// Image errors
+	"image.unsupported":     "Неподдерживаемый формат. Отправляйте только изображения JPEG, PNG или WebP.",
+	"image.too_large":       "Изображение слишком большое. Отправьте изображение меньше %d МБ.",
+	"image.download_failed": "Не удалось скачать изображение. Попробуйте ещё раз.",
+	"image.request_failed":  "Не удалось создать запрос. Попробуйте ещё раз.",
+	"image.fetch_failed":    "Не удалось получить изображение. Попробуйте ещё раз.",
+	"image.read_failed":     "Не удалось прочитать изображение. Попробуйте ещё раз.",
+	"image.invalid":         "Не удаётся открыть это изображение. Отправьте фото в формате JPEG, PNG или WebP.",
+
+	// Estimate results
+	"estimate.api_error":     "Ошибка API. Попробуйте позже.",
+	"estimate.no_food_image": "На изображении не найдено еды. Отправьте фото с едой.",
+	"estimate.no_food_text":  "Не удалось распознать еду в описании. Опишите, что вы съели.",
+	"result.title":           "🍽️ Оценка калорий",
+	"result.calories":        "Калорийность: %s",
+	"result.macros":          "БЖУ: %s",
+	"result.confidence":      "Уверенность: %s",
+	"result.items":           "Найденные продукты: %s",
+	"result.no_items":        "Не найдено",
+	"confidence.low":         "Низкая",
+	"confidence.medium":      "Средняя",
+	"confidence.high":        "Высокая",
+	"item.portion":           " (%s)",
+	"item.calories":          " — %s",
+	"macros":                 "Б %s г · У %s г · Ж %s г",
+	"macros.fiber":           " · Клетчатка %s г",
+	"macros.sugar":           " · Сахар %s г",
+
+	// Confirmation (Save / Adjust / Discard)
+	"confirm.question":              "Сохранить этот приём пищи в дневник?",
+	"confirm.autosave_minutes.one":  " (сохранится автоматически через %d минуту)",
+	"confirm.autosave_minutes.few":  " (сохранится автоматически через %d минуты)",
+	"confirm.autosave_minutes.many": " (сохранится автоматически через %d минут)",
+	"confirm.autosave_seconds.one":  " (сохранится автоматически через %d секунду)",
+	"confirm.autosave_seconds.few":  " (сохранится автоматически через %d секунды)",
+	"confirm.autosave_seconds.many": " (сохранится автоматически через %d секунд)",
+	"confirm.saved":                 "✅ Сохранено",
+	"confirm.autosaved":             "💾 Сохранено автоматически",
+	"confirm.saved_calories":        "%s: %s",
+	"confirm.save_failed":           "Не удалось сохранить приём пищи. Попробуйте позже.",
+	"confirm.discarded":             "🗑 Оценка удалена. Ничего не сохранено.",
+	"confirm.replaced":              "↩️ Предыдущая оценка заменена без сохранения.",
+	"adjust.prompt":                 "✏️ Измените порцию относительно исходной оценки или введите сумму ккал вручную",
+	"adjust.manual_prompt":          "✍️ Отправьте общую калорийность блюда, например 450",
+	"adjust.manual_range":           "Отправьте число от 1 до %s, например 450",
+	"adjust.expired":                "Эта оценка больше недоступна. Отправьте /estimate, чтобы начать заново.",
+	"callback.estimating":           "Оцениваю...",
+	"callback.photo_first":          "Сначала отправьте фото блюда",
+	"callback.send_another":         "Отправьте другое изображение",
+	"callback.canceled":             "Оценка отменена",
+	"callback.saved":                "Сохранено",
+	"callback.discarded":            "Удалено",
+	"callback.nothing_save":         "Нечего сохранять",
+	"callback.nothing_discard":      "Нечего удалять",
+	"callback.nothing_adjust":       "Нечего изменять",
+	"callback.nothing_change":       "Нечего менять",
+	"callback.unknown_adjust":       "Неизвестное изменение",
+	"callback.unknown":              "Неизвестное действие",
+	"callback.outdated":             "Эта оценка больше не активна",
+	"callback.unknown_meal":         "Неизвестный приём пищи",
+	"callback.portion":              "Порция ×%s",
+
+	// Meals
+	"meal.breakfast":      "Завтрак",
+	"meal.lunch":          "Обед",
+	"meal.dinner":         "Ужин",
+	"meal.snack":          "Перекус",
+	"meal.current":        "Приём пищи: %s (нажмите ниже, чтобы изменить)",
+	"meals.title_default": "🍽️ Время приёмов пищи (по умолчанию):",
+	"meals.title":         "🍽️ Ваше время приёмов пищи:",
+	"meals.snack":         "• Перекус: в любое другое время",
+	"meals.help":          "Приёмы пищи определяются по вашему часовому поясу (/goal). Изменить время можно так: /meals breakfast=06:00-10:00,lunch=12:00-15:00,dinner=18:00-22:00",
+	"meals.reset_hint":    " или вернуть значения по умолчанию: /meals reset",
+	"meals.invalid":       "Неверное время приёмов пищи: %s\n\nПример: /meals breakfast=06:00-10:00,lunch=12:00-15:00,dinner=18:00-22:00",
+	"meals.unavailable":   "Время приёмов пищи недоступно в этом боте.",
+
+	// Goals
+	"goal.today":       "🎯 Сегодня: %s / %s",
+	"goal.remaining":   "Осталось: %s",
+	"goal.over":        "Цель превышена на %s",
+	"goal.none":        "🎯 Дневная цель не задана (часовой пояс: %s)\n\nЗадайте её командой /goal <ккал>, например /goal 2000\nМожно указать и часовой пояс: /goal 2000 Europe/Moscow",
+	"goal.status":      "🎯 Дневная цель: %s (часовой пояс: %s)\n\nИзмените её командой /goal <ккал> или отключите: /goal off",
+	"goal.range":       "Введите цель от 1 до %s ккал, например /goal 2000",
+	"goal.unavailable": "Дневные цели недоступны в этом боте.",
+	"goal.load_failed": "Не удалось загрузить вашу цель. Попробуйте ещё раз.",
+	"goal.save_failed": "Не удалось сохранить вашу цель. Попробуйте ещё раз.",
+	"timezone.server":  "время сервера",
+	"timezone.unknown": "Неизвестный часовой пояс. Укажите имя IANA, например Europe/Moscow или Asia/Yekaterinburg.",
+
+	// One-shot mode
+	"oneshot.on":          "⚡ Быстрый режим включён: любое фото еды оценивается сразу.\n\nНесколько фото одного блюда? Отправьте их альбомом и нажмите «Готово».\nОтключить: /oneshot off",
+	"oneshot.off":         "📸 Быстрый режим выключен: отправляйте /estimate перед фото.\n\nВключите его командой /oneshot on, чтобы любое фото еды оценивалось сразу.",
+	"oneshot.usage":       "Использование: /oneshot on или /oneshot off",
+	"oneshot.unavailable": "Быстрый режим недоступен в этом боте.",
+
+	// Summaries
+	"summary.today":        "Сегодня",
+	"summary.week":         "Последние 7 дней",
+	"summary.month":        "Последние 30 дней",
+	"summary.total":        "Всего: %s",
+	"summary.entries":      "Записей: %d",
+	"summary.average.one":  "В среднем: %s/день (записи за %d из %d дня)",
+	"summary.average.few":  "В среднем: %s/день (записи за %d из %d дней)",
+	"summary.average.many": "В среднем: %s/день (записи за %d из %d дней)",
+	"summary.goal.one":     "Цель: %s — в пределах цели %d из %d дня с записями",
+	"summary.goal.few":     "Цель: %s — в пределах цели %d из %d дней с записями",
+	"summary.goal.many":    "Цель: %s — в пределах цели %d из %d дней с записями",
+	"summary.empty":        "За этот период нет записей. Добавьте приём пищи через /estimate или /log.",
+	"summary.by_meal":      "По приёмам пищи:",
+	"summary.per_day":      "По дням:",
+	"summary.line":         "• %s: %s (%d)",
+	"summary.unavailable":  "Сводки недоступны в этом боте.",
+	"summary.load_failed":  "Не удалось загрузить ваши записи. Попробуйте ещё раз.",
+
+	// Export
+	"export.caption.one":  "📤 Ваш журнал калорий: %d запись",
+	"export.caption.few":  "📤 Ваш журнал калорий: %d записи",
+	"export.caption.many": "📤 Ваш журнал калорий: %d записей",
+	"export.empty":        "За этот период нет записей для экспорта.",
+	"export.usage":        "Использование: /export [csv|ndjson] [с] [по]\nДаты в формате ГГГГ-ММ-ДД, например /export csv 2024-05-01 2024-05-31",
+	"export.unavailable":  "Экспорт недоступен в этом боте.",
+	"export.failed":       "Не удалось экспортировать журнал. Попробуйте ещё раз.",
+
+	// Settings
+	"settings.title":            "⚙️ Настройки",
+	"settings.timezone":         "Часовой пояс: %s",
+	"settings.language":         "Язык: %s",
+	"settings.language_default": "%s (по умолчанию)",
+	"settings.locale":           "Региональный формат: %s",
+	"settings.locale_default":   "%s (по языку)",
+	"settings.units":            "Единицы: %s",
+	"settings.energy":           "Энергия: %s",
+	"settings.goal":             "Дневная цель: %s",
+	"settings.goal_unset":       "Дневная цель: не задана (/goal)",
+	"settings.oneshot_on":       "Быстрый режим: включён",
+	"settings.oneshot_off":      "Быстрый режим: выключен",
+	"settings.meals_default":    "Время приёмов пищи: по умолчанию (/meals)",
+	"settings.meals":            "Время приёмов пищи: %s",
+	"settings.diet":             "Особенности питания: %s",
+	"settings.diet_unset":       "Особенности питания: нет",
+	"settings.saved":            "✅ Настройки сохранены",
+	"settings.usage":            "Использование:\n/settings timezone Europe/Moscow\n/settings language ru\n/settings locale ru-RU\n/settings units metric|imperial\n/settings energy kcal|kj\n/settings diet вегетарианец, без орехов (none — очистить)",
+	"settings.unknown_language": "Неизвестный язык. Укажите двухбуквенный код, например en, es, ru или zh.",
+	"settings.unknown_locale":   "Неизвестный региональный формат. Укажите тег, например ru-RU, en-US или es-MX.",
+	"settings.invalid_units":    "Единицы должны быть metric или imperial.",
+	"settings.invalid_energy":   "Энергия должна быть kcal или kj.",
+	"settings.invalid_diet":     "Особенности питания — не более %d символов.",
+	"settings.unavailable":      "Настройки недоступны в этом боте.",
+	"settings.load_failed":      "Не удалось загрузить настройки. Попробуйте ещё раз.",
+	"settings.save_failed":      "Не удалось сохранить настройки. Попробуйте ещё раз.",
+	"units.metric":              "метрические",
+	"units.imperial":            "имперские",
+	"energy_unit.kcal":          "ккал",
+	"energy_unit.kj":            "кДж",
+
+	// Energy and portion sizes in the user's units
+	"energy.kcal":    "%s ккал",
+	"energy.kj":      "%s кДж",
+	"portion.grams":  "%s г",
+	"portion.ounces": "%s унц.",
+}
+
+// ruWeekdays and ruMonths are the CLDR abbreviated names used by ShortDate (months in the genitive case)
+var (
+	ruWeekdays = [...]string{"вс", "пн", "вт", "ср", "чт", "пт", "сб"}
+	ruMonths   = [...]string{"янв.", "февр.", "мар.", "апр.", "мая", "июн.", "июл.", "авг.", "сент.", "окт.", "нояб.", "дек."}
+)
@@ -0,0 +1,214 @@
+package i18n
+
+// zh is the Simplified Chinese catalog
+var zh = Messages{
+	// /start
+	"welcome": `👋 欢迎使用 Calorie Estimation Bot！
+
+我可以通过分析照片帮你估算食物的热量。
+
+**使用方法：**
+1. 发送 /estimate 命令
+2. 上传一张或多张餐食照片，然后点击“完成”
+3. 获取带可信度的热量估算
+4. 点击“保存”记入日志，或先“调整”份量
+
+没有照片？发送 /log 加上描述，例如 /log 两个鸡蛋和一片吐司
+
+**功能：**
+• 🍽️ 即时热量估算
+• 📊 可信度提示（低/中/高）
+• 🔄 用其他照片重新估算
+• ⚡ 快捷模式：无需 /estimate，直接发送照片（/oneshot on）
+• 🎯 每日热量目标（/goal 2000）
+• 📊 /today、/week 和 /month 汇总，按餐次分组
+• 📤 用 /export 将日志导出为 CSV 或 JSON
+• 🍳 用 /meals 设置早餐、午餐和晚餐时间
+• ⚙️ 用 /settings 设置时区、语言和单位
+• ❌ 随时取消
+
+准备好了吗？发送 /estimate 开始吧！`,
+
+	// Buttons
+	"button.cancel":      "取消",
+	"button.done":        "完成",
+	"button.re_estimate": "重新估算",
+	"button.save":        "✅ 保存",
+	"button.adjust":      "✏️ 调整",
+	"button.discard":     "🗑 放弃",
+	"button.manual_kcal": "输入千卡",
+
+	// Photo and text flows
+	"estimate.prompt":            "📸 请发送一张食物照片来估算热量\n\n同一餐有多张照片（或相册）？全部发送后点击“完成”。",
+	"photo.added":                "📸 已添加第 %d 张照片。继续发送这餐的照片，或点击“完成”开始估算。",
+	"photo.album":                "📸 已收到相册。继续发送这餐的照片，或点击“完成”开始估算。",
+	"photo.limit.other":          "每餐最多可添加 %d 张照片。点击“完成”开始估算。",
+	"photo.outside_flow":         "📸 要通过照片估算热量，请先发送 /estimate，再发送照片。",
+	"photo.busy":                 "⏳ 仍在分析你上一餐的照片。估算结果出来后请重新发送这张照片。",
+	"photo.oneshot_tip":          "提示：用 /oneshot on 开启快捷模式后，发送的任何食物照片都会立即估算。",
+	"log.prompt":                 "✍️ 描述你吃了什么，例如“两个鸡蛋和一片吐司”",
+	"note.added":                 "📝 已记下。现在请发送餐食照片。",
+	"note.added_collecting":      "📝 已记下。继续发送照片，或点击“完成”开始估算。",
+	"note.too_long.other":        "备注太长了。请控制在 %d 个字符以内。",
+	"description.too_long.other": "描述太长了。请控制在 %d 个字符以内。",
+	"processing.image":           "⏳ 正在分析你的照片...",
+	"processing.photos.other":    "⏳ 正在分析你的 %d 张照片...",
+	"processing.description":     "⏳ 正在分析你的餐食...",
+	"reestimate.image":           "📸 请再发送一张食物照片",
+	"reestimate.description":     "✍️ 请再发送一段餐食描述",
+	"cancel.done":                "估算已取消。发送 /estimate 重新开始。",
+
+	// Image errors
+	"image.unsupported":     "不支持的格式。请只发送 JPEG、PNG 或 WebP 图片。",
+	"image.too_large":       "图片太大。请发送小于 %d MB 的图片。",
+	"image.download_failed": "图片下载失败。请重试。",
+	"image.request_failed":  "无法创建请求。请重试。",
+	"image.fetch_failed":    "无法获取图片。请重试。",
+	"image.read_failed":     "无法读取图片。请重试。",
+	"image.invalid":         "无法识别这张图片。请发送 JPEG、PNG 或 WebP 照片。",
+
+	// Estimate results
+	"estimate.api_error":     "API 出错。请稍后再试。",
+	"estimate.no_food_image": "图片中未检测到食物。请发送包含食物的图片。",
+	"estimate.no_food_text":  "未能从描述中识别出食物。请描述你吃了什么。",
+	"result.title":           "🍽️ 热量估算",
+	"result.calories":        "估算热量：%s",
+	"result.macros":          "营养素：%s",
+	"result.confidence":      "可信度：%s",
+	"result.items":           "识别到的食物：%s",
+	"result.no_items":        "未识别到",
+	"confidence.low":         "低",
+	"confidence.medium":      "中",
+	"confidence.high":        "高",
+	"item.portion":           "（%s）",
+	"item.calories":          " — %s",
+	"macros":                 "蛋白质 %s克 · 碳水 %s克 · 脂肪 %s克",
+	"macros.fiber":           " · 膳食纤维 %s克",
+	"macros.sugar":           " · 糖 %s克",
+
+	// Confirmation (Save / Adjust / Discard)
+	"confirm.question":               "将这餐保存到日志吗？",
+	"confirm.autosave_minutes.other": "（%d 分钟后自动保存）",
+	"confirm.autosave_seconds.other": "（%d 秒后自动保存）",
+	"confirm.saved":                  "✅ 已保存",
+	"confirm.autosaved":              "💾 已自动保存",
+	"confirm.saved_calories":         "%s：%s",
+	"confirm.save_failed":            "保存失败。请稍后再试。",
+	"confirm.discarded":              "🗑 已放弃本次估算，未保存任何内容。",
+	"confirm.replaced":               "↩️ 上一次估算已被替换，未保存。",
+	"adjust.prompt":                  "✏️ 按原始估算调整份量，或直接输入总千卡数",
+	"adjust.manual_prompt":           "✍️ 发送这餐的总热量，例如 450",
+	"adjust.manual_range":            "请发送 1 到 %s 之间的数字，例如 450",
+	"adjust.expired":                 "这次估算已失效。发送 /estimate 重新开始。",
+	"callback.estimating":            "正在估算...",
+	"callback.photo_first":           "请先发送餐食照片",
+	"callback.send_another":          "请发送另一张图片",
+	"callback.canceled":              "估算已取消",
+	"callback.saved":                 "已保存",
+	"callback.discarded":             "已放弃",
+	"callback.nothing_save":          "没有可保存的内容",
+	"callback.nothing_discard":       "没有可放弃的内容",
+	"callback.nothing_adjust":        "没有可调整的内容",
+	"callback.nothing_change":        "没有可更改的内容",
+	"callback.unknown_adjust":        "未知的调整",
+	"callback.unknown":               "未知操作",
+	"callback.outdated":              "此估算已失效",
+	"callback.unknown_meal":          "未知的餐次",
+	"callback.portion":               "份量 ×%s",
+
+	// Meals
+	"meal.breakfast":      "早餐",
+	"meal.lunch":          "午餐",
+	"meal.dinner":         "晚餐",
+	"meal.snack":          "加餐",
+	"meal.current":        "餐次：%s（点击下方按钮更改）",
+	"meals.title_default": "🍽️ 用餐时间（默认）：",
+	"meals.title":         "🍽️ 你的用餐时间：",
+	"meals.snack":         "• 加餐：其他任何时间",
+	"meals.help":          "餐次按你的时区（/goal）划分。修改时间示例：/meals breakfast=06:00-10:00,lunch=12:00-15:00,dinner=18:00-22:00",
+	"meals.reset_hint":    "，或用 /meals reset 恢复默认",
+	"meals.invalid":       "用餐时间无效：%s\n\n示例：/meals breakfast=06:00-10:00,lunch=12:00-15:00,dinner=18:00-22:00",
+	"meals.unavailable":   "此机器人不支持用餐时间设置。",
+
+	// Goals
+	"goal.today":       "🎯 今天：%s / %s",
+	"goal.remaining":   "剩余：%s",
+	"goal.over":        "超出目标 %s",
+	"goal.none":        "🎯 尚未设置每日目标（时区：%s）\n\n用 /goal <千卡> 设置，例如 /goal 2000\n也可以加上时区：/goal 2000 Asia/Shanghai",
+	"goal.status":      "🎯 每日目标：%s（时区：%s）\n\n用 /goal <千卡> 修改，或用 /goal off 取消",
+	"goal.range":       "请输入 1 到 %s 千卡之间的目标，例如 /goal 2000",
+	"goal.unavailable": "此机器人不支持每日目标。",
+	"goal.load_failed": "无法加载你的目标。请重试。",
+	"goal.save_failed": "无法保存你的目标。请重试。",
+	"timezone.server":  "服务器时间",
+	"timezone.unknown": "未知时区。请使用 IANA 名称，例如 Asia/Shanghai 或 America/New_York。",
+
+	// One-shot mode
+	"oneshot.on":          "⚡ 快捷模式已开启：发送的任何食物照片都会立即估算。\n\n同一餐有多张照片？以相册形式发送并点击“完成”。\n用 /oneshot off 关闭",
+	"oneshot.off":         "📸 快捷模式已关闭：请先发送 /estimate 再发照片。\n\n用 /oneshot on 开启后，任何食物照片都会立即估算。",
+	"oneshot.usage":       "用法：/oneshot on 或 /oneshot off",
+	"oneshot.unavailable": "此机器人不支持快捷模式。",
+
+	// Summaries
+	"summary.today":         "今天",
+	"summary.week":          "最近 7 天",
+	"summary.month":         "最近 30 天",
+	"summary.total":         "总计：%s",
+	"summary.entries":       "记录：%d 条",
+	"summary.average.other": "平均：%s/天（%d/%d 天有记录）",
+	"summary.goal.other":    "目标：%s — 有记录的 %d/%d 天达标",
+	"summary.empty":         "这段时间没有记录。用 /estimate 或 /log 添加一餐。",
+	"summary.by_meal":       "按餐次：",
+	"summary.per_day":       "按天：",
+	"summary.line":          "• %s：%s（%d）",
+	"summary.unavailable":   "此机器人不支持汇总。",
+	"summary.load_failed":   "无法加载你的记录。请重试。",
+
+	// Export
+	"export.caption.other": "📤 你的热量日志：%d 条记录",
+	"export.empty":         "这段时间没有可导出的记录。",
+	"export.usage":         "用法：/export [csv|ndjson] [开始日期] [结束日期]\n日期格式为 YYYY-MM-DD，例如 /export csv 2024-05-01 2024-05-31",
+	"export.unavailable":   "此机器人不支持导出。",
+	"export.failed":        "无法导出你的日志。请重试。",
+
+	// Settings
+	"settings.title":            "⚙️ 设置",
+	"settings.timezone":         "时区：%s",
+	"settings.language":         "语言：%s",
+	"settings.language_default": "%s（默认）",
+	"settings.locale":           "地区格式：%s",
+	"settings.locale_default":   "%s（跟随语言）",
+	"settings.units":            "单位：%s",
+	"settings.energy":           "能量单位：%s",
+	"settings.goal":             "每日目标：%s",
+	"settings.goal_unset":       "每日目标：未设置（/goal）",
+	"settings.oneshot_on":       "快捷模式：开",
+	"settings.oneshot_off":      "快捷模式：关",
+	"settings.meals_default":    "用餐时间：默认（/meals）",
+	"settings.meals":            "用餐时间：%s",
+	"settings.diet":             "饮食备注：%s",
+	"settings.diet_unset":       "饮食备注：无",
+	"settings.saved":            "✅ 设置已保存",
+	"settings.usage":            "用法：\n/settings timezone Asia/Shanghai\n/settings language zh\n/settings locale zh-CN\n/settings units metric|imperial\n/settings energy kcal|kj\n/settings diet 素食，不吃坚果 (none 清除)",
+	"settings.unknown_language": "未知语言。请使用两个字母的代码，例如 en、es、ru 或 zh。",
+	"settings.unknown_locale":   "未知地区格式。请使用类似 zh-CN、en-US 或 es-MX 的标签。",
+	"settings.invalid_units":    "单位必须是 metric 或 imperial。",
+	"settings.invalid_energy":   "能量单位必须是 kcal 或 kj。",
+	"settings.invalid_diet":     "饮食备注最多 %d 个字符。",
+	"settings.unavailable":      "此机器人不支持设置。",
+	"settings.load_failed":      "无法加载你的设置。请重试。",
+	"settings.save_failed":      "无法保存你的设置。请重试。",
+	"units.metric":              "公制",
+	"units.imperial":            "英制",
+	"energy_unit.kcal":          "千卡",
+	"energy_unit.kj":            "千焦",
+
+	// Energy and portion sizes in the user's units
+	"energy.kcal":    "%s 千卡",
+	"energy.kj":      "%s 千焦",
+	"portion.grams":  "%s克",
+	"portion.ounces": "%s盎司",
+}
+
+// zhWeekdays are the CLDR abbreviated weekday names used by ShortDate
+var zhWeekdays = [...]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"}
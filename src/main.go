@@ -20,24 +20,36 @@ func main() {
 		log.Fatal("TELEGRAM_BOT_TOKEN environment variable not set")
 	}
 
-	geminiKey := os.Getenv("GEMINI_API_KEY")
-	if geminiKey == "" {
-		log.Fatal("GEMINI_API_KEY environment variable not set")
-	}
-
 	// Log startup (without exposing secrets)
 	log.Println("Starting Calorie Estimation Bot...")
 	log.Println("Environment variables validated")
 
 	// Initialize services
 	sessionManager := services.NewSessionManager()
-	geminiClient, err := services.NewGeminiClient()
+
+	// Load versioned prompt templates (embedded, or PROMPTS_DIR)
+	prompts, err := services.NewPromptsFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load prompts: %v", err)
+	}
+
+	// Create estimator chain from ESTIMATOR_PROVIDERS (default: gemini, which needs GEMINI_API_KEY)
+	chain, err := services.NewEstimatorFromEnv(prompts)
+	if err != nil {
+		log.Fatalf("Failed to initialize estimator: %v", err)
+	}
+
+	// Repeated photos reuse the cached estimate (ESTIMATE_CACHE_TTL, ESTIMATE_CACHE_SIZE, ESTIMATE_CACHE_MODE)
+	estimator, err := services.NewCachingEstimatorFromEnv(chain, prompts)
 	if err != nil {
-		log.Fatalf("Failed to initialize Gemini client: %v", err)
+		log.Fatalf("Failed to configure estimate cache: %v", err)
 	}
 
-	// Create estimator
-	estimator := services.NewGeminiEstimator(geminiClient)
+	// Uploads are decoded, stripped of EXIF, oriented and downsized (IMAGE_MAX_EDGE, IMAGE_MAX_BYTES)
+	images, err := services.NewImagePreprocessorFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure image preprocessing: %v", err)
+	}
 
 	// Start session cleanup goroutine (T018)
 	sessionManager.StartCleanupRoutine()
@@ -63,11 +75,13 @@ func main() {
 	// Initialize handlers (T024-T033)
 	// NOTE: This standalone bot does NOT share storage with miniapp.
 	// Use cmd/unified/main.go for shared storage integration.
-	estimateHandler := handlers.NewEstimateHandler(sender, sessionManager, estimator, nil)
+	estimateHandler := handlers.NewEstimateHandler(sender, sessionManager, estimator, nil, nil, images)
 
 	// Register command handlers
 	tgBot.Handle("/start", estimateHandler.HandleStart)
 	tgBot.Handle("/estimate", estimateHandler.HandleEstimate)
+	tgBot.Handle("/log", estimateHandler.HandleLog)
+	tgBot.Handle(telebot.OnText, estimateHandler.HandleText)
 	tgBot.Handle(telebot.OnPhoto, estimateHandler.HandlePhoto)
 	tgBot.Handle(telebot.OnDocument, estimateHandler.HandleDocument)
 
@@ -80,7 +94,9 @@ func main() {
 		log.Printf("[CALLBACK] User %d clicked button. Callback data: '%s' (after trim)", userID, callbackData)
 
 		var err error
-		switch callbackData {
+		// Result buttons carry the pending estimate ID after the action, e.g. "save|12"
+		action, _ := handlers.ParseCallbackData(callbackData)
+		switch action {
 		case "re_estimate":
 			log.Printf("[CALLBACK] Handling re_estimate for user %d", userID)
 			err = estimateHandler.HandleReEstimate(c)
@@ -95,13 +111,20 @@ func main() {
 				log.Printf("[CALLBACK ERROR] HandleCancel failed for user %d: %v", userID, err)
 			}
 			return err
+		case "done":
+			log.Printf("[CALLBACK] Handling done for user %d", userID)
+			err = estimateHandler.HandleDone(c)
+			if err != nil {
+				log.Printf("[CALLBACK ERROR] HandleDone failed for user %d: %v", userID, err)
+			}
+			return err
 		default:
 			log.Printf("[CALLBACK WARNING] Unknown callback data '%s' from user %d", callbackData, userID)
-			return c.Respond(&telebot.CallbackResponse{Text: "Unknown action"})
+			return estimateHandler.HandleUnknownCallback(c)
 		}
 	})
 
-	log.Println("Handlers registered: /estimate, photo upload, inline buttons")
+	log.Println("Handlers registered: /estimate, /log, photo upload, text, inline buttons")
 
 	// Start bot polling
 	tgBot.Start()
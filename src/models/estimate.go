@@ -3,8 +3,12 @@ package models
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/src/i18n"
 )
 
 // SessionState represents the current state of a user's /estimate flow
@@ -17,12 +21,21 @@ const (
 	// StateAwaitingImage indicates bot is waiting for image upload
 	StateAwaitingImage SessionState = "awaiting_image"
 
+	// StateCollectingImages indicates one or more photos of a meal were received and more may follow until Done
+	StateCollectingImages SessionState = "collecting_images"
+
+	// StateAwaitingDescription indicates bot is waiting for a text meal description (/log)
+	StateAwaitingDescription SessionState = "awaiting_description"
+
+	// StateAwaitingCalories indicates bot is waiting for a manual kcal total for the pending estimate (Adjust)
+	StateAwaitingCalories SessionState = "awaiting_calories"
+
 	// StateProcessing indicates bot is processing uploaded image via Gemini
 	StateProcessing SessionState = "processing"
 )
 
 // UserSession tracks in-memory session state for a single user during /estimate flow
-// Stored in sync.Map keyed by UserID (thread-safe, in-memory only)
+// Held by SessionManager keyed by UserID (mutex-guarded, in-memory only)
 type UserSession struct {
 	// LastActivity tracks the last user interaction (for cleanup after 15 min timeout)
 	LastActivity time.Time
@@ -35,6 +48,60 @@ type UserSession struct {
 
 	// State is the current flow state
 	State SessionState
+
+	// Images holds preprocessed photos collected for a multi-photo meal (StateCollectingImages)
+	Images []ImageInput
+
+	// AlbumID is the Telegram media group of the most recently collected photo (empty for single photos)
+	AlbumID string
+
+	// Hints are photo captions and text notes sent while awaiting or collecting photos
+	// Passed to the estimator and saved as the log's note
+	Hints []string
+
+	// Pending is the delivered estimate awaiting Save, Adjust or Discard (nil when none)
+	Pending *PendingEstimate
+}
+
+// PendingEstimate is an estimate shown to the user but not yet saved to the log
+type PendingEstimate struct {
+	// ID distinguishes successive pending estimates so a stale auto-save timer or a button on an
+	// older result never acts on a newer one; the result buttons carry it in their callback data
+	ID int
+
+	// Original is the estimator's answer; portion multipliers always apply to it
+	Original *EstimateResult
+
+	// Result is what will be saved (Original, possibly adjusted)
+	Result *EstimateResult
+
+	// Note is the user's caption or notes for the log entry
+	Note string
+
+	// NextState is the state to return to after a manual kcal entry (AwaitingImage or AwaitingDescription)
+	NextState SessionState
+
+	// MealType is inferred when the estimate is delivered and may be changed with the meal buttons
+	MealType internalmodels.MealType
+
+	// DeliveredAt is when the estimate was shown; it becomes the log timestamp however late it is saved
+	DeliveredAt time.Time
+
+	// MessageID is the bot message showing the estimate with its buttons (0 until shown)
+	MessageID int
+
+	// MenuIDs are the Adjust menus (×N, Enter kcal) posted for the estimate
+	// Their buttons, like the result's, are removed once the estimate is saved, discarded or replaced
+	MenuIDs []int
+}
+
+// MaxMealImages bounds how many photos can be collected for one estimate (Telegram albums hold up to 10)
+const MaxMealImages = 10
+
+// ImageInput is one encoded photo passed to the estimator
+type ImageInput struct {
+	Data     []byte
+	MimeType string
 }
 
 // EstimateResult holds the calorie estimation output from Gemini Vision API
@@ -43,58 +110,94 @@ type EstimateResult struct {
 	Reasoning string `json:"reasoning,omitempty"`
 
 	// Confidence level: "low", "medium", or "high"
-	Confidence string `json:"confidence"`
+	Confidence string `json:"confidence" schema:"enum=low|medium|high"`
 
-	// FoodItems contains detected food items (empty array if no food)
-	FoodItems []string `json:"items,omitempty"`
+	// FoodItems contains detected food items with per-item calories (empty array if no food)
+	FoodItems []FoodItem `json:"items,omitempty"`
 
 	// Calories is the total estimated calories (kcal)
+	// Derived from the item sum by SyncCalories when every item carries calories
 	Calories int `json:"calories"`
+
+	// Macros is the macronutrient breakdown in grams (optional)
+	Macros *Macros `json:"macros,omitempty"`
+
+	// Provider names the estimator backend that produced this result (set by FallbackEstimator)
+	Provider string `json:"provider,omitempty" schema:"-"`
+
+	// PromptVersion is the prompts/estimation template version used (set by the provider)
+	PromptVersion string `json:"promptVersion,omitempty" schema:"-"`
 }
 
-// FormatWelcomeMessage returns the bot introduction and usage instructions for /start command
-func FormatWelcomeMessage() string {
-	return `👋 Welcome to Calorie Estimation Bot!
+// Macros holds macronutrient amounts in grams
+type Macros struct {
+	Protein float64 `json:"protein"`
+	Carbs   float64 `json:"carbs"`
+	Fat     float64 `json:"fat"`
 
-I help you estimate the calories in your food by analyzing images.
+	// Fiber and Sugar are optional; nil when the model did not estimate them
+	Fiber *float64 `json:"fiber,omitempty"`
+	Sugar *float64 `json:"sugar,omitempty"`
+}
 
-**How to use:**
-1. Send /estimate command
-2. Upload a photo of your food
-3. Receive calorie estimate with confidence indicator
+// Validate checks macronutrient amounts are non-negative and consistent
+func (m *Macros) Validate() error {
+	if m.Protein < 0 || m.Carbs < 0 || m.Fat < 0 {
+		return fmt.Errorf("macros must be non-negative, got protein=%.1f carbs=%.1f fat=%.1f", m.Protein, m.Carbs, m.Fat)
+	}
+	if m.Fiber != nil && (*m.Fiber < 0 || *m.Fiber > m.Carbs) {
+		return fmt.Errorf("fiber must be between 0 and carbs (%.1f g), got %.1f", m.Carbs, *m.Fiber)
+	}
+	if m.Sugar != nil && (*m.Sugar < 0 || *m.Sugar > m.Carbs) {
+		return fmt.Errorf("sugar must be between 0 and carbs (%.1f g), got %.1f", m.Carbs, *m.Sugar)
+	}
+	return nil
+}
 
-**Features:**
-• 🍽️ Instant calorie estimation
-• 📊 Confidence indicators (Low/Medium/High)
-• 🔄 Re-estimate with different images
-• ❌ Cancel anytime
+// FormatMacros renders macros as a single line, e.g. "P 30g · C 45g · F 12g · Fiber 5g"
+func FormatMacros(p *i18n.Printer, m *Macros) string {
+	line := p.T("macros", p.Decimal(m.Protein, 0), p.Decimal(m.Carbs, 0), p.Decimal(m.Fat, 0))
+	if m.Fiber != nil {
+		line += p.T("macros.fiber", p.Decimal(*m.Fiber, 0))
+	}
+	if m.Sugar != nil {
+		line += p.T("macros.sugar", p.Decimal(*m.Sugar, 0))
+	}
+	return line
+}
 
-Ready to start? Send /estimate to begin!`
+// FormatWelcomeMessage returns the bot introduction and usage instructions for /start command
+func FormatWelcomeMessage(p *i18n.Printer) string {
+	return p.T("welcome")
 }
 
 // FormatResult formats an EstimateResult into fixed-format response per FR-006
 // Returns deterministic, consistent message structure
-func FormatResult(result *EstimateResult) string {
-	itemsList := "None detected"
+func FormatResult(p *i18n.Printer, result *EstimateResult) string {
+	itemsList := p.T("result.no_items")
 	if len(result.FoodItems) > 0 {
-		itemsList = strings.Join(result.FoodItems, ", ")
+		lines := make([]string, len(result.FoodItems))
+		for i, item := range result.FoodItems {
+			lines[i] = "\n• " + item.Format(p)
+		}
+		itemsList = strings.Join(lines, "")
 	}
 
-	// Capitalize first letter of confidence
-	confidence := result.Confidence
-	if len(confidence) > 0 {
-		confidence = strings.ToUpper(string(confidence[0])) + strings.ToLower(confidence[1:])
+	macrosLine := ""
+	if result.Macros != nil {
+		macrosLine = p.T("result.macros", FormatMacros(p, result.Macros)) + "\n"
 	}
 
-	return fmt.Sprintf(
-		"🍽️ Calorie Estimate\n\n"+
-			"Estimated Calories: %d kcal\n"+
-			"Confidence: %s\n\n"+
-			"Detected Items: %s",
-		result.Calories,
-		confidence,
-		itemsList,
-	)
+	return p.T("result.title") + "\n\n" +
+		p.T("result.calories", p.Energy(result.Calories)) + "\n" +
+		macrosLine +
+		p.T("result.confidence", FormatConfidence(p, result.Confidence)) + "\n\n" +
+		p.T("result.items", itemsList)
+}
+
+// FormatConfidence returns the localized confidence level, e.g. "Medium"
+func FormatConfidence(p *i18n.Printer, confidence string) string {
+	return p.T("confidence." + strings.ToLower(confidence))
 }
 
 // HasFood returns true if the result contains recognized food items
@@ -103,16 +206,120 @@ func (r *EstimateResult) HasFood() bool {
 	return len(r.FoodItems) > 0 && r.Calories > 0
 }
 
+// SyncCalories sets the total to the sum of per-item calories
+// Leaves the total unchanged unless every item carries a calorie estimate, since a
+// partial sum would undercount
+func (r *EstimateResult) SyncCalories() {
+	if total, ok := internalmodels.ItemCalories(LogItems(r.FoodItems)); ok {
+		r.Calories = total
+	}
+}
+
 // Validate checks if EstimateResult fields meet validation rules from data-model.md
 func (r *EstimateResult) Validate() error {
 	if r.Calories < 0 {
 		return fmt.Errorf("calories must be non-negative, got %d", r.Calories)
 	}
 
+	for i := range r.FoodItems {
+		if err := r.FoodItems[i].Validate(); err != nil {
+			return err
+		}
+	}
+
 	validConfidence := map[string]bool{"low": true, "medium": true, "high": true}
 	if !validConfidence[strings.ToLower(r.Confidence)] {
 		return fmt.Errorf("confidence must be low/medium/high, got %s", r.Confidence)
 	}
 
+	if r.Macros != nil {
+		if err := r.Macros.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// Scaled returns a copy for factor times the portion (e.g. 0.5 for half), see ScaledTo
+func (r *EstimateResult) Scaled(factor float64) *EstimateResult {
+	return r.scale(factor, int(math.Round(float64(r.Calories)*factor)))
+}
+
+// ScaledTo returns a copy adjusted to the given total (kcal), scaling item portions,
+// item calories and macros proportionally; when every item has calories, rounding is
+// absorbed by the largest item so the items still add up to the total
+func (r *EstimateResult) ScaledTo(calories int) *EstimateResult {
+	if r.Calories <= 0 {
+		scaled := r.Clone()
+		scaled.Calories = calories
+		return scaled
+	}
+	return r.scale(float64(calories)/float64(r.Calories), calories)
+}
+
+// scale multiplies portions, item calories and macros by factor and sets the total to calories
+func (r *EstimateResult) scale(factor float64, calories int) *EstimateResult {
+	scaled := r.Clone()
+	scaled.Calories = calories
+
+	sum, largest, complete := 0, -1, true
+	for i := range scaled.FoodItems {
+		item := &scaled.FoodItems[i]
+		item.PortionGrams = math.Round(item.PortionGrams*factor*10) / 10
+		if item.Calories == nil {
+			complete = false
+			continue
+		}
+		itemCalories := int(math.Round(float64(*item.Calories) * factor))
+		item.Calories = &itemCalories
+		sum += itemCalories
+		if largest < 0 || itemCalories > *scaled.FoodItems[largest].Calories {
+			largest = i
+		}
+	}
+	if complete && sum > 0 && sum != calories {
+		adjusted := max(0, *scaled.FoodItems[largest].Calories+calories-sum)
+		scaled.FoodItems[largest].Calories = &adjusted
+	}
+
+	if m := scaled.Macros; m != nil {
+		m.Protein = scaleGrams(m.Protein, factor)
+		m.Carbs = scaleGrams(m.Carbs, factor)
+		m.Fat = scaleGrams(m.Fat, factor)
+		if m.Fiber != nil {
+			*m.Fiber = min(scaleGrams(*m.Fiber, factor), m.Carbs)
+		}
+		if m.Sugar != nil {
+			*m.Sugar = min(scaleGrams(*m.Sugar, factor), m.Carbs)
+		}
+	}
+	return scaled
+}
+
+// scaleGrams scales a macro amount and rounds to 0.1 g
+func scaleGrams(grams, factor float64) float64 {
+	return math.Round(grams*factor*10) / 10
+}
+
+// Clone returns a deep copy so cached results can be handed out without sharing slices or pointers
+func (r *EstimateResult) Clone() *EstimateResult {
+	clone := *r
+	clone.FoodItems = append([]FoodItem(nil), r.FoodItems...)
+	for i := range clone.FoodItems {
+		clone.FoodItems[i].Calories = copyCalories(r.FoodItems[i].Calories)
+	}
+	if r.Macros != nil {
+		macros := *r.Macros
+		if r.Macros.Fiber != nil {
+			fiber := *r.Macros.Fiber
+			macros.Fiber = &fiber
+		}
+		if r.Macros.Sugar != nil {
+			sugar := *r.Macros.Sugar
+			macros.Sugar = &sugar
+		}
+		clone.Macros = &macros
+	}
+	return &clone
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/src/i18n"
+)
+
+// FormatGoalProgress formats today's consumption against the daily goal
+// Appended to the estimate result when the user has a goal set
+func FormatGoalProgress(p *i18n.Printer, consumed, goal int) string {
+	today := p.T("goal.today", p.EnergyValue(consumed), p.Energy(goal))
+	if remaining := goal - consumed; remaining >= 0 {
+		return today + "\n" + p.T("goal.remaining", p.Energy(remaining))
+	}
+	return today + "\n" + p.T("goal.over", p.Energy(consumed-goal))
+}
+
+// FormatGoalStatus formats the reply to the /goal command
+func FormatGoalStatus(p *i18n.Printer, goal int, timezone string) string {
+	if timezone == "" {
+		timezone = p.T("timezone.server")
+	}
+	if goal == 0 {
+		return p.T("goal.none", timezone)
+	}
+	return p.T("goal.status", p.Energy(goal), timezone)
+}
+
+// FormatOneShotStatus formats the reply to the /oneshot command
+func FormatOneShotStatus(p *i18n.Printer, enabled bool) string {
+	if enabled {
+		return p.T("oneshot.on")
+	}
+	return p.T("oneshot.off")
+}
+
+// FormatMealLabel returns the localized meal name, e.g. "Breakfast"
+func FormatMealLabel(p *i18n.Printer, mealType internalmodels.MealType) string {
+	return p.T("meal." + string(mealType))
+}
+
+// FormatMealType formats the meal line shown on an estimate awaiting confirmation
+func FormatMealType(p *i18n.Printer, mealType internalmodels.MealType) string {
+	return p.T("meal.current", FormatMealLabel(p, mealType))
+}
+
+// FormatMealWindowsStatus formats the reply to the /meals command
+func FormatMealWindowsStatus(p *i18n.Printer, windows []internalmodels.MealWindow, defaults bool) string {
+	var b strings.Builder
+	if defaults {
+		b.WriteString(p.T("meals.title_default") + "\n")
+	} else {
+		b.WriteString(p.T("meals.title") + "\n")
+	}
+	for _, w := range windows {
+		fmt.Fprintf(&b, "\n• %s: %02d:%02d–%02d:%02d", FormatMealLabel(p, w.Type), w.Start/60, w.Start%60, w.End/60, w.End%60)
+	}
+	b.WriteString("\n" + p.T("meals.snack"))
+	b.WriteString("\n\n" + p.T("meals.help"))
+	if !defaults {
+		b.WriteString(p.T("meals.reset_hint"))
+	}
+	return b.String()
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/src/i18n"
+)
+
+// FoodItem is a single detected food with its own portion and calorie estimate
+// It mirrors the JSON the estimation prompts ask for ("grams"); LogItems converts it to the
+// log's item, whose API and storage schema names the portion "portionGrams"
+type FoodItem struct {
+	// Name is the food name, e.g. "Grilled chicken breast"
+	Name string `json:"name"`
+
+	// PortionGrams is the estimated portion weight in grams (0 if unknown)
+	PortionGrams float64 `json:"grams,omitempty"`
+
+	// Calories is the estimated energy of this item alone (kcal); nil when the model gave none
+	// (string items), while 0 is a real estimate, e.g. water
+	Calories *int `json:"calories" schema:"required"`
+}
+
+// UnmarshalJSON accepts either a structured item object or a plain string name
+// Older prompts (and occasionally the model) return items as free-text strings
+func (i *FoodItem) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*i = FoodItem{Name: name}
+		return nil
+	}
+
+	type plain FoodItem // avoids recursing into this method
+	var item plain
+	if err := json.Unmarshal(data, &item); err != nil {
+		return err
+	}
+	*i = FoodItem(item)
+	return nil
+}
+
+// Validate checks a single item's fields
+func (i *FoodItem) Validate() error {
+	if strings.TrimSpace(i.Name) == "" {
+		return fmt.Errorf("item name cannot be empty")
+	}
+	if i.PortionGrams < 0 {
+		return fmt.Errorf("item %q portion must be non-negative, got %.1f", i.Name, i.PortionGrams)
+	}
+	if i.Calories != nil && *i.Calories < 0 {
+		return fmt.Errorf("item %q calories must be non-negative, got %d", i.Name, *i.Calories)
+	}
+	return nil
+}
+
+// String renders the item in English as "Name (200g) — 330 kcal", omitting unknown parts
+func (i FoodItem) String() string {
+	return i.Format(i18n.New(i18n.DefaultLanguage, ""))
+}
+
+// Format renders the item in the printer's language, omitting unknown parts
+func (i FoodItem) Format(p *i18n.Printer) string {
+	s := i.Name
+	if i.PortionGrams > 0 {
+		s += p.T("item.portion", p.Portion(i.PortionGrams))
+	}
+	if i.Calories != nil {
+		s += p.T("item.calories", p.Energy(*i.Calories))
+	}
+	return s
+}
+
+// LogItems converts estimator food items into the shared log model
+func LogItems(items []FoodItem) []internalmodels.FoodItem {
+	result := make([]internalmodels.FoodItem, len(items))
+	for i, item := range items {
+		result[i] = internalmodels.FoodItem{
+			Name:         item.Name,
+			PortionGrams: item.PortionGrams,
+			Calories:     copyCalories(item.Calories),
+		}
+	}
+	return result
+}
+
+// copyCalories copies an optional calorie value so items never share it
+func copyCalories(calories *int) *int {
+	if calories == nil {
+		return nil
+	}
+	copied := *calories
+	return &copied
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"strings"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/src/i18n"
+)
+
+// FormatSettings formats the user's profile for the /settings command
+func FormatSettings(p *i18n.Printer, profile *internalmodels.UserProfile) string {
+	lines := []string{
+		p.T("settings.title") + "\n",
+		p.T("settings.timezone", orDefault(profile.Timezone, p.T("timezone.server"))),
+		p.T("settings.language", orDefault(profile.Language, p.T("settings.language_default", internalmodels.DefaultLanguage))),
+		p.T("settings.locale", orDefault(profile.Locale, p.T("settings.locale_default", profile.LocaleOrDefault()))),
+		p.T("settings.units", p.T("units."+string(profile.UnitsOrDefault()))),
+		p.T("settings.energy", p.T("energy_unit."+string(profile.EnergyOrDefault()))),
+	}
+
+	if profile.DailyGoal > 0 {
+		lines = append(lines, p.T("settings.goal", p.Energy(profile.DailyGoal)))
+	} else {
+		lines = append(lines, p.T("settings.goal_unset"))
+	}
+	if profile.OneShot {
+		lines = append(lines, p.T("settings.oneshot_on"))
+	} else {
+		lines = append(lines, p.T("settings.oneshot_off"))
+	}
+	if profile.MealWindows == "" {
+		lines = append(lines, p.T("settings.meals_default"))
+	} else {
+		lines = append(lines, p.T("settings.meals", strings.ReplaceAll(profile.MealWindows, ",", ", ")))
+	}
+	if profile.DietaryNotes == "" {
+		lines = append(lines, p.T("settings.diet_unset"))
+	} else {
+		lines = append(lines, p.T("settings.diet", profile.DietaryNotes))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// orDefault returns value, or fallback when value is empty
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/src/i18n"
+)
+
+// FormatSummary formats a period summary for /today, /week and /month
+// Returns deterministic, fixed-format message structure like FormatResult
+func FormatSummary(p *i18n.Printer, title string, s *internalmodels.PeriodSummary) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "📊 %s\n\n", title)
+	b.WriteString(p.T("summary.total", p.Energy(s.TotalCalories)) + "\n")
+	b.WriteString(p.T("summary.entries", s.Entries) + "\n")
+
+	if len(s.Days) > 1 {
+		b.WriteString(p.N("summary.average", len(s.Days), p.Energy(s.AveragePerLoggedDay()), s.DaysLogged, len(s.Days)) + "\n")
+	}
+
+	if s.DailyGoal > 0 {
+		if len(s.Days) == 1 {
+			b.WriteString("\n" + FormatGoalProgress(p, s.TotalCalories, s.DailyGoal) + "\n")
+		} else {
+			b.WriteString(p.N("summary.goal", s.DaysLogged, p.Energy(s.DailyGoal), s.DaysWithinGoal, s.DaysLogged) + "\n")
+		}
+	}
+
+	if s.Entries == 0 {
+		b.WriteString("\n" + p.T("summary.empty"))
+		return b.String()
+	}
+
+	b.WriteString("\n" + p.T("summary.by_meal"))
+	for _, meal := range s.Meals {
+		if meal.Entries == 0 {
+			continue
+		}
+		b.WriteString("\n" + p.T("summary.line", FormatMealLabel(p, meal.MealType), p.Energy(meal.Calories), meal.Entries))
+	}
+	b.WriteString("\n")
+
+	if len(s.Days) > 1 {
+		b.WriteString("\n" + p.T("summary.per_day"))
+		for _, day := range s.Days {
+			if day.Entries == 0 {
+				continue
+			}
+			marker := ""
+			if s.DailyGoal > 0 && day.Calories > s.DailyGoal {
+				marker = " ⚠️"
+			}
+			b.WriteString("\n" + p.T("summary.line", p.ShortDate(day.Date), p.Energy(day.Calories), day.Entries) + marker)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
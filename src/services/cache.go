@@ -0,0 +1,274 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/freezind/telegram-calories-bot/src/models"
+)
+
+const (
+	// DefaultCacheTTL is how long an image estimate is reused when ESTIMATE_CACHE_TTL is not set
+	DefaultCacheTTL = 24 * time.Hour
+
+	// DefaultCacheSize bounds the number of cached estimates when ESTIMATE_CACHE_SIZE is not set
+	DefaultCacheSize = 500
+
+	// DefaultPerceptualDistance is the max dHash Hamming distance treated as the same photo
+	DefaultPerceptualDistance = 6
+)
+
+// CacheOptions configures a CachingEstimator
+type CacheOptions struct {
+	TTL        time.Duration // Entry lifetime (0 = DefaultCacheTTL)
+	MaxEntries int           // LRU bound (0 = DefaultCacheSize)
+
+	// Perceptual also matches resized/recompressed copies by dHash when no exact match exists
+	Perceptual  bool
+	MaxDistance int // Hamming distance threshold for perceptual matches (0 = DefaultPerceptualDistance)
+}
+
+// cacheEntry is one cached image estimate
+type cacheEntry struct {
+	key     string   // Exact content key
+	variant string   // Prompt version and vars the estimate was produced with
+	hashes  []uint64 // Perceptual hash per photo, in order (nil when unavailable)
+	result  *models.EstimateResult
+	expires time.Time
+}
+
+// CachingEstimator wraps an Estimator and reuses image estimates for repeated photos
+// Keys combine the image content hash, mime type, user hints and the user's prompt version/vars,
+// so a prompt change or A/B variant never serves another variant's answer
+// Text estimates are passed through uncached
+type CachingEstimator struct {
+	inner   Estimator
+	prompts *Prompts
+	opts    CacheOptions
+
+	mu      sync.Mutex
+	order   *list.List               // Front = most recently used
+	entries map[string]*list.Element // Exact key -> element holding *cacheEntry
+}
+
+// NewCachingEstimator wraps inner with an in-memory LRU cache
+// prompts may be nil to use DefaultPrompts()
+func NewCachingEstimator(inner Estimator, prompts *Prompts, opts CacheOptions) *CachingEstimator {
+	if prompts == nil {
+		prompts = DefaultPrompts()
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultCacheTTL
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = DefaultCacheSize
+	}
+	if opts.MaxDistance <= 0 {
+		opts.MaxDistance = DefaultPerceptualDistance
+	}
+	return &CachingEstimator{
+		inner:   inner,
+		prompts: prompts,
+		opts:    opts,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+// NewCachingEstimatorFromEnv wraps inner per ESTIMATE_CACHE_TTL (Go duration, "0" disables),
+// ESTIMATE_CACHE_SIZE and ESTIMATE_CACHE_MODE ("exact" or "perceptual", default exact)
+// Returns inner unchanged when caching is disabled
+func NewCachingEstimatorFromEnv(inner Estimator, prompts *Prompts) (Estimator, error) {
+	opts := CacheOptions{TTL: DefaultCacheTTL, MaxEntries: DefaultCacheSize}
+
+	if raw := os.Getenv("ESTIMATE_CACHE_TTL"); raw != "" {
+		ttl, err := time.ParseDuration(raw)
+		if err != nil || ttl < 0 {
+			return nil, fmt.Errorf("invalid ESTIMATE_CACHE_TTL %q: must be a duration like 24h (0 disables)", raw)
+		}
+		if ttl == 0 {
+			log.Printf("[CACHE] Estimate cache disabled")
+			return inner, nil
+		}
+		opts.TTL = ttl
+	}
+
+	if raw := os.Getenv("ESTIMATE_CACHE_SIZE"); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("invalid ESTIMATE_CACHE_SIZE %q: must be a positive integer", raw)
+		}
+		opts.MaxEntries = size
+	}
+
+	switch mode := os.Getenv("ESTIMATE_CACHE_MODE"); mode {
+	case "", "exact":
+	case "perceptual":
+		opts.Perceptual = true
+	default:
+		return nil, fmt.Errorf("invalid ESTIMATE_CACHE_MODE %q: must be exact or perceptual", mode)
+	}
+
+	log.Printf("[CACHE] Estimate cache: %d entries, TTL %v, perceptual=%v", opts.MaxEntries, opts.TTL, opts.Perceptual)
+	return NewCachingEstimator(inner, prompts, opts), nil
+}
+
+// EstimateFromImages returns a cached estimate for the same photos (or, in perceptual mode, visually
+// equivalent ones in the same order) and hints, otherwise calls the wrapped estimator and caches a successful result
+func (c *CachingEstimator) EstimateFromImages(ctx context.Context, images []models.ImageInput, hints string) (*models.EstimateResult, error) {
+	variant := c.variant(ctx, hints)
+	key := exactCacheKey(images, variant)
+
+	var hashes []uint64
+	if c.opts.Perceptual {
+		hashes = perceptualHashes(images)
+	}
+
+	if result, ok := c.lookup(key, variant, hashes); ok {
+		return result, nil
+	}
+
+	result, err := c.inner.EstimateFromImages(ctx, images, hints)
+	if err != nil {
+		return nil, err
+	}
+
+	c.store(&cacheEntry{
+		key:     key,
+		variant: variant,
+		hashes:  hashes,
+		result:  result.Clone(),
+		expires: time.Now().Add(c.opts.TTL),
+	})
+	return result, nil
+}
+
+// EstimateFromText is not cached; descriptions rarely repeat verbatim
+func (c *CachingEstimator) EstimateFromText(ctx context.Context, description string) (*models.EstimateResult, error) {
+	return c.inner.EstimateFromText(ctx, description)
+}
+
+// Len returns the number of cached entries (including not-yet-evicted expired ones)
+func (c *CachingEstimator) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// variant identifies the prompt the request would be rendered with
+// Hints are included because "half portion" must not reuse the full-portion answer
+func (c *CachingEstimator) variant(ctx context.Context, hints string) string {
+	req := promptRequestFrom(ctx)
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s", c.prompts.VersionFor(req.UserID), req.Vars.Locale, req.Vars.Units, req.Vars.DietaryNotes, req.Vars.Language, hints)
+}
+
+// exactCacheKey hashes the prompt variant together with each photo's mime type and bytes
+func exactCacheKey(images []models.ImageInput, variant string) string {
+	h := sha256.New()
+	h.Write([]byte(variant))
+	for _, image := range images {
+		// Length-prefix each photo so boundaries between photos are unambiguous
+		fmt.Fprintf(h, "\x00%s\x00%d\x00", image.MimeType, len(image.Data))
+		h.Write(image.Data)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// perceptualHashes hashes every photo, returning nil if any cannot be decoded
+func perceptualHashes(images []models.ImageInput) []uint64 {
+	hashes := make([]uint64, len(images))
+	for i, image := range images {
+		hash, err := PerceptualHash(image.Data)
+		if err != nil {
+			log.Printf("[CACHE] Perceptual hash unavailable, using exact match only: %v", err)
+			return nil
+		}
+		hashes[i] = hash
+	}
+	return hashes
+}
+
+// hashDistance is the largest per-photo Hamming distance between two photo sets (-1 if not comparable)
+func hashDistance(a, b []uint64) int {
+	if len(a) == 0 || len(a) != len(b) {
+		return -1
+	}
+	worst := 0
+	for i := range a {
+		worst = max(worst, HammingDistance(a[i], b[i]))
+	}
+	return worst
+}
+
+// lookup finds an unexpired entry by exact key, then by perceptual distance within the same variant
+func (c *CachingEstimator) lookup(key, variant string, hashes []uint64) (*models.EstimateResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		if now.Before(entry.expires) {
+			c.order.MoveToFront(elem)
+			log.Printf("[CACHE] Exact hit")
+			return entry.result.Clone(), true
+		}
+		c.remove(elem)
+	}
+
+	if hashes == nil {
+		return nil, false
+	}
+
+	var best *list.Element
+	bestDistance := c.opts.MaxDistance + 1
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		entry := elem.Value.(*cacheEntry)
+		switch {
+		case !now.Before(entry.expires):
+			c.remove(elem)
+		case entry.variant == variant:
+			if d := hashDistance(entry.hashes, hashes); d >= 0 && d < bestDistance {
+				best, bestDistance = elem, d
+			}
+		}
+		elem = next
+	}
+	if best == nil {
+		return nil, false
+	}
+
+	c.order.MoveToFront(best)
+	log.Printf("[CACHE] Perceptual hit (distance %d)", bestDistance)
+	return best.Value.(*cacheEntry).result.Clone(), true
+}
+
+// store inserts or replaces an entry and evicts least recently used entries over the bound
+func (c *CachingEstimator) store(entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[entry.key]; ok {
+		c.remove(elem)
+	}
+	c.entries[entry.key] = c.order.PushFront(entry)
+
+	for c.order.Len() > c.opts.MaxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove drops an element from both the list and the index (caller holds mu)
+func (c *CachingEstimator) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
@@ -2,14 +2,22 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"github.com/freezind/telegram-calories-bot/src/models"
 )
 
+// errNoImages is returned when an image estimate is requested without any photos
+var errNoImages = errors.New("at least one image is required")
+
 // Estimator is the interface for calorie estimation
 type Estimator interface {
-	// EstimateFromImage analyzes image bytes and returns calorie estimate
-	EstimateFromImage(ctx context.Context, imageBytes []byte, mimeType string) (*models.EstimateResult, error)
+	// EstimateFromImages analyzes one or more photos of a single meal and returns one combined estimate
+	// hints is optional free text from the user (photo captions, notes like "half portion, no sauce")
+	EstimateFromImages(ctx context.Context, images []models.ImageInput, hints string) (*models.EstimateResult, error)
+
+	// EstimateFromText analyzes a free-text meal description and returns calorie estimate
+	EstimateFromText(ctx context.Context, description string) (*models.EstimateResult, error)
 }
 
 // GeminiEstimator uses Gemini API for real estimation
@@ -22,7 +30,12 @@ func NewGeminiEstimator(client *GeminiClient) Estimator {
 	return &GeminiEstimator{client: client}
 }
 
-// EstimateFromImage estimates calories from image bytes using Gemini
-func (e *GeminiEstimator) EstimateFromImage(ctx context.Context, imageBytes []byte, mimeType string) (*models.EstimateResult, error) {
-	return e.client.EstimateCalories(ctx, imageBytes, mimeType)
+// EstimateFromImages estimates calories from meal photos using Gemini
+func (e *GeminiEstimator) EstimateFromImages(ctx context.Context, images []models.ImageInput, hints string) (*models.EstimateResult, error) {
+	return e.client.EstimateCalories(ctx, images, hints)
+}
+
+// EstimateFromText estimates calories from a meal description using Gemini
+func (e *GeminiEstimator) EstimateFromText(ctx context.Context, description string) (*models.EstimateResult, error) {
+	return e.client.EstimateCaloriesFromText(ctx, description)
 }
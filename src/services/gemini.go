@@ -2,124 +2,252 @@ package services
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"log"
 	"os"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/freezind/telegram-calories-bot/src/models"
 	"google.golang.org/genai"
 )
 
+const (
+	// defaultGeminiModel is the fast, cost-effective model per research.md
+	defaultGeminiModel = "gemini-2.5-flash"
+
+	// DefaultGeminiConcurrency caps in-flight Gemini calls per process
+	DefaultGeminiConcurrency = 4
+
+	// geminiAttemptTimeout bounds a single GenerateContent call (30 seconds per data-model.md)
+	geminiAttemptTimeout = 30 * time.Second
+)
+
+// GeminiOptions configures a GeminiClient
+type GeminiOptions struct {
+	APIKey        string
+	Model         string      // Defaults to gemini-2.5-flash
+	BaseURL       string      // Optional API endpoint override (proxies, tests)
+	MaxConcurrent int         // In-flight request limit (0 = DefaultGeminiConcurrency)
+	Retry         RetryPolicy // Zero value = DefaultRetryPolicy
+	Prompts       *Prompts    // nil = DefaultPrompts()
+}
+
 // GeminiClient wraps Google Gemini SDK for calorie estimation
 // Handles API calls per contracts/gemini-vision.yaml
+// A single genai client is reused for all requests; safe for concurrent use
 type GeminiClient struct {
-	apiKey string
-	model  string
+	client  *genai.Client
+	model   string
+	retry   RetryPolicy
+	prompts *Prompts
+	slots   chan struct{} // Semaphore limiting concurrent GenerateContent calls
 }
 
 // NewGeminiClient creates a new Gemini client instance
 // Validates API key exists in environment
-func NewGeminiClient() (*GeminiClient, error) {
+// Optional: GEMINI_MAX_CONCURRENT (default 4), GEMINI_MAX_ATTEMPTS (default 4)
+func NewGeminiClient(prompts *Prompts) (*GeminiClient, error) {
 	apiKey := os.Getenv("GEMINI_API_KEY")
 	if apiKey == "" {
 		return nil, fmt.Errorf("GEMINI_API_KEY environment variable not set")
 	}
 
-	return &GeminiClient{
-		apiKey: apiKey,
-		model:  "gemini-2.5-flash", // Fast, cost-effective model per research.md
-	}, nil
+	opts := GeminiOptions{APIKey: apiKey, Retry: DefaultRetryPolicy, Prompts: prompts}
+
+	if raw := os.Getenv("GEMINI_MAX_CONCURRENT"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid GEMINI_MAX_CONCURRENT %q: must be a positive integer", raw)
+		}
+		opts.MaxConcurrent = n
+	}
+	if raw := os.Getenv("GEMINI_MAX_ATTEMPTS"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid GEMINI_MAX_ATTEMPTS %q: must be a positive integer", raw)
+		}
+		opts.Retry.MaxAttempts = n
+	}
+
+	return NewGeminiClientWithOptions(opts)
 }
 
-// EstimateCalories analyzes a food image and returns calorie estimate
-// Uses structured JSON prompt per contracts/gemini-vision.yaml
-func (gc *GeminiClient) EstimateCalories(ctx context.Context, imageBytes []byte, mimeType string) (*models.EstimateResult, error) {
-	// Create client with timeout (30 seconds per data-model.md)
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
+// NewGeminiClientWithOptions creates the long-lived genai client once
+func NewGeminiClientWithOptions(opts GeminiOptions) (*GeminiClient, error) {
+	if opts.APIKey == "" {
+		return nil, fmt.Errorf("gemini API key is required")
+	}
+	if opts.Model == "" {
+		opts.Model = defaultGeminiModel
+	}
+	if opts.MaxConcurrent <= 0 {
+		opts.MaxConcurrent = DefaultGeminiConcurrency
+	}
+	if opts.Retry == (RetryPolicy{}) {
+		opts.Retry = DefaultRetryPolicy
+	}
+	if opts.Prompts == nil {
+		opts.Prompts = DefaultPrompts()
+	}
 
 	// Initialize client per research.md Decision 1
-	client, err := genai.NewClient(ctxWithTimeout, &genai.ClientConfig{
-		APIKey:  gc.apiKey,
-		Backend: genai.BackendGeminiAPI,
+	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		APIKey:      opts.APIKey,
+		Backend:     genai.BackendGeminiAPI,
+		HTTPOptions: genai.HTTPOptions{BaseURL: opts.BaseURL},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create genai client: %w", err)
 	}
 
-	// Structured prompt per research.md Decision 3 and contracts/gemini-vision.yaml
-	prompt := `You are a nutrition analysis assistant. Analyze this food image and estimate total calories.
-
-Output ONLY valid JSON with this exact structure:
-{
-  "calories": <number>,
-  "confidence": "low|medium|high",
-  "items": ["food1", "food2", ...],
-  "reasoning": "brief explanation"
+	return &GeminiClient{
+		client:  client,
+		model:   opts.Model,
+		retry:   opts.Retry,
+		prompts: opts.Prompts,
+		slots:   make(chan struct{}, opts.MaxConcurrent),
+	}, nil
 }
 
-Confidence levels:
-- high: Common foods, clear portions visible
-- medium: Some foods recognizable, portions estimated
-- low: Unclear foods or portions, or non-food image
+// EstimateCalories analyzes one or more photos of a meal and returns a combined calorie estimate
+// Uses the versioned estimation prompt (prompts/estimation) assigned to the user on ctx; hints may be empty
+func (gc *GeminiClient) EstimateCalories(ctx context.Context, images []models.ImageInput, hints string) (*models.EstimateResult, error) {
+	if len(images) == 0 {
+		return nil, errNoImages
+	}
+
+	prompt, version, err := gc.prompts.ImagePrompt(ctx, len(images), hints)
+	if err != nil {
+		return nil, err
+	}
 
-If no food detected, return:
-{"calories": 0, "confidence": "low", "items": [], "reasoning": "No food detected"}
+	// Create multimodal content: prompt + images (per research.md)
+	parts := []*genai.Part{genai.NewPartFromText(prompt)}
+	for _, image := range images {
+		parts = append(parts, genai.NewPartFromBytes(image.Data, image.MimeType)) // Supports JPEG, PNG, WebP
+	}
 
-Example (grilled chicken with vegetables):
-{"calories": 450, "confidence": "high", "items": ["Grilled chicken breast (200g)", "Steamed broccoli (100g)", "Brown rice (150g)"], "reasoning": "Standard portions for grilled chicken plate"}`
+	return gc.estimate(ctx, parts, version)
+}
+
+// EstimateCaloriesFromText estimates calories from a free-text meal description
+// e.g. "2 eggs and a slice of toast"; uses the same JSON contract as image estimation
+func (gc *GeminiClient) EstimateCaloriesFromText(ctx context.Context, description string) (*models.EstimateResult, error) {
+	prompt, version, err := gc.prompts.TextPrompt(ctx, description)
+	if err != nil {
+		return nil, err
+	}
 
-	// Create multimodal content: prompt + image (per research.md)
 	parts := []*genai.Part{
 		genai.NewPartFromText(prompt),
-		genai.NewPartFromBytes(imageBytes, mimeType), // Supports JPEG, PNG, WebP
 	}
 
+	return gc.estimate(ctx, parts, version)
+}
+
+// estimate sends the prompt parts to Gemini and parses the JSON estimate
+// A reply that fails parsing or validation gets exactly one repair round-trip
+// promptVersion is stamped on the result and selects the matching repair template
+func (gc *GeminiClient) estimate(ctx context.Context, parts []*genai.Part, promptVersion string) (*models.EstimateResult, error) {
 	content := []*genai.Content{{
 		Parts: parts,
 		Role:  genai.RoleUser,
 	}}
 
-	// Generate content using Gemini 2.5 Flash (optimized for speed)
-	response, err := client.Models.GenerateContent(ctxWithTimeout, gc.model, content, &genai.GenerateContentConfig{
-		Temperature: floatPtr(0.2), // Low temperature for deterministic output
+	reply, err := gc.generate(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	result, parseErr := parseEstimateResponse("Gemini", reply)
+	if parseErr == nil {
+		result.PromptVersion = promptVersion
+		return result, nil
+	}
+
+	log.Printf("[GEMINI] Invalid estimate, requesting repair: %v", parseErr)
+
+	repair, err := gc.prompts.RepairPrompt(promptVersion, parseErr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Show the model its own reply and the problem, then ask for corrected JSON only
+	content = append(content,
+		genai.NewContentFromText(reply, genai.RoleModel),
+		genai.NewContentFromText(repair, genai.RoleUser),
+	)
+
+	reply, err = gc.generate(ctx, content)
+	if err != nil {
+		return nil, fmt.Errorf("repair request failed: %w (original error: %v)", err, parseErr)
+	}
+
+	result, err = parseEstimateResponse("Gemini", reply)
+	if err != nil {
+		return nil, err
+	}
+	result.PromptVersion = promptVersion
+	return result, nil
+}
+
+// generate runs GenerateContent in JSON mode and returns the reply text
+// Transient failures (429/5xx, timeouts) are retried per gc.retry
+func (gc *GeminiClient) generate(ctx context.Context, content []*genai.Content) (string, error) {
+	config := &genai.GenerateContentConfig{
+		Temperature:      floatPtr(0.2), // Low temperature for deterministic output
+		ResponseMIMEType: "application/json",
+		ResponseSchema:   estimateSchema,
+	}
+
+	var response *genai.GenerateContentResponse
+	err := gc.retry.Do(ctx, func(ctx context.Context) error {
+		// Slot is released during backoff so waiting retries don't starve other users
+		if err := gc.acquire(ctx); err != nil {
+			return err
+		}
+		defer gc.release()
+
+		attemptCtx, cancel := context.WithTimeout(ctx, geminiAttemptTimeout)
+		defer cancel()
+
+		// Generate content using Gemini 2.5 Flash (optimized for speed)
+		var err error
+		response, err = gc.client.Models.GenerateContent(attemptCtx, gc.model, content, config)
+		return err
 	})
 	if err != nil {
-		return nil, fmt.Errorf("gemini API call failed: %w", err)
+		return "", fmt.Errorf("gemini API call failed: %w", err)
 	}
 
 	// Parse response
-	if len(response.Candidates) == 0 || len(response.Candidates[0].Content.Parts) == 0 {
-		return nil, fmt.Errorf("no response from Gemini API")
+	if len(response.Candidates) == 0 || response.Candidates[0].Content == nil || len(response.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("no response from Gemini API")
 	}
 
 	// Extract text from first part
 	textPart := response.Candidates[0].Content.Parts[0].Text
 	if textPart == "" {
-		return nil, fmt.Errorf("unexpected empty response from Gemini API")
+		return "", fmt.Errorf("unexpected empty response from Gemini API")
 	}
 
-	// Clean JSON response (remove markdown code blocks if present)
-	jsonText := strings.TrimSpace(textPart)
-	jsonText = strings.TrimPrefix(jsonText, "```json")
-	jsonText = strings.TrimPrefix(jsonText, "```")
-	jsonText = strings.TrimSuffix(jsonText, "```")
-	jsonText = strings.TrimSpace(jsonText)
-
-	// Unmarshal JSON to EstimateResult
-	var result models.EstimateResult
-	if err := json.Unmarshal([]byte(jsonText), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse Gemini JSON response: %w (response: %s)", err, jsonText)
-	}
+	return textPart, nil
+}
 
-	// Validate result per data-model.md
-	if err := result.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid result from Gemini: %w", err)
+// acquire takes a concurrency slot, waiting until one frees up or ctx ends
+func (gc *GeminiClient) acquire(ctx context.Context) error {
+	select {
+	case gc.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+}
 
-	return &result, nil
+// release returns a concurrency slot
+func (gc *GeminiClient) release() {
+	<-gc.slots
 }
 
 // floatPtr returns a pointer to a float32 value
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"  // Register GIF decoder for image.Decode
+	_ "image/jpeg" // Register JPEG decoder for image.Decode
+	_ "image/png"  // Register PNG decoder for image.Decode
+	"math/bits"
+)
+
+// dHash grid: 9x8 luminance samples give 8 horizontal gradients per row = 64 bits
+const (
+	dHashWidth  = 9
+	dHashHeight = 8
+)
+
+// PerceptualHash computes a 64-bit difference hash (dHash) of an encoded image
+// Resized or recompressed copies of the same photo hash to nearby values (small Hamming distance)
+func PerceptualHash(imageBytes []byte) (uint64, error) {
+	img, _, err := image.Decode(bytes.NewReader(imageBytes))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() < dHashWidth || bounds.Dy() < dHashHeight {
+		return 0, fmt.Errorf("image too small for perceptual hash: %dx%d", bounds.Dx(), bounds.Dy())
+	}
+
+	// Box-average the image down to the dHash grid
+	var grid [dHashHeight][dHashWidth]float64
+	for gy := 0; gy < dHashHeight; gy++ {
+		y0 := bounds.Min.Y + gy*bounds.Dy()/dHashHeight
+		y1 := bounds.Min.Y + (gy+1)*bounds.Dy()/dHashHeight
+		for gx := 0; gx < dHashWidth; gx++ {
+			x0 := bounds.Min.X + gx*bounds.Dx()/dHashWidth
+			x1 := bounds.Min.X + (gx+1)*bounds.Dx()/dHashWidth
+
+			var sum float64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					sum += luminance(img, x, y)
+				}
+			}
+			grid[gy][gx] = sum / float64((x1-x0)*(y1-y0))
+		}
+	}
+
+	var hash uint64
+	for gy := 0; gy < dHashHeight; gy++ {
+		for gx := 0; gx < dHashWidth-1; gx++ {
+			hash <<= 1
+			if grid[gy][gx] > grid[gy][gx+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash, nil
+}
+
+// luminance returns the Rec. 601 luma of a pixel in [0, 65535]
+func luminance(img image.Image, x, y int) float64 {
+	r, g, b, _ := img.At(x, y).RGBA()
+	return 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+}
+
+// HammingDistance counts the differing bits between two perceptual hashes
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
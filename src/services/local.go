@@ -0,0 +1,138 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/freezind/telegram-calories-bot/src/models"
+)
+
+// LocalEstimator calls a self-hosted HTTP model that speaks the EstimateResult JSON contract
+// Intended as a last-resort stand-in when hosted providers are unavailable
+type LocalEstimator struct {
+	url        string
+	prompts    *Prompts
+	httpClient *http.Client
+}
+
+// localRequest is the body POSTed to the local model
+// Photo requests set Image/MimeType to the first photo, plus Images listing every photo when there are several
+// Text requests set Description; Hints carries the user's notes on a photo request (already in Prompt)
+type localRequest struct {
+	Prompt      string       `json:"prompt"`
+	Image       string       `json:"image,omitempty"`
+	MimeType    string       `json:"mimeType,omitempty"`
+	Images      []localImage `json:"images,omitempty"`
+	Hints       string       `json:"hints,omitempty"`
+	Description string       `json:"description,omitempty"`
+}
+
+// localImage is one base64-encoded photo in a multi-photo request
+type localImage struct {
+	Data     string `json:"data"`
+	MimeType string `json:"mimeType"`
+}
+
+// NewLocalEstimator creates an estimator that POSTs to the given URL
+// prompts may be nil to use DefaultPrompts()
+func NewLocalEstimator(url string, prompts *Prompts) *LocalEstimator {
+	if prompts == nil {
+		prompts = DefaultPrompts()
+	}
+	return &LocalEstimator{url: url, prompts: prompts, httpClient: &http.Client{}}
+}
+
+// NewLocalEstimatorFromEnv configures a LocalEstimator from LOCAL_ESTIMATOR_URL
+func NewLocalEstimatorFromEnv(prompts *Prompts) (*LocalEstimator, error) {
+	url := os.Getenv("LOCAL_ESTIMATOR_URL")
+	if url == "" {
+		return nil, fmt.Errorf("LOCAL_ESTIMATOR_URL environment variable not set")
+	}
+	return NewLocalEstimator(url, prompts), nil
+}
+
+// EstimateFromImages sends the base64 photos to the local model
+func (e *LocalEstimator) EstimateFromImages(ctx context.Context, images []models.ImageInput, hints string) (*models.EstimateResult, error) {
+	if len(images) == 0 {
+		return nil, errNoImages
+	}
+
+	prompt, version, err := e.prompts.ImagePrompt(ctx, len(images), hints)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := make([]localImage, len(images))
+	for i, image := range images {
+		encoded[i] = localImage{Data: base64.StdEncoding.EncodeToString(image.Data), MimeType: image.MimeType}
+	}
+
+	payload := localRequest{
+		Prompt:   prompt,
+		Image:    encoded[0].Data,
+		MimeType: encoded[0].MimeType,
+		Hints:    hints,
+	}
+	if len(encoded) > 1 {
+		payload.Images = encoded
+	}
+	return e.post(ctx, version, payload)
+}
+
+// EstimateFromText sends the meal description to the local model
+func (e *LocalEstimator) EstimateFromText(ctx context.Context, description string) (*models.EstimateResult, error) {
+	prompt, version, err := e.prompts.TextPrompt(ctx, description)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.post(ctx, version, localRequest{
+		Prompt:      prompt,
+		Description: description,
+	})
+}
+
+// post sends the request and parses the EstimateResult JSON reply
+func (e *LocalEstimator) post(ctx context.Context, promptVersion string, payload localRequest) (*models.EstimateResult, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode local model request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create local model request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("local model call failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local model response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if len(respBody) > maxProviderErrorBody {
+			respBody = respBody[:maxProviderErrorBody]
+		}
+		return nil, fmt.Errorf("local model returned %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	result, err := parseEstimateResponse("local model", string(respBody))
+	if err != nil {
+		return nil, err
+	}
+	result.PromptVersion = promptVersion
+	return result, nil
+}
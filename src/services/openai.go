@@ -0,0 +1,186 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/freezind/telegram-calories-bot/src/models"
+)
+
+const (
+	// defaultOpenAIBaseURL is used when OPENAI_BASE_URL is not set
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+	// defaultOpenAIModel is used when OPENAI_MODEL is not set
+	defaultOpenAIModel = "gpt-4o-mini"
+
+	// maxProviderErrorBody bounds how much of an error response is included in error messages
+	maxProviderErrorBody = 512
+)
+
+// OpenAIEstimator estimates calories via any OpenAI-compatible chat completions endpoint
+// Works with OpenAI, Azure-style proxies, OpenRouter, vLLM, Ollama and similar servers
+type OpenAIEstimator struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	prompts    *Prompts
+	httpClient *http.Client
+}
+
+// NewOpenAIEstimator creates an estimator for the given OpenAI-compatible endpoint
+// baseURL is the API root (e.g. https://api.openai.com/v1); apiKey may be empty for local servers
+// prompts may be nil to use DefaultPrompts()
+func NewOpenAIEstimator(baseURL, apiKey, model string, prompts *Prompts) *OpenAIEstimator {
+	if prompts == nil {
+		prompts = DefaultPrompts()
+	}
+	return &OpenAIEstimator{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		model:      model,
+		prompts:    prompts,
+		httpClient: &http.Client{},
+	}
+}
+
+// NewOpenAIEstimatorFromEnv configures an OpenAIEstimator from OPENAI_BASE_URL, OPENAI_API_KEY and OPENAI_MODEL
+// OPENAI_API_KEY is required unless OPENAI_BASE_URL points at a custom server
+func NewOpenAIEstimatorFromEnv(prompts *Prompts) (*OpenAIEstimator, error) {
+	baseURL := os.Getenv("OPENAI_BASE_URL")
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+		if apiKey == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY environment variable not set")
+		}
+	}
+
+	model := os.Getenv("OPENAI_MODEL")
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+
+	return NewOpenAIEstimator(baseURL, apiKey, model, prompts), nil
+}
+
+// openAIMessage is a chat message with multimodal content parts
+type openAIMessage struct {
+	Role    string          `json:"role"`
+	Content []openAIContent `json:"content"`
+}
+
+// openAIContent is a single text or image_url content part
+type openAIContent struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *openAIImageURL `json:"image_url,omitempty"`
+}
+
+// openAIImageURL carries an image as a data: URL
+type openAIImageURL struct {
+	URL string `json:"url"`
+}
+
+// openAIRequest is the chat completions request body
+type openAIRequest struct {
+	Model          string            `json:"model"`
+	Messages       []openAIMessage   `json:"messages"`
+	Temperature    float64           `json:"temperature"`
+	ResponseFormat map[string]string `json:"response_format,omitempty"`
+}
+
+// openAIResponse is the subset of the chat completions response we read
+type openAIResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+}
+
+// EstimateFromImages sends each photo as a base64 data URL alongside the estimation prompt
+func (e *OpenAIEstimator) EstimateFromImages(ctx context.Context, images []models.ImageInput, hints string) (*models.EstimateResult, error) {
+	if len(images) == 0 {
+		return nil, errNoImages
+	}
+
+	prompt, version, err := e.prompts.ImagePrompt(ctx, len(images), hints)
+	if err != nil {
+		return nil, err
+	}
+
+	content := []openAIContent{{Type: "text", Text: prompt}}
+	for _, image := range images {
+		dataURL := "data:" + image.MimeType + ";base64," + base64.StdEncoding.EncodeToString(image.Data)
+		content = append(content, openAIContent{Type: "image_url", ImageURL: &openAIImageURL{URL: dataURL}})
+	}
+	return e.complete(ctx, version, content)
+}
+
+// EstimateFromText sends the meal description with the estimation prompt
+func (e *OpenAIEstimator) EstimateFromText(ctx context.Context, description string) (*models.EstimateResult, error) {
+	prompt, version, err := e.prompts.TextPrompt(ctx, description)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.complete(ctx, version, []openAIContent{
+		{Type: "text", Text: prompt},
+	})
+}
+
+// complete runs a single chat completion and parses the JSON estimate
+func (e *OpenAIEstimator) complete(ctx context.Context, promptVersion string, content []openAIContent) (*models.EstimateResult, error) {
+	body, err := json.Marshal(openAIRequest{
+		Model:          e.model,
+		Messages:       []openAIMessage{{Role: "user", Content: content}},
+		Temperature:    0.2, // Low temperature for deterministic output
+		ResponseFormat: map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAI request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenAI request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI API call failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxProviderErrorBody))
+		return nil, fmt.Errorf("OpenAI API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+
+	var completion openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return nil, fmt.Errorf("failed to decode OpenAI response: %w", err)
+	}
+	if len(completion.Choices) == 0 || completion.Choices[0].Message.Content == "" {
+		return nil, fmt.Errorf("no response from OpenAI API")
+	}
+
+	result, err := parseEstimateResponse("OpenAI", completion.Choices[0].Message.Content)
+	if err != nil {
+		return nil, err
+	}
+	result.PromptVersion = promptVersion
+	return result, nil
+}
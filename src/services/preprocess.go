@@ -0,0 +1,247 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"os"
+	"strconv"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // Register WebP decoder for image.Decode
+)
+
+const (
+	// DefaultImageMaxEdge is the longest side (px) images are downsized to before estimation
+	DefaultImageMaxEdge = 1568
+
+	// DefaultImageMaxBytes rejects uploads larger than this (Telegram's bot download limit is 20 MB)
+	DefaultImageMaxBytes = 20 << 20
+
+	// DefaultImageQuality is the JPEG quality used when re-encoding
+	DefaultImageQuality = 85
+
+	// maxImagePixels guards against decompression bombs (tiny files with huge dimensions)
+	maxImagePixels = 50_000_000
+)
+
+var (
+	// ErrImageTooLarge is returned when an upload exceeds the configured byte or pixel limit
+	ErrImageTooLarge = errors.New("image too large")
+
+	// ErrUnsupportedImage is returned when the bytes are not a decodable JPEG, PNG or WebP image
+	ErrUnsupportedImage = errors.New("unsupported or corrupt image")
+)
+
+// ImageOptions configures the preprocessing applied before images reach the estimator
+type ImageOptions struct {
+	MaxEdge  int   // Longest side in pixels after downsizing (0 = DefaultImageMaxEdge)
+	MaxBytes int64 // Upload size limit in bytes (0 = DefaultImageMaxBytes)
+	Quality  int   // JPEG re-encode quality 1-100 (0 = DefaultImageQuality)
+}
+
+// ImagePreprocessor normalizes uploads: decode, auto-orient, downsize, re-encode as JPEG
+// Re-encoding drops all metadata, so EXIF (including GPS) never leaves the bot
+type ImagePreprocessor struct {
+	opts ImageOptions
+}
+
+// NewImagePreprocessor creates a preprocessor, filling zero options with defaults
+func NewImagePreprocessor(opts ImageOptions) *ImagePreprocessor {
+	if opts.MaxEdge <= 0 {
+		opts.MaxEdge = DefaultImageMaxEdge
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultImageMaxBytes
+	}
+	if opts.Quality <= 0 || opts.Quality > 100 {
+		opts.Quality = DefaultImageQuality
+	}
+	return &ImagePreprocessor{opts: opts}
+}
+
+// NewImagePreprocessorFromEnv configures a preprocessor from IMAGE_MAX_EDGE, IMAGE_MAX_BYTES and IMAGE_JPEG_QUALITY
+func NewImagePreprocessorFromEnv() (*ImagePreprocessor, error) {
+	var opts ImageOptions
+	for _, setting := range []struct {
+		name   string
+		target func(int)
+	}{
+		{"IMAGE_MAX_EDGE", func(v int) { opts.MaxEdge = v }},
+		{"IMAGE_MAX_BYTES", func(v int) { opts.MaxBytes = int64(v) }},
+		{"IMAGE_JPEG_QUALITY", func(v int) { opts.Quality = v }},
+	} {
+		raw := os.Getenv(setting.name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value <= 0 {
+			return nil, fmt.Errorf("invalid %s %q: must be a positive integer", setting.name, raw)
+		}
+		setting.target(value)
+	}
+	return NewImagePreprocessor(opts), nil
+}
+
+// MaxBytes returns the upload size limit so callers can reject before downloading
+func (p *ImagePreprocessor) MaxBytes() int64 {
+	return p.opts.MaxBytes
+}
+
+// Process returns the normalized JPEG bytes and mime type for an uploaded image
+// Returns ErrImageTooLarge or ErrUnsupportedImage for uploads that should not reach the model
+func (p *ImagePreprocessor) Process(data []byte) ([]byte, string, error) {
+	if int64(len(data)) > p.opts.MaxBytes {
+		return nil, "", fmt.Errorf("%w: %d bytes exceeds the %d byte limit", ErrImageTooLarge, len(data), p.opts.MaxBytes)
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, "", fmt.Errorf("%w: %dx%d pixels", ErrImageTooLarge, config.Width, config.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+
+	img := orient(p.downsize(src), exifOrientation(data))
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: p.opts.Quality}); err != nil {
+		return nil, "", fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), "image/jpeg", nil
+}
+
+// downsize scales src so its longest side is at most MaxEdge, flattening transparency onto white
+func (p *ImagePreprocessor) downsize(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if longest := max(width, height); longest > p.opts.MaxEdge {
+		width = max(1, width*p.opts.MaxEdge/longest)
+		height = max(1, height*p.opts.MaxEdge/longest)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	if width == bounds.Dx() && height == bounds.Dy() {
+		draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Over)
+	} else {
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+	}
+	return dst
+}
+
+// orient applies an EXIF orientation (1-8) so the image is stored upright
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		// 5-8 involve a 90° turn, so width and height swap
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // Mirrored horizontally
+				sx, sy = w-1-x, y
+			case 3: // Rotated 180°
+				sx, sy = w-1-x, h-1-y
+			case 4: // Mirrored vertically
+				sx, sy = x, h-1-y
+			case 5: // Transposed
+				sx, sy = y, x
+			case 6: // Needs 90° clockwise rotation
+				sx, sy = y, h-1-x
+			case 7: // Transversed
+				sx, sy = w-1-y, h-1-x
+			case 8: // Needs 90° counter-clockwise rotation
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, sy):src.PixOffset(sx, sy)+4])
+		}
+	}
+	return dst
+}
+
+// exifOrientation reads the EXIF Orientation tag from a JPEG (1 when absent or unreadable)
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	// Walk JPEG segments until the APP1 Exif segment or the start of image data
+	for pos := 2; pos+4 <= len(data); {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == 0xDA || marker == 0xD9 { // Start of scan / end of image
+			return 1
+		}
+		size := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + size
+		if size < 2 || end > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : end]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		pos = end
+	}
+	return 1
+}
+
+// tiffOrientation finds tag 0x0112 in IFD0 of a TIFF-structured EXIF block
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8:]))
+			if value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/freezind/telegram-calories-bot/prompts"
+)
+
+// DefaultPromptVersion is the estimation prompt used when PROMPT_VERSION is not set
+const DefaultPromptVersion = "v1"
+
+// PromptVars are per-user values available to estimation templates
+type PromptVars struct {
+	Locale       string // Profile locale, e.g. "en-GB", "es-MX" (empty if not set)
+	Units        string // e.g. "metric", "imperial"
+	DietaryNotes string // Free-text profile notes, e.g. "vegetarian"
+	Language     string // Language for item names and reasoning, e.g. "Spanish" (empty for English)
+}
+
+// promptData is the full template input
+type promptData struct {
+	PromptVars
+	Description string // Meal description ("text" template)
+	ImageCount  int    // Number of photos of the meal ("image" template)
+	Hints       string // User notes about the photographed meal ("image" template, may be empty)
+	Error       string // Validation failure ("repair" template)
+}
+
+// PromptRequest identifies who an estimate is for so the right prompt version and vars are used
+type PromptRequest struct {
+	UserID int64
+	Vars   PromptVars
+}
+
+// promptRequestKey is the context key for PromptRequest
+type promptRequestKey struct{}
+
+// WithPromptRequest attaches the requesting user's prompt settings to ctx
+func WithPromptRequest(ctx context.Context, req PromptRequest) context.Context {
+	return context.WithValue(ctx, promptRequestKey{}, req)
+}
+
+// promptRequestFrom returns the PromptRequest on ctx (zero value if none)
+func promptRequestFrom(ctx context.Context) PromptRequest {
+	req, _ := ctx.Value(promptRequestKey{}).(PromptRequest)
+	return req
+}
+
+// Prompts holds the parsed estimation prompt versions and the A/B assignment
+type Prompts struct {
+	versions map[string]*template.Template
+	active   string
+
+	// Users whose hash bucket (0-99) is below experimentPercent get experiment instead of active
+	experiment        string
+	experimentPercent int
+}
+
+// LoadPrompts parses every estimation/<version>.tmpl in fsys
+func LoadPrompts(fsys fs.FS, active string) (*Prompts, error) {
+	files, err := fs.Glob(fsys, "estimation/*.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt templates: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no estimation prompt templates found")
+	}
+
+	p := &Prompts{versions: map[string]*template.Template{}, active: active}
+	for _, file := range files {
+		version := strings.TrimSuffix(path.Base(file), ".tmpl")
+		tmpl, err := template.New(version).Option("missingkey=error").ParseFS(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse prompt %s: %w", file, err)
+		}
+		for _, name := range []string{"image", "text", "repair"} {
+			if tmpl.Lookup(name) == nil {
+				return nil, fmt.Errorf("prompt %s is missing the %q template", file, name)
+			}
+		}
+		p.versions[version] = tmpl
+	}
+
+	if _, ok := p.versions[active]; !ok {
+		return nil, fmt.Errorf("unknown prompt version %q (available: %s)", active, strings.Join(p.Versions(), ", "))
+	}
+
+	return p, nil
+}
+
+// NewPromptsFromEnv loads prompts from PROMPTS_DIR (default: templates embedded in the binary)
+// PROMPT_VERSION selects the active version; PROMPT_EXPERIMENT_VERSION and
+// PROMPT_EXPERIMENT_PERCENT (0-100) route a stable share of users to a second version
+func NewPromptsFromEnv() (*Prompts, error) {
+	var fsys fs.FS = prompts.FS
+	if dir := os.Getenv("PROMPTS_DIR"); dir != "" {
+		fsys = os.DirFS(dir)
+	}
+
+	active := os.Getenv("PROMPT_VERSION")
+	if active == "" {
+		active = DefaultPromptVersion
+	}
+
+	p, err := LoadPrompts(fsys, active)
+	if err != nil {
+		return nil, err
+	}
+
+	if experiment := os.Getenv("PROMPT_EXPERIMENT_VERSION"); experiment != "" {
+		percent, err := strconv.Atoi(os.Getenv("PROMPT_EXPERIMENT_PERCENT"))
+		if err != nil {
+			return nil, fmt.Errorf("PROMPT_EXPERIMENT_PERCENT must be an integer 0-100 when PROMPT_EXPERIMENT_VERSION is set")
+		}
+		if err := p.SetExperiment(experiment, percent); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf("[PROMPTS] Active version %s (experiment: %q at %d%%)", p.active, p.experiment, p.experimentPercent)
+	return p, nil
+}
+
+// SetExperiment routes percent% of users (by stable user-ID hash) to version
+func (p *Prompts) SetExperiment(version string, percent int) error {
+	if _, ok := p.versions[version]; !ok {
+		return fmt.Errorf("unknown experiment prompt version %q", version)
+	}
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("experiment percent must be 0-100, got %d", percent)
+	}
+	p.experiment = version
+	p.experimentPercent = percent
+	return nil
+}
+
+// Versions lists the loaded prompt versions in sorted order
+func (p *Prompts) Versions() []string {
+	versions := make([]string, 0, len(p.versions))
+	for v := range p.versions {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// VersionFor returns the prompt version assigned to a user
+// Assignment is deterministic so a user always sees the same variant
+func (p *Prompts) VersionFor(userID int64) string {
+	if p.experiment == "" || p.experimentPercent == 0 {
+		return p.active
+	}
+	if userBucket(userID) < p.experimentPercent {
+		return p.experiment
+	}
+	return p.active
+}
+
+// userBucket maps a user ID to a stable bucket in [0, 100)
+func userBucket(userID int64) int {
+	h := fnv.New32a()
+	h.Write([]byte(strconv.FormatInt(userID, 10)))
+	return int(h.Sum32() % 100)
+}
+
+// render executes a named template of the given version
+func (p *Prompts) render(version, name string, data promptData) (string, error) {
+	tmpl, ok := p.versions[version]
+	if !ok {
+		return "", fmt.Errorf("unknown prompt version %q", version)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt %s/%s: %w", version, name, err)
+	}
+	return buf.String(), nil
+}
+
+// ImagePrompt renders the photo prompt for the user on ctx, returning the text and version used
+// imageCount > 1 asks for one combined estimate across all photos of the meal; hints may be empty
+func (p *Prompts) ImagePrompt(ctx context.Context, imageCount int, hints string) (string, string, error) {
+	req := promptRequestFrom(ctx)
+	version := p.VersionFor(req.UserID)
+	text, err := p.render(version, "image", promptData{PromptVars: req.Vars, ImageCount: imageCount, Hints: hints})
+	return text, version, err
+}
+
+// TextPrompt renders the description prompt for the user on ctx, returning the text and version used
+func (p *Prompts) TextPrompt(ctx context.Context, description string) (string, string, error) {
+	req := promptRequestFrom(ctx)
+	version := p.VersionFor(req.UserID)
+	text, err := p.render(version, "text", promptData{PromptVars: req.Vars, Description: description})
+	return text, version, err
+}
+
+// RepairPrompt renders the follow-up sent when a reply fails validation
+func (p *Prompts) RepairPrompt(version string, cause error) (string, error) {
+	return p.render(version, "repair", promptData{Error: cause.Error()})
+}
+
+var (
+	defaultPromptsOnce sync.Once
+	defaultPrompts     *Prompts
+)
+
+// DefaultPrompts returns the embedded templates with DefaultPromptVersion active
+// Used by estimators constructed without an explicit *Prompts
+func DefaultPrompts() *Prompts {
+	defaultPromptsOnce.Do(func() {
+		p, err := LoadPrompts(prompts.FS, DefaultPromptVersion)
+		if err != nil {
+			// Embedded templates are validated by tests; a failure here is a build defect
+			panic(fmt.Sprintf("embedded prompts are invalid: %v", err))
+		}
+		defaultPrompts = p
+	})
+	return defaultPrompts
+}
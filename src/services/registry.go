@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/freezind/telegram-calories-bot/src/models"
+)
+
+const (
+	// DefaultProviders is the provider chain used when ESTIMATOR_PROVIDERS is not set
+	DefaultProviders = "gemini"
+
+	// DefaultProviderTimeout bounds a single provider attempt before falling back to the next
+	DefaultProviderTimeout = 45 * time.Second
+)
+
+// Provider is a named estimator backend in a fallback chain
+type Provider struct {
+	Name      string
+	Estimator Estimator
+	Timeout   time.Duration // Per-attempt timeout (0 = DefaultProviderTimeout)
+}
+
+// FallbackEstimator tries each provider in priority order until one succeeds
+// The winning provider's name is recorded on EstimateResult.Provider
+type FallbackEstimator struct {
+	providers []Provider
+}
+
+// NewFallbackEstimator creates an estimator over providers in priority order
+func NewFallbackEstimator(providers ...Provider) (*FallbackEstimator, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("at least one estimator provider is required")
+	}
+	return &FallbackEstimator{providers: providers}, nil
+}
+
+// EstimateFromImages estimates from meal photos, falling back across providers
+func (f *FallbackEstimator) EstimateFromImages(ctx context.Context, images []models.ImageInput, hints string) (*models.EstimateResult, error) {
+	return f.run(ctx, func(ctx context.Context, e Estimator) (*models.EstimateResult, error) {
+		return e.EstimateFromImages(ctx, images, hints)
+	})
+}
+
+// EstimateFromText estimates from a meal description, falling back across providers
+func (f *FallbackEstimator) EstimateFromText(ctx context.Context, description string) (*models.EstimateResult, error) {
+	return f.run(ctx, func(ctx context.Context, e Estimator) (*models.EstimateResult, error) {
+		return e.EstimateFromText(ctx, description)
+	})
+}
+
+// run calls each provider with its own timeout and returns the first success
+func (f *FallbackEstimator) run(ctx context.Context, call func(context.Context, Estimator) (*models.EstimateResult, error)) (*models.EstimateResult, error) {
+	var errs []error
+
+	for _, p := range f.providers {
+		timeout := p.Timeout
+		if timeout <= 0 {
+			timeout = DefaultProviderTimeout
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		result, err := call(attemptCtx, p.Estimator)
+		cancel()
+
+		if err == nil {
+			result.Provider = p.Name
+			log.Printf("[ESTIMATOR] %s succeeded in %v", p.Name, time.Since(start))
+			return result, nil
+		}
+
+		log.Printf("[ESTIMATOR] %s failed after %v: %v", p.Name, time.Since(start), err)
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+
+		// Caller gave up; remaining providers would fail the same way
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, fmt.Errorf("all estimator providers failed: %w", errors.Join(errs...))
+}
+
+// providerFactories builds each supported provider from its environment variables
+var providerFactories = map[string]func(*Prompts) (Estimator, error){
+	"gemini": func(prompts *Prompts) (Estimator, error) {
+		client, err := NewGeminiClient(prompts)
+		if err != nil {
+			return nil, err
+		}
+		return NewGeminiEstimator(client), nil
+	},
+	"openai": func(prompts *Prompts) (Estimator, error) {
+		return NewOpenAIEstimatorFromEnv(prompts)
+	},
+	"local": func(prompts *Prompts) (Estimator, error) {
+		return NewLocalEstimatorFromEnv(prompts)
+	},
+}
+
+// NewEstimatorFromEnv builds the provider chain from ESTIMATOR_PROVIDERS
+// e.g. ESTIMATOR_PROVIDERS=gemini,openai,local (priority order, default "gemini")
+// ESTIMATOR_TIMEOUT (Go duration, default 45s) bounds each provider attempt
+// All providers render the same versioned prompts
+func NewEstimatorFromEnv(prompts *Prompts) (*FallbackEstimator, error) {
+	names := os.Getenv("ESTIMATOR_PROVIDERS")
+	if names == "" {
+		names = DefaultProviders
+	}
+
+	timeout := DefaultProviderTimeout
+	if raw := os.Getenv("ESTIMATOR_TIMEOUT"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid ESTIMATOR_TIMEOUT %q: must be a positive duration like 30s", raw)
+		}
+		timeout = parsed
+	}
+
+	var providers []Provider
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		factory, ok := providerFactories[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown estimator provider %q (supported: gemini, openai, local)", name)
+		}
+		estimator, err := factory(prompts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize %s provider: %w", name, err)
+		}
+		providers = append(providers, Provider{Name: name, Estimator: estimator, Timeout: timeout})
+	}
+
+	log.Printf("[ESTIMATOR] Provider chain: %s (timeout %v each)", names, timeout)
+	return NewFallbackEstimator(providers...)
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/freezind/telegram-calories-bot/src/models"
+)
+
+// maxLoggedResponse bounds how much of a malformed model reply is echoed in errors
+const maxLoggedResponse = 500
+
+// parseEstimateResponse decodes a provider's JSON reply into a validated EstimateResult
+// provider is only used to label errors
+func parseEstimateResponse(provider, text string) (*models.EstimateResult, error) {
+	jsonText, ok := ExtractJSONObject(text)
+	if !ok {
+		return nil, fmt.Errorf("no JSON object in %s response (response: %s)", provider, truncateResponse(text))
+	}
+
+	// Unmarshal JSON to EstimateResult
+	var result models.EstimateResult
+	if err := json.Unmarshal([]byte(jsonText), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse %s JSON response: %w (response: %s)", provider, err, truncateResponse(jsonText))
+	}
+
+	// Total is derived from the per-item breakdown
+	result.SyncCalories()
+
+	// Validate result per data-model.md
+	if err := result.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid result from %s: %w", provider, err)
+	}
+
+	return &result, nil
+}
+
+// ExtractJSONObject finds the JSON object in a model reply
+// Returns the whole reply when it is valid JSON (after trimming markdown fences),
+// otherwise the first balanced {...} that parses, skipping surrounding prose
+func ExtractJSONObject(text string) (string, bool) {
+	trimmed := strings.TrimSpace(text)
+	trimmed = strings.TrimPrefix(trimmed, "```json")
+	trimmed = strings.TrimPrefix(trimmed, "```")
+	trimmed = strings.TrimSuffix(trimmed, "```")
+	trimmed = strings.TrimSpace(trimmed)
+	if strings.HasPrefix(trimmed, "{") && json.Valid([]byte(trimmed)) {
+		return trimmed, true
+	}
+
+	for start := strings.IndexByte(text, '{'); start >= 0; {
+		if end := balancedObjectEnd(text, start); end > 0 {
+			if candidate := text[start:end]; json.Valid([]byte(candidate)) {
+				return candidate, true
+			}
+		}
+		next := strings.IndexByte(text[start+1:], '{')
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+
+	return "", false
+}
+
+// balancedObjectEnd returns the index just past the brace closing the object at start, or -1
+// Braces inside JSON strings are ignored
+func balancedObjectEnd(text string, start int) int {
+	depth := 0
+	inString, escaped := false, false
+
+	for i := start; i < len(text); i++ {
+		c := text[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+
+	return -1
+}
+
+// truncateResponse shortens a model reply for error messages
+func truncateResponse(text string) string {
+	if len(text) <= maxLoggedResponse {
+		return text
+	}
+	return text[:maxLoggedResponse] + "..."
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// RetryPolicy controls how transient provider errors are retried
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first (values < 1 mean 1)
+	BaseDelay   time.Duration // Backoff before the second attempt; doubles each retry
+	MaxDelay    time.Duration // Upper bound on a single backoff and on server retry hints (<= 0 means no cap)
+}
+
+// DefaultRetryPolicy retries transient errors up to 3 times with jittered exponential backoff
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// Do calls fn until it succeeds, returns a non-retryable error, or attempts run out
+// Server retry hints (google.rpc.RetryInfo) take precedence over computed backoff
+func (p RetryPolicy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	attempts := max(p.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+
+		retry, hint := retryableError(err)
+		if !retry || attempt >= attempts || ctx.Err() != nil {
+			return err
+		}
+
+		delay := p.Backoff(attempt)
+		if hint > 0 {
+			delay = p.capDelay(hint)
+		}
+
+		// Don't sleep past the caller's deadline just to fail afterwards
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return err
+		}
+
+		log.Printf("[RETRY] Attempt %d/%d failed, retrying in %v: %v", attempt, attempts, delay, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// Backoff returns a full-jitter exponential delay for the given (1-based) failed attempt
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+	// BaseDelay doubled attempt-1 times, saturating instead of overflowing
+	ceiling := time.Duration(math.MaxInt64)
+	if shift := attempt - 1; shift < 63 && p.BaseDelay <= math.MaxInt64>>shift {
+		ceiling = p.BaseDelay << shift
+	}
+	ceiling = p.capDelay(ceiling)
+
+	// Full jitter spreads out retries from concurrent requests hitting the same limit
+	return time.Duration(rand.Int64N(int64(ceiling)) + 1)
+}
+
+// capDelay limits a positive delay to MaxDelay, if one is set
+func (p RetryPolicy) capDelay(delay time.Duration) time.Duration {
+	if p.MaxDelay > 0 {
+		return min(delay, p.MaxDelay)
+	}
+	return delay
+}
+
+// retryableError reports whether err is transient and any server-suggested delay
+func retryableError(err error) (bool, time.Duration) {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true, retryInfoDelay(apiErr.Details)
+		}
+		return false, 0
+	}
+
+	// A single attempt timing out is worth another try while the caller still has time
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true, 0
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr), 0
+}
+
+// retryInfoDelay extracts google.rpc.RetryInfo.retryDelay (e.g. "37s") from error details
+func retryInfoDelay(details []map[string]any) time.Duration {
+	for _, detail := range details {
+		if detail["@type"] != "type.googleapis.com/google.rpc.RetryInfo" {
+			continue
+		}
+		raw, ok := detail["retryDelay"].(string)
+		if !ok {
+			continue
+		}
+		if delay, err := time.ParseDuration(raw); err == nil {
+			return delay
+		}
+	}
+	return 0
+}
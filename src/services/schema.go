@@ -0,0 +1,85 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/freezind/telegram-calories-bot/src/models"
+	"google.golang.org/genai"
+)
+
+// estimateSchema is the Gemini response schema for EstimateResult, derived once at startup
+var estimateSchema = SchemaFor(reflect.TypeOf(models.EstimateResult{}))
+
+// EstimateResultSchema returns the response schema sent with every Gemini estimation request
+func EstimateResultSchema() *genai.Schema {
+	return estimateSchema
+}
+
+// SchemaFor derives a Gemini response schema from a Go type using its json tags
+// Fields without omitempty are required; pointers are nullable
+// The `schema` tag supports "-" (exclude), "enum=a|b|c" (allowed string values) and
+// "required" (a pointer field the model must fill with a non-null value)
+func SchemaFor(t reflect.Type) *genai.Schema {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+
+	schema := &genai.Schema{}
+	if nullable {
+		schema.Nullable = genai.Ptr(true)
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		schema.Type = genai.TypeString
+	case reflect.Bool:
+		schema.Type = genai.TypeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema.Type = genai.TypeInteger
+	case reflect.Float32, reflect.Float64:
+		schema.Type = genai.TypeNumber
+	case reflect.Slice, reflect.Array:
+		schema.Type = genai.TypeArray
+		schema.Items = SchemaFor(t.Elem())
+	case reflect.Struct:
+		schema.Type = genai.TypeObject
+		schema.Properties = map[string]*genai.Schema{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() || field.Tag.Get("schema") == "-" {
+				continue
+			}
+
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			prop := SchemaFor(field.Type)
+			if enum, ok := strings.CutPrefix(field.Tag.Get("schema"), "enum="); ok {
+				prop.Enum = strings.Split(enum, "|")
+			}
+
+			required := !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Pointer
+			if field.Tag.Get("schema") == "required" {
+				prop.Nullable = nil
+				required = true
+			}
+
+			schema.Properties[name] = prop
+			schema.PropertyOrdering = append(schema.PropertyOrdering, name)
+			if required {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+
+	return schema
+}
@@ -5,86 +5,324 @@ import (
 	"sync"
 	"time"
 
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
 	"github.com/freezind/telegram-calories-bot/src/models"
 )
 
-// SessionManager manages in-memory user sessions using sync.Map (thread-safe)
+// staleSessionAge is how long a session may stay inactive before CleanupStale removes it
+const staleSessionAge = 15 * time.Minute
+
+// SessionManager manages in-memory user sessions (thread-safe)
 // Implements state transitions per data-model.md state machine
 type SessionManager struct {
-	sessions sync.Map // map[int64]*models.UserSession
+	// mu guards every session and all of its fields; album photos, auto-save timers
+	// and button taps for the same user run concurrently
+	mu       sync.Mutex
+	sessions map[int64]*models.UserSession
+
+	// pendingSeq numbers pending estimates (guarded by mu)
+	pendingSeq int
 }
 
 // NewSessionManager creates a new session manager instance
 func NewSessionManager() *SessionManager {
-	return &SessionManager{}
+	return &SessionManager{sessions: make(map[int64]*models.UserSession)}
 }
 
-// GetSession retrieves a user's session, creating a new one if it doesn't exist
-func (sm *SessionManager) GetSession(userID int64) *models.UserSession {
-	if val, ok := sm.sessions.Load(userID); ok {
-		session, ok := val.(*models.UserSession)
-		if !ok {
-			// This should never happen, but handle gracefully
-			session = &models.UserSession{
-				UserID:       userID,
-				State:        models.StateIdle,
-				LastActivity: time.Now(),
-			}
-			sm.sessions.Store(userID, session)
-			return session
+// session returns the user's live session, creating one in Idle state if needed, and
+// updates its activity timestamp; the caller must hold mu
+func (sm *SessionManager) session(userID int64) *models.UserSession {
+	session, ok := sm.sessions[userID]
+	if !ok {
+		session = &models.UserSession{
+			UserID: userID,
+			State:  models.StateIdle,
 		}
-		session.LastActivity = time.Now() // Update activity timestamp
-		return session
+		sm.sessions[userID] = session
 	}
+	session.LastActivity = time.Now()
+	return session
+}
 
-	// Create new session in Idle state
-	session := &models.UserSession{
-		UserID:       userID,
-		State:        models.StateIdle,
-		LastActivity: time.Now(),
+// snapshot copies a session so callers can read it without holding mu
+func snapshot(session *models.UserSession) *models.UserSession {
+	copied := *session
+	copied.Images = append([]models.ImageInput(nil), session.Images...)
+	copied.Hints = append([]string(nil), session.Hints...)
+	if session.Pending != nil {
+		copied.Pending = copyPending(session.Pending)
 	}
-	sm.sessions.Store(userID, session)
-	return session
+	return &copied
 }
 
-// UpdateSession updates a user's session state
+// GetSession returns a copy of a user's session, creating a new one if it doesn't exist
+// Changes to the copy are not stored; use the SessionManager methods to modify a session
+func (sm *SessionManager) GetSession(userID int64) *models.UserSession {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return snapshot(sm.session(userID))
+}
+
+// UpdateSession updates a user's session state and returns a copy of the session
 // Validates state transitions per data-model.md state machine
 func (sm *SessionManager) UpdateSession(userID int64, state models.SessionState) *models.UserSession {
-	session := sm.GetSession(userID)
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	session := sm.session(userID)
 	session.State = state
-	session.LastActivity = time.Now()
-	sm.sessions.Store(userID, session)
-	return session
+	return snapshot(session)
 }
 
 // SetMessageID updates the message ID for a user's session
 func (sm *SessionManager) SetMessageID(userID int64, messageID int) {
-	session := sm.GetSession(userID)
-	session.MessageID = messageID
-	sm.sessions.Store(userID, session)
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.session(userID).MessageID = messageID
+}
+
+// TransitionState moves the session from one state to another in a single step
+// Reports false and leaves the session unchanged if it is not in the from state
+func (sm *SessionManager) TransitionState(userID int64, from, to models.SessionState) bool {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	session := sm.session(userID)
+	if session.State != from {
+		return false
+	}
+	session.State = to
+	return true
+}
+
+// TakeMessageID returns the session's current message ID and clears it
+// Returns 0 if there is none
+func (sm *SessionManager) TakeMessageID(userID int64) int {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	session := sm.session(userID)
+	messageID := session.MessageID
+	session.MessageID = 0
+	return messageID
+}
+
+// ImageResult is the outcome of AddImage
+type ImageResult int
+
+const (
+	ImageAdded         ImageResult = iota
+	ImageLimitReached              // MaxMealImages photos are already collected
+	ImageNotCollecting             // The session stopped accepting photos, e.g. the meal is being estimated
+)
+
+// AddImage appends a collected photo and moves the session to CollectingImages
+// The photo is only added while the session is awaiting or collecting photos; startMeal
+// also accepts it in any other state except Processing (one-shot albums start a meal)
+// Returns the number of photos collected, the outcome and whether the photo starts a new
+// album or is a single photo, so callers can acknowledge an album once instead of per photo
+func (sm *SessionManager) AddImage(userID int64, image models.ImageInput, albumID string, startMeal bool) (count int, result ImageResult, announce bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	session := sm.session(userID)
+	switch session.State {
+	case models.StateAwaitingImage, models.StateCollectingImages:
+	case models.StateProcessing:
+		return len(session.Images), ImageNotCollecting, false
+	default:
+		if !startMeal {
+			return len(session.Images), ImageNotCollecting, false
+		}
+	}
+	if len(session.Images) >= models.MaxMealImages {
+		return len(session.Images), ImageLimitReached, false
+	}
+
+	announce = albumID == "" || albumID != session.AlbumID
+	session.Images = append(session.Images, image)
+	session.AlbumID = albumID
+	session.State = models.StateCollectingImages
+	return len(session.Images), ImageAdded, announce
+}
+
+// SeenAlbum records albumID on the session and reports whether it was already recorded
+// Used to reply once per album to photos that are not being collected
+func (sm *SessionManager) SeenAlbum(userID int64, albumID string) bool {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	session := sm.session(userID)
+	if session.AlbumID == albumID {
+		return true
+	}
+	session.AlbumID = albumID
+	return false
+}
+
+// AddHint records a caption or text note for the meal being photographed
+func (sm *SessionManager) AddHint(userID int64, hint string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	session := sm.session(userID)
+	session.Hints = append(session.Hints, hint)
+}
+
+// TakeMeal returns the collected photos and hints and clears them from the session
+func (sm *SessionManager) TakeMeal(userID int64) ([]models.ImageInput, []string) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	session := sm.session(userID)
+	images, hints := session.Images, session.Hints
+	session.Images = nil
+	session.AlbumID = ""
+	session.Hints = nil
+	return images, hints
+}
+
+// SetPending stores a delivered estimate awaiting confirmation, replacing any previous one
+// Returns a copy of the pending estimate, whose ID is used with TakePending, and the estimate
+// it replaced (nil if none) so the caller can retire its buttons
+func (sm *SessionManager) SetPending(userID int64, result *models.EstimateResult, note string, nextState models.SessionState, mealType internalmodels.MealType) (pending, replaced *models.PendingEstimate) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	sm.pendingSeq++
+	session := sm.session(userID)
+	replaced = session.Pending
+	session.Pending = &models.PendingEstimate{
+		ID:          sm.pendingSeq,
+		Original:    result.Clone(),
+		Result:      result.Clone(),
+		Note:        note,
+		NextState:   nextState,
+		MealType:    mealType,
+		DeliveredAt: time.Now(),
+	}
+	return copyPending(session.Pending), replaced
+}
+
+// pending returns the live pending estimate if it matches id (0 matches any); the caller must hold mu
+func (sm *SessionManager) pending(userID int64, id int) *models.PendingEstimate {
+	pending := sm.session(userID).Pending
+	if pending == nil || (id != 0 && pending.ID != id) {
+		return nil
+	}
+	return pending
+}
+
+// SetPendingMealType changes the meal type of the pending estimate and returns a copy of it
+// Returns nil if nothing is pending or, for a non-zero id, the pending estimate is another one
+func (sm *SessionManager) SetPendingMealType(userID int64, id int, mealType internalmodels.MealType) *models.PendingEstimate {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	pending := sm.pending(userID, id)
+	if pending == nil {
+		return nil
+	}
+	pending.MealType = mealType
+	return copyPending(pending)
+}
+
+// AdjustPending replaces the pending result with adjust(original) and returns a copy of it
+// Returns nil if nothing is pending or, for a non-zero id, the pending estimate is another one
+func (sm *SessionManager) AdjustPending(userID int64, id int, adjust func(original *models.EstimateResult) *models.EstimateResult) *models.PendingEstimate {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	pending := sm.pending(userID, id)
+	if pending == nil {
+		return nil
+	}
+	pending.Result = adjust(pending.Original)
+	return copyPending(pending)
+}
+
+// HasPending reports whether the user has an estimate awaiting confirmation
+// A non-zero id only matches that estimate
+func (sm *SessionManager) HasPending(userID int64, id int) bool {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	return sm.pending(userID, id) != nil
+}
+
+// SetPendingMessage records the message showing the pending estimate with the given id
+func (sm *SessionManager) SetPendingMessage(userID int64, id, messageID int) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if pending := sm.pending(userID, id); pending != nil {
+		pending.MessageID = messageID
+	}
+}
+
+// AddPendingMenu records an Adjust menu posted for the pending estimate with the given id
+func (sm *SessionManager) AddPendingMenu(userID int64, id, messageID int) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if pending := sm.pending(userID, id); pending != nil {
+		pending.MenuIDs = append(pending.MenuIDs, messageID)
+	}
+}
+
+// copyPending copies a pending estimate so callers can read it without holding mu
+func copyPending(pending *models.PendingEstimate) *models.PendingEstimate {
+	copied := *pending
+	copied.Result = pending.Result.Clone()
+	copied.MenuIDs = append([]int(nil), pending.MenuIDs...)
+	return &copied
+}
+
+// TakePending removes and returns the pending estimate
+// A non-zero id only matches that estimate, so a stale timer never takes a newer one
+func (sm *SessionManager) TakePending(userID int64, id int) *models.PendingEstimate {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	pending := sm.pending(userID, id)
+	if pending == nil {
+		return nil
+	}
+	sm.session(userID).Pending = nil
+	return pending
 }
 
 // DeleteSession removes a user's session from memory
 // Called on Cancel button or after result delivery
 func (sm *SessionManager) DeleteSession(userID int64) {
-	sm.sessions.Delete(userID)
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	delete(sm.sessions, userID)
 }
 
 // CleanupStale removes sessions inactive for more than 15 minutes
-// Prevents memory leaks from abandoned sessions
+// Prevents memory leaks from abandoned sessions; sessions holding an unconfirmed
+// estimate are kept so Save and auto-save still find it
 func (sm *SessionManager) CleanupStale() int {
+	return sm.CleanupInactiveSince(time.Now().Add(-staleSessionAge))
+}
+
+// CleanupInactiveSince removes sessions with no activity since cutoff, except those
+// holding an unconfirmed estimate, and returns how many were removed
+func (sm *SessionManager) CleanupInactiveSince(cutoff time.Time) int {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
 	count := 0
-	sm.sessions.Range(func(key, value interface{}) bool {
-		session, ok := value.(*models.UserSession)
-		if !ok {
-			return true // skip invalid entries
-		}
-		if time.Since(session.LastActivity) > 15*time.Minute {
-			sm.sessions.Delete(key)
+	for userID, session := range sm.sessions {
+		if session.Pending == nil && session.LastActivity.Before(cutoff) {
+			delete(sm.sessions, userID)
 			count++
 		}
-		return true // continue iteration
-	})
+	}
 	return count
 }
 
@@ -0,0 +1,218 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/src/handlers"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	"github.com/freezind/telegram-calories-bot/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for confirming estimates before they are saved
+// Tests: Save/Adjust/Discard buttons, portion multipliers, manual kcal entry, auto-save, scaling
+
+func TestConfirm_SavesOnlyOnSave(t *testing.T) {
+	h, sender, _, _, store := newTextTestHandler()
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log eggs and toast", "eggs", "and", "toast")))
+	assert.Contains(t, sender.last().text, "Save this meal to your log?")
+	require.NotNil(t, sender.last().markup)
+	row := sender.last().markup.InlineKeyboard[0]
+	assert.Equal(t, []string{"save", "adjust", "discard"}, []string{row[0].Unique, row[1].Unique, row[2].Unique})
+	assert.Empty(t, logsFor(store, 42), "nothing saved before confirmation")
+
+	require.NoError(t, h.HandleSave(newCallbackContext(42, "save")))
+	assert.Contains(t, sender.last().text, "Saved: 235 kcal")
+	assert.Len(t, logsFor(store, 42), 1)
+
+	// A second tap on the same message does not save twice
+	require.NoError(t, h.HandleSave(newCallbackContext(42, "save")))
+	assert.Len(t, logsFor(store, 42), 1)
+}
+
+func TestConfirm_DiscardAndReEstimateSaveNothing(t *testing.T) {
+	h, sender, _, _, store := newTextTestHandler()
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log soup", "soup")))
+	require.NoError(t, h.HandleDiscard(newCallbackContext(42, "discard")))
+	assert.Contains(t, sender.last().text, "discarded")
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log pasta", "pasta")))
+	require.NoError(t, h.HandleReEstimate(newCallbackContext(42, "re_estimate")))
+	require.NoError(t, h.HandleSave(newCallbackContext(42, "save")))
+	assert.Empty(t, logsFor(store, 42), "the wrong estimate is never saved")
+
+	// Only the corrected estimate ends up in the log
+	require.NoError(t, h.HandleText(newTextContext(42, "pasta with pesto")))
+	require.NoError(t, h.HandleSave(newCallbackContext(42, "save")))
+	assert.Len(t, logsFor(store, 42), 1)
+}
+
+func TestConfirm_OlderResultButtonsRejected(t *testing.T) {
+	h, sender, _, _, store := newTextTestHandler()
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log soup", "soup")))
+	first := len(sender.sent)
+	saveFirst := sender.button(first, "save")
+	require.NotEmpty(t, saveFirst.Data, "buttons carry the estimate ID")
+	require.NoError(t, h.HandleAdjust(newTapContext(42, first, sender.button(first, "adjust"))))
+	menu := len(sender.sent)
+
+	// A new estimate replaces the unsaved one and retires its buttons
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log pasta", "pasta")))
+	assert.Contains(t, sender.last().text, "replaced without saving")
+	second := len(sender.sent) - 1 // "Analyzing..." edited into the result, above the notice
+	assert.Nil(t, sender.sent[first-1].markup)
+	assert.Nil(t, sender.sent[menu-1].markup, "Adjust menu retired too")
+
+	// Buttons of the first result no longer act on the second estimate
+	require.NoError(t, h.HandleSave(newTapContext(42, first, saveFirst)))
+	require.NoError(t, h.HandleScale(newTapContext(42, menu, sender.button(menu, "scale_2"))))
+	assert.Empty(t, logsFor(store, 42))
+
+	require.NoError(t, h.HandleSave(newTapContext(42, second, sender.button(second, "save"))))
+	logs := logsFor(store, 42)
+	require.Len(t, logs, 1)
+	assert.Equal(t, 235, logs[0].Calories, "not scaled by the old menu")
+}
+
+func TestConfirm_SaveRetiresAdjustMenu(t *testing.T) {
+	h, sender, _, _, _ := newTextTestHandler()
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log soup", "soup")))
+	result := len(sender.sent)
+	require.NoError(t, h.HandleAdjust(newTapContext(42, result, sender.button(result, "adjust"))))
+	menu := len(sender.sent)
+	require.NotNil(t, sender.sent[menu-1].markup)
+
+	require.NoError(t, h.HandleSave(newTapContext(42, result, sender.button(result, "save"))))
+	assert.Nil(t, sender.sent[result-1].markup)
+	assert.Nil(t, sender.sent[menu-1].markup)
+}
+
+func TestConfirm_PortionMultipliers(t *testing.T) {
+	h, sender, _, _, store := newTextTestHandler()
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log eggs and toast", "eggs", "and", "toast")))
+	result := len(sender.sent) - 1
+	require.NoError(t, h.HandleAdjust(newCallbackContext(42, "adjust")))
+	require.NotNil(t, sender.last().markup)
+	assert.Equal(t, "scale_0.5", sender.last().markup.InlineKeyboard[0][0].Unique)
+
+	// The result message is updated in place
+	require.NoError(t, h.HandleScale(newCallbackContext(42, "scale_2")))
+	assert.Contains(t, sender.sent[result].text, "470 kcal")
+
+	// Multipliers apply to the original estimate rather than compounding
+	require.NoError(t, h.HandleScale(newCallbackContext(42, "scale_0.5")))
+	assert.Contains(t, sender.sent[result].text, "118 kcal")
+
+	require.NoError(t, h.HandleSave(newCallbackContext(42, "save")))
+	logs := logsFor(store, 42)
+	require.Len(t, logs, 1)
+	assert.Equal(t, 118, logs[0].Calories)
+	assert.Equal(t, 50.0, logs[0].FoodItems[0].PortionGrams)
+}
+
+func TestConfirm_ManualCalories(t *testing.T) {
+	h, sender, _, sm, store := newTextTestHandler()
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log eggs and toast", "eggs", "and", "toast")))
+	require.NoError(t, h.HandleManualCalories(newCallbackContext(42, "manual_kcal")))
+	assert.Equal(t, models.StateAwaitingCalories, sm.GetSession(42).State)
+
+	require.NoError(t, h.HandleText(newTextContext(42, "lots")))
+	assert.Contains(t, sender.last().text, "Please send a number")
+
+	require.NoError(t, h.HandleText(newTextContext(42, "400 kcal")))
+	assert.Contains(t, sender.last().text, "400 kcal")
+	assert.Equal(t, models.StateAwaitingDescription, sm.GetSession(42).State, "back in the flow the estimate came from")
+
+	require.NoError(t, h.HandleSave(newCallbackContext(42, "save")))
+	logs := logsFor(store, 42)
+	require.Len(t, logs, 1)
+	assert.Equal(t, 400, logs[0].Calories, "items still add up to the entered total")
+}
+
+func TestConfirm_AutoSave(t *testing.T) {
+	h, _, _, _, store := newTextTestHandler()
+	h.SetAutoSave(20 * time.Millisecond)
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log soup", "soup")))
+	assert.Eventually(t, func() bool { return len(logsFor(store, 42)) == 1 }, time.Second, 5*time.Millisecond)
+
+	// A discarded estimate is not saved when its timer fires
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log pasta", "pasta")))
+	require.NoError(t, h.HandleDiscard(newCallbackContext(42, "discard")))
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, logsFor(store, 42), 1)
+}
+
+func TestConfirm_AutoSaveKeepsDeliveryTime(t *testing.T) {
+	h, _, _, _, store := newTextTestHandler()
+	h.SetAutoSave(50 * time.Millisecond)
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log soup", "soup")))
+	delivered := time.Now()
+	require.Eventually(t, func() bool { return len(logsFor(store, 42)) == 1 }, time.Second, 5*time.Millisecond)
+
+	// The meal is logged when it was shown, not when the timer fired
+	assert.False(t, logsFor(store, 42)[0].Timestamp.After(delivered))
+}
+
+func TestConfirm_WithoutStorage(t *testing.T) {
+	sender := &fakeSender{}
+	h := handlers.NewEstimateHandler(sender, services.NewSessionManager(), newFakeEstimator(), nil, nil, nil)
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log soup", "soup")))
+	require.NotNil(t, sender.last().markup)
+	assert.Equal(t, "re_estimate", sender.last().markup.InlineKeyboard[0][0].Unique)
+	assert.NotContains(t, sender.last().text, "Save this meal")
+}
+
+func TestAutoSaveDelayFromEnv(t *testing.T) {
+	t.Setenv("ESTIMATE_AUTOSAVE_AFTER", "")
+	delay, err := handlers.AutoSaveDelayFromEnv()
+	require.NoError(t, err)
+	assert.Zero(t, delay)
+
+	t.Setenv("ESTIMATE_AUTOSAVE_AFTER", "10m")
+	delay, err = handlers.AutoSaveDelayFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, delay)
+
+	t.Setenv("ESTIMATE_AUTOSAVE_AFTER", "soon")
+	_, err = handlers.AutoSaveDelayFromEnv()
+	assert.Error(t, err)
+}
+
+func TestEstimateResult_ScaledTo(t *testing.T) {
+	fiber := 4.0
+	original := &models.EstimateResult{
+		FoodItems: []models.FoodItem{
+			{Name: "Rice", PortionGrams: 150, Calories: intPtr(195)},
+			{Name: "Chicken", PortionGrams: 100, Calories: intPtr(165)},
+			{Name: "Sauce", PortionGrams: 20, Calories: intPtr(41)},
+		},
+		Calories: 401,
+		Macros:   &models.Macros{Protein: 35, Carbs: 45, Fat: 8, Fiber: &fiber},
+	}
+
+	scaled := original.ScaledTo(500)
+	assert.Equal(t, 500, scaled.Calories)
+	total, ok := internalmodels.ItemCalories(models.LogItems(scaled.FoodItems))
+	assert.True(t, ok)
+	assert.Equal(t, 500, total)
+	assert.InDelta(t, 187, scaled.FoodItems[0].PortionGrams, 0.1)
+	assert.InDelta(t, 43.6, scaled.Macros.Protein, 0.1)
+	assert.InDelta(t, 5, *scaled.Macros.Fiber, 0.1)
+
+	// The original is left untouched
+	assert.Equal(t, 401, original.Calories)
+	assert.Equal(t, 195, *original.FoodItems[0].Calories)
+	assert.Equal(t, 4.0, *original.Macros.Fiber)
+}
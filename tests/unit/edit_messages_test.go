@@ -0,0 +1,77 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/freezind/telegram-calories-bot/src/handlers"
+	"github.com/freezind/telegram-calories-bot/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	telebot "gopkg.in/telebot.v3"
+)
+
+// Unit tests for editing bot messages in place
+// Tests: "Analyzing" becomes the result, stale buttons removed after Cancel/Save, fallback when editing fails
+
+func TestEditMessages_AnalyzingBecomesResult(t *testing.T) {
+	h, sender, _, _, _ := newTextTestHandler()
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log eggs and toast", "eggs", "and", "toast")))
+	require.Len(t, sender.sent, 1, "no second message for the result")
+	assert.Equal(t, []int{1}, sender.edited)
+	assert.Contains(t, sender.sent[0].text, "235 kcal")
+	assert.NotNil(t, sender.sent[0].markup)
+	assert.Empty(t, sender.deleted)
+}
+
+func TestEditMessages_ErrorReplacesAnalyzing(t *testing.T) {
+	h, sender, estimator, _, _ := newTextTestHandler()
+	estimator.err = errors.New("boom")
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log soup", "soup")))
+	require.Len(t, sender.sent, 1)
+	assert.Contains(t, sender.sent[0].text, "API error")
+	assert.Nil(t, sender.sent[0].markup)
+}
+
+func TestEditMessages_CancelStripsButtons(t *testing.T) {
+	h, sender, _, _, _ := newTextTestHandler()
+
+	require.NoError(t, h.HandleEstimate(newTextContext(42, "/estimate")))
+	require.NotNil(t, sender.sent[0].markup)
+
+	require.NoError(t, h.HandleCancel(newCallbackContext(42, "cancel")))
+	assert.Nil(t, sender.sent[0].markup, "Cancel button removed from the prompt")
+	assert.Contains(t, sender.last().text, "Estimation canceled")
+}
+
+func TestEditMessages_SaveStripsButtons(t *testing.T) {
+	h, sender, _, _, _ := newTextTestHandler()
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log soup", "soup")))
+	require.NoError(t, h.HandleSave(newCallbackContext(42, "save")))
+
+	assert.Contains(t, sender.sent[0].text, "235 kcal", "result stays visible")
+	assert.Nil(t, sender.sent[0].markup)
+}
+
+// editFailingSender rejects every edit, e.g. when the original message was deleted by the user
+type editFailingSender struct {
+	fakeSender
+}
+
+func (f *editFailingSender) Edit(msg telebot.Editable, what interface{}, opts ...interface{}) (*telebot.Message, error) {
+	return nil, errors.New("message to edit not found")
+}
+
+func TestEditMessages_FallsBackToSend(t *testing.T) {
+	sender := &editFailingSender{}
+	store := storage.NewMemoryStorage()
+	h := handlers.NewEstimateHandler(sender, services.NewSessionManager(), newFakeEstimator(), store, store, nil)
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log soup", "soup")))
+	require.Len(t, sender.sent, 2)
+	assert.Contains(t, sender.last().text, "235 kcal")
+}
@@ -0,0 +1,199 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/freezind/telegram-calories-bot/prompts"
+	"github.com/freezind/telegram-calories-bot/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for the estimate cache
+// Tests: exact hits, prompt-variant isolation, TTL and LRU bounds, perceptual matches for resized copies
+
+// testPhoto draws a deterministic "plate" scene; seed shifts the layout
+func testPhoto(width, height, seed int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			// Background gradient plus a bright disc whose position depends on seed
+			v := uint8((x*255/width + y*128/height) / 2)
+			dx, dy := x-width*(seed%3+1)/4, y-height/2
+			if dx*dx+dy*dy < (height/3)*(height/3) {
+				v = 240 - v/4
+			}
+			img.Set(x, y, color.RGBA{v, v / 2, 255 - v, 255})
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// resizedJPEG downsizes with nearest-neighbour sampling and recompresses, like a forwarded Telegram photo
+func resizedJPEG(t *testing.T, src image.Image, width, height int) []byte {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			dst.Set(x, y, src.At(x*bounds.Dx()/width, y*bounds.Dy()/height))
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 60}))
+	return buf.Bytes()
+}
+
+func TestCachingEstimator_ExactHit(t *testing.T) {
+	inner := newFakeEstimator()
+	cache := services.NewCachingEstimator(inner, nil, services.CacheOptions{})
+	photo := []byte{0xFF, 0xD8, 0x01, 0x02}
+
+	first, err := cache.EstimateFromImages(context.Background(), oneImage(photo, "image/jpeg"), "")
+	require.NoError(t, err)
+	first.Calories = 1 // Callers may mutate their copy
+
+	second, err := cache.EstimateFromImages(context.Background(), oneImage(photo, "image/jpeg"), "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, inner.images, "second call served from cache")
+	assert.Equal(t, 235, second.Calories, "cached result is not shared with callers")
+
+	_, err = cache.EstimateFromImages(context.Background(), oneImage(photo, "image/png"), "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, inner.images, "mime type is part of the key")
+
+	_, err = cache.EstimateFromText(context.Background(), "eggs")
+	require.NoError(t, err)
+	_, err = cache.EstimateFromText(context.Background(), "eggs")
+	require.NoError(t, err)
+	assert.Len(t, inner.descriptions, 2, "text estimates are not cached")
+}
+
+func TestCachingEstimator_KeyedByPromptVariant(t *testing.T) {
+	p, err := services.LoadPrompts(prompts.FS, "v1")
+	require.NoError(t, err)
+	require.NoError(t, p.SetExperiment("v2", 50))
+
+	// Find one user on each variant
+	var controlUser, experimentUser int64
+	for id := int64(1); controlUser == 0 || experimentUser == 0; id++ {
+		if p.VersionFor(id) == "v2" {
+			experimentUser = id
+		} else {
+			controlUser = id
+		}
+	}
+
+	inner := newFakeEstimator()
+	cache := services.NewCachingEstimator(inner, p, services.CacheOptions{})
+	photo := []byte("same photo")
+
+	for _, userID := range []int64{controlUser, experimentUser, controlUser} {
+		ctx := services.WithPromptRequest(context.Background(), services.PromptRequest{UserID: userID})
+		_, err := cache.EstimateFromImages(ctx, oneImage(photo, "image/jpeg"), "")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, inner.images, "one call per prompt version")
+}
+
+func TestCachingEstimator_ErrorsNotCached(t *testing.T) {
+	inner := newFakeEstimator()
+	inner.err = errors.New("quota exceeded")
+	cache := services.NewCachingEstimator(inner, nil, services.CacheOptions{})
+
+	_, err := cache.EstimateFromImages(context.Background(), oneImage([]byte{1}, "image/jpeg"), "")
+	require.Error(t, err)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestCachingEstimator_TTLAndSizeBound(t *testing.T) {
+	inner := newFakeEstimator()
+	cache := services.NewCachingEstimator(inner, nil, services.CacheOptions{TTL: 30 * time.Millisecond, MaxEntries: 2})
+	ctx := context.Background()
+
+	for _, photo := range []string{"a", "b", "c"} {
+		_, err := cache.EstimateFromImages(ctx, oneImage([]byte(photo), "image/jpeg"), "")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, cache.Len(), "oldest entry evicted")
+
+	_, err := cache.EstimateFromImages(ctx, oneImage([]byte("a"), "image/jpeg"), "")
+	require.NoError(t, err)
+	assert.Equal(t, 4, inner.images, "evicted entry is recomputed")
+
+	time.Sleep(50 * time.Millisecond)
+	_, err = cache.EstimateFromImages(ctx, oneImage([]byte("c"), "image/jpeg"), "")
+	require.NoError(t, err)
+	assert.Equal(t, 5, inner.images, "expired entry is recomputed")
+}
+
+func TestPerceptualHash_ResizedCopyIsClose(t *testing.T) {
+	original := testPhoto(640, 480, 1)
+
+	a, err := services.PerceptualHash(encodePNG(t, original))
+	require.NoError(t, err)
+	b, err := services.PerceptualHash(resizedJPEG(t, original, 320, 240))
+	require.NoError(t, err)
+	c, err := services.PerceptualHash(encodePNG(t, testPhoto(640, 480, 2)))
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, services.HammingDistance(a, b), services.DefaultPerceptualDistance)
+	assert.Greater(t, services.HammingDistance(a, c), services.DefaultPerceptualDistance)
+
+	_, err = services.PerceptualHash([]byte("not an image"))
+	assert.Error(t, err)
+}
+
+func TestCachingEstimator_PerceptualMode(t *testing.T) {
+	original := testPhoto(640, 480, 1)
+	ctx := context.Background()
+
+	exact := newFakeEstimator()
+	exactCache := services.NewCachingEstimator(exact, nil, services.CacheOptions{})
+	perceptual := newFakeEstimator()
+	perceptualCache := services.NewCachingEstimator(perceptual, nil, services.CacheOptions{Perceptual: true})
+
+	for _, cache := range []*services.CachingEstimator{exactCache, perceptualCache} {
+		_, err := cache.EstimateFromImages(ctx, oneImage(encodePNG(t, original), "image/png"), "")
+		require.NoError(t, err)
+		_, err = cache.EstimateFromImages(ctx, oneImage(resizedJPEG(t, original, 320, 240), "image/jpeg"), "")
+		require.NoError(t, err)
+		_, err = cache.EstimateFromImages(ctx, oneImage(encodePNG(t, testPhoto(640, 480, 2)), "image/png"), "")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, exact.images, "exact mode misses recompressed copies")
+	assert.Equal(t, 2, perceptual.images, "perceptual mode reuses the resized copy but not a different photo")
+}
+
+func TestNewCachingEstimatorFromEnv(t *testing.T) {
+	inner := newFakeEstimator()
+
+	t.Setenv("ESTIMATE_CACHE_TTL", "0")
+	estimator, err := services.NewCachingEstimatorFromEnv(inner, nil)
+	require.NoError(t, err)
+	assert.Same(t, inner, estimator, "TTL 0 disables the cache")
+
+	t.Setenv("ESTIMATE_CACHE_TTL", "1h")
+	t.Setenv("ESTIMATE_CACHE_MODE", "fuzzy")
+	_, err = services.NewCachingEstimatorFromEnv(inner, nil)
+	assert.Error(t, err)
+
+	t.Setenv("ESTIMATE_CACHE_MODE", "perceptual")
+	estimator, err = services.NewCachingEstimatorFromEnv(inner, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &services.CachingEstimator{}, estimator)
+}
@@ -20,7 +20,7 @@ func TestEstimateResult_Validate_ValidResults(t *testing.T) {
 			result: models.EstimateResult{
 				Calories:   450,
 				Confidence: "high",
-				FoodItems:  []string{"Chicken", "Rice"},
+				FoodItems:  []models.FoodItem{{Name: "Chicken"}, {Name: "Rice"}},
 			},
 		},
 		{
@@ -28,7 +28,7 @@ func TestEstimateResult_Validate_ValidResults(t *testing.T) {
 			result: models.EstimateResult{
 				Calories:   300,
 				Confidence: "medium",
-				FoodItems:  []string{"Salad"},
+				FoodItems:  []models.FoodItem{{Name: "Salad"}},
 			},
 		},
 		{
@@ -36,7 +36,7 @@ func TestEstimateResult_Validate_ValidResults(t *testing.T) {
 			result: models.EstimateResult{
 				Calories:   100,
 				Confidence: "low",
-				FoodItems:  []string{},
+				FoodItems:  []models.FoodItem{},
 			},
 		},
 		{
@@ -44,7 +44,7 @@ func TestEstimateResult_Validate_ValidResults(t *testing.T) {
 			result: models.EstimateResult{
 				Calories:   0,
 				Confidence: "low",
-				FoodItems:  []string{},
+				FoodItems:  []models.FoodItem{},
 			},
 		},
 	}
@@ -108,7 +108,7 @@ func TestEstimateResult_HasFood(t *testing.T) {
 			name: "has food - with items and calories",
 			result: models.EstimateResult{
 				Calories:  450,
-				FoodItems: []string{"Chicken", "Rice"},
+				FoodItems: []models.FoodItem{{Name: "Chicken"}, {Name: "Rice"}},
 			},
 			expected: true,
 		},
@@ -116,7 +116,7 @@ func TestEstimateResult_HasFood(t *testing.T) {
 			name: "no food - empty items",
 			result: models.EstimateResult{
 				Calories:  0,
-				FoodItems: []string{},
+				FoodItems: []models.FoodItem{},
 			},
 			expected: false,
 		},
@@ -124,7 +124,7 @@ func TestEstimateResult_HasFood(t *testing.T) {
 			name: "no food - zero calories with items",
 			result: models.EstimateResult{
 				Calories:  0,
-				FoodItems: []string{"Something"},
+				FoodItems: []models.FoodItem{{Name: "Something"}},
 			},
 			expected: false,
 		},
@@ -132,7 +132,7 @@ func TestEstimateResult_HasFood(t *testing.T) {
 			name: "no food - calories but empty items",
 			result: models.EstimateResult{
 				Calories:  100,
-				FoodItems: []string{},
+				FoodItems: []models.FoodItem{},
 			},
 			expected: false,
 		},
@@ -156,7 +156,7 @@ func TestFormatResult(t *testing.T) {
 			result: models.EstimateResult{
 				Calories:   450,
 				Confidence: "high",
-				FoodItems:  []string{"Chicken", "Rice", "Vegetables"},
+				FoodItems:  []models.FoodItem{{Name: "Chicken"}, {Name: "Rice"}, {Name: "Vegetables"}},
 			},
 			contains: []string{"450 kcal", "High", "Chicken", "Rice", "Vegetables"},
 		},
@@ -165,7 +165,7 @@ func TestFormatResult(t *testing.T) {
 			result: models.EstimateResult{
 				Calories:   0,
 				Confidence: "low",
-				FoodItems:  []string{},
+				FoodItems:  []models.FoodItem{},
 			},
 			contains: []string{"0 kcal", "Low", "None detected"},
 		},
@@ -173,10 +173,53 @@ func TestFormatResult(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			formatted := models.FormatResult(&tt.result)
+			formatted := models.FormatResult(english, &tt.result)
 			for _, expected := range tt.contains {
 				assert.Contains(t, formatted, expected)
 			}
 		})
 	}
 }
+
+func TestEstimateResult_Validate_Macros(t *testing.T) {
+	fiber := 5.0
+	tooMuchSugar := 60.0
+
+	valid := models.EstimateResult{
+		Calories:   450,
+		Confidence: "high",
+		Macros:     &models.Macros{Protein: 40, Carbs: 45, Fat: 10, Fiber: &fiber},
+	}
+	assert.NoError(t, valid.Validate())
+
+	negative := models.EstimateResult{
+		Calories:   450,
+		Confidence: "high",
+		Macros:     &models.Macros{Protein: -1, Carbs: 45, Fat: 10},
+	}
+	assert.ErrorContains(t, negative.Validate(), "macros must be non-negative")
+
+	sugarOverCarbs := models.EstimateResult{
+		Calories:   450,
+		Confidence: "high",
+		Macros:     &models.Macros{Protein: 40, Carbs: 45, Fat: 10, Sugar: &tooMuchSugar},
+	}
+	assert.ErrorContains(t, sugarOverCarbs.Validate(), "sugar must be between 0 and carbs")
+}
+
+func TestFormatResult_WithMacros(t *testing.T) {
+	fiber := 5.0
+	result := models.EstimateResult{
+		Calories:   450,
+		Confidence: "high",
+		FoodItems:  []models.FoodItem{{Name: "Chicken"}},
+		Macros:     &models.Macros{Protein: 48, Carbs: 42, Fat: 8, Fiber: &fiber},
+	}
+
+	formatted := models.FormatResult(english, &result)
+	assert.Contains(t, formatted, "Macros: P 48g · C 42g · F 8g · Fiber 5g")
+	assert.NotContains(t, formatted, "Sugar")
+
+	result.Macros = nil
+	assert.NotContains(t, models.FormatResult(english, &result), "Macros")
+}
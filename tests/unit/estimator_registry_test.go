@@ -0,0 +1,169 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/freezind/telegram-calories-bot/src/handlers"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	"github.com/freezind/telegram-calories-bot/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for the multi-provider estimator chain
+// Tests: fallback on error/timeout, provider recorded on result and log, OpenAI-compatible and local backends
+
+const registryEstimateJSON = `{"calories": 0, "confidence": "high", "items": [{"name": "Banana", "grams": 120, "calories": 105}], "reasoning": "One banana"}`
+
+// blockingEstimator never answers until its context is cancelled
+type blockingEstimator struct{}
+
+func (blockingEstimator) EstimateFromImages(ctx context.Context, images []models.ImageInput, hints string) (*models.EstimateResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (blockingEstimator) EstimateFromText(ctx context.Context, description string) (*models.EstimateResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestFallbackEstimator_FallsBackOnError(t *testing.T) {
+	primary := newFakeEstimator()
+	primary.err = errors.New("503 unavailable")
+	backup := newFakeEstimator()
+
+	chain, err := services.NewFallbackEstimator(
+		services.Provider{Name: "primary", Estimator: primary},
+		services.Provider{Name: "backup", Estimator: backup},
+	)
+	require.NoError(t, err)
+
+	result, err := chain.EstimateFromText(context.Background(), "eggs")
+	require.NoError(t, err)
+	assert.Equal(t, "backup", result.Provider)
+	assert.Equal(t, []string{"eggs"}, primary.descriptions)
+	assert.Equal(t, []string{"eggs"}, backup.descriptions)
+}
+
+func TestFallbackEstimator_FallsBackOnTimeout(t *testing.T) {
+	chain, err := services.NewFallbackEstimator(
+		services.Provider{Name: "slow", Estimator: blockingEstimator{}, Timeout: 20 * time.Millisecond},
+		services.Provider{Name: "fast", Estimator: newFakeEstimator()},
+	)
+	require.NoError(t, err)
+
+	result, err := chain.EstimateFromImages(context.Background(), oneImage([]byte{0xFF}, "image/jpeg"), "")
+	require.NoError(t, err)
+	assert.Equal(t, "fast", result.Provider)
+}
+
+func TestFallbackEstimator_AllFail(t *testing.T) {
+	first := newFakeEstimator()
+	first.err = errors.New("quota exceeded")
+	second := newFakeEstimator()
+	second.err = errors.New("connection refused")
+
+	chain, err := services.NewFallbackEstimator(
+		services.Provider{Name: "first", Estimator: first},
+		services.Provider{Name: "second", Estimator: second},
+	)
+	require.NoError(t, err)
+
+	_, err = chain.EstimateFromText(context.Background(), "soup")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "first: quota exceeded")
+	assert.Contains(t, err.Error(), "second: connection refused")
+
+	_, err = services.NewFallbackEstimator()
+	assert.Error(t, err)
+}
+
+func TestNewEstimatorFromEnv_RejectsUnknownProvider(t *testing.T) {
+	t.Setenv("ESTIMATOR_PROVIDERS", "gemini,carrier-pigeon")
+	t.Setenv("GEMINI_API_KEY", "test-key")
+
+	_, err := services.NewEstimatorFromEnv(services.DefaultPrompts())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "carrier-pigeon")
+}
+
+func TestOpenAIEstimator_ImageRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "vision-model", body["model"])
+		encoded, _ := json.Marshal(body["messages"])
+		assert.Contains(t, string(encoded), "data:image/png;base64,iVBO")
+
+		reply, _ := json.Marshal(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"content": registryEstimateJSON}}},
+		})
+		w.Write(reply)
+	}))
+	defer server.Close()
+
+	estimator := services.NewOpenAIEstimator(server.URL+"/v1/", "sk-test", "vision-model", nil)
+	result, err := estimator.EstimateFromImages(context.Background(), oneImage([]byte{0x89, 'P', 'N', 'G'}, "image/png"), "")
+	require.NoError(t, err)
+	assert.Equal(t, 105, result.Calories, "total derived from items")
+	require.Len(t, result.FoodItems, 1)
+	assert.Equal(t, "Banana", result.FoodItems[0].Name)
+}
+
+func TestOpenAIEstimator_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"rate limited"}`, http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	_, err := services.NewOpenAIEstimator(server.URL, "", "m", nil).EstimateFromText(context.Background(), "toast")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "429")
+}
+
+func TestLocalEstimator_TextRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "a banana", body["description"])
+		assert.True(t, strings.Contains(body["prompt"], "a banana"))
+		w.Write([]byte(registryEstimateJSON))
+	}))
+	defer server.Close()
+
+	result, err := services.NewLocalEstimator(server.URL, nil).EstimateFromText(context.Background(), "a banana")
+	require.NoError(t, err)
+	assert.Equal(t, 105, result.Calories)
+}
+
+func TestEstimateHandler_RecordsProviderOnLog(t *testing.T) {
+	failing := newFakeEstimator()
+	failing.err = errors.New("gemini down")
+	chain, err := services.NewFallbackEstimator(
+		services.Provider{Name: "gemini", Estimator: failing},
+		services.Provider{Name: "local", Estimator: newFakeEstimator()},
+	)
+	require.NoError(t, err)
+
+	store := storage.NewMemoryStorage()
+	h := handlers.NewEstimateHandler(&fakeSender{}, services.NewSessionManager(), chain, store, store, nil)
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log eggs and toast", "eggs", "and", "toast")))
+	require.NoError(t, h.HandleSave(newCallbackContext(42, "save")))
+
+	logs := logsFor(store, 42)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "local", logs[0].Provider)
+}
@@ -0,0 +1,173 @@
+package unit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/freezind/telegram-calories-bot/internal/handlers"
+	"github.com/freezind/telegram-calories-bot/internal/middleware"
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	telebot "gopkg.in/telebot.v3"
+)
+
+// Unit tests for exporting a user's log history
+// Tests: CSV and NDJSON writers, date-range filtering, GET /api/export, /export bot command
+
+// exportTestLogs returns two logs on May 1 and May 3 2024 (UTC), newest first
+func exportTestLogs() []internalmodels.Log {
+	fiber := 2.5
+	return []internalmodels.Log{
+		{
+			ID:         "b",
+			FoodItems:  []internalmodels.FoodItem{{Name: "Pasta", PortionGrams: 250, Calories: intPtr(400)}},
+			Calories:   400,
+			Confidence: internalmodels.ConfidenceMedium,
+			MealType:   internalmodels.MealDinner,
+			Note:       "=1+1, extra cheese",
+			Timestamp:  time.Date(2024, 5, 3, 19, 30, 0, 0, time.UTC),
+		},
+		{
+			ID:         "a",
+			FoodItems:  []internalmodels.FoodItem{{Name: "Oats", PortionGrams: 60.5, Calories: intPtr(230)}, {Name: "Coffee"}},
+			Calories:   230,
+			Macros:     &internalmodels.Macros{Protein: 8, Carbs: 40, Fat: 4.5, Fiber: &fiber},
+			Confidence: internalmodels.ConfidenceHigh,
+			MealType:   internalmodels.MealBreakfast,
+			Timestamp:  time.Date(2024, 5, 1, 7, 15, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestWriteExport_CSV(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	logs := internalmodels.ExportLogs(exportTestLogs(), nil, nil)
+	require.NoError(t, internalmodels.WriteExport(&buf, internalmodels.ExportCSV, logs, loc))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{
+		"date", "time", "meal_type", "calories_kcal", "protein_g", "carbs_g", "fat_g", "fiber_g", "sugar_g",
+		"items", "confidence", "note", "id", "timestamp",
+	}, rows[0])
+
+	// Oldest first, local date and time, macros in grams
+	assert.Equal(t, []string{
+		"2024-05-01", "09:15", "breakfast", "230", "8", "40", "4.5", "2.5", "",
+		"Oats (60.5 g, 230 kcal); Coffee", "high", "", "a", "2024-05-01T07:15:00Z",
+	}, rows[1])
+
+	// Notes that look like formulas are not evaluated by spreadsheets
+	assert.Equal(t, "'=1+1, extra cheese", rows[2][11])
+	assert.Equal(t, "", rows[2][4], "unknown macros stay empty")
+}
+
+func TestWriteExport_NDJSON(t *testing.T) {
+	from := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	logs := internalmodels.ExportLogs(exportTestLogs(), &from, nil)
+	require.Len(t, logs, 1)
+
+	var buf bytes.Buffer
+	require.NoError(t, internalmodels.WriteExport(&buf, internalmodels.ExportNDJSON, logs, time.UTC))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 1)
+	var decoded internalmodels.Log
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &decoded))
+	assert.Equal(t, "b", decoded.ID)
+	assert.Equal(t, "=1+1, extra cheese", decoded.Note, "JSON keeps the note as written")
+
+	format, err := internalmodels.ParseExportFormat("jsonl")
+	require.NoError(t, err)
+	assert.Equal(t, internalmodels.ExportNDJSON, format)
+	_, err = internalmodels.ParseExportFormat("xlsx")
+	assert.Error(t, err)
+}
+
+func TestExportHandler_Export(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	for _, l := range exportTestLogs() {
+		l := l
+		require.NoError(t, store.CreateLog(7, &l))
+	}
+	handler := handlers.NewExportHandler(store, store)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(7)))
+		rec := httptest.NewRecorder()
+		handler.Export(rec, req)
+		return rec
+	}
+
+	rec := get("/api/export")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), `attachment; filename="calorie-log-`)
+	rows, err := csv.NewReader(rec.Body).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, rows, 3)
+
+	// The to date is inclusive
+	rec = get("/api/export?format=ndjson&from=2024-05-01&to=2024-05-01")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	scanner := bufio.NewScanner(rec.Body)
+	var ids []string
+	for scanner.Scan() {
+		var l internalmodels.Log
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &l))
+		ids = append(ids, l.ID)
+	}
+	assert.Len(t, ids, 1)
+
+	assert.Equal(t, http.StatusBadRequest, get("/api/export?format=xlsx").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/export?from=yesterday").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/export?from=2024-05-03&to=2024-05-01").Code)
+}
+
+func TestHandleExport(t *testing.T) {
+	h, sender, _, _, store := newTextTestHandler()
+
+	require.NoError(t, h.HandleExport(newTextContext(42, "/export")))
+	assert.Contains(t, sender.last().text, "No entries to export")
+
+	for _, l := range exportTestLogs() {
+		l := l
+		require.NoError(t, store.CreateLog(42, &l))
+	}
+
+	require.NoError(t, h.HandleExport(newTextContext(42, "/export json 2024-05-03", "json", "2024-05-03")))
+	document, ok := sender.last().what.(*telebot.Document)
+	require.True(t, ok, "the export is sent as a document")
+	assert.Equal(t, "application/x-ndjson", document.MIME)
+	assert.True(t, strings.HasSuffix(document.FileName, ".ndjson"), document.FileName)
+	assert.Equal(t, "📤 Your calorie log: 1 entry", document.Caption)
+	content, err := io.ReadAll(document.FileReader)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"note":"=1+1, extra cheese"`)
+
+	require.NoError(t, h.HandleExport(newTextContext(42, "/export")))
+	document, ok = sender.last().what.(*telebot.Document)
+	require.True(t, ok)
+	assert.Equal(t, "text/csv", document.MIME)
+	assert.Equal(t, "📤 Your calorie log: 2 entries", document.Caption)
+
+	require.NoError(t, h.HandleExport(newTextContext(42, "/export csv tomorrow", "csv", "tomorrow")))
+	assert.Contains(t, sender.last().text, "Usage: /export")
+}
@@ -0,0 +1,252 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/src/i18n"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	telebot "gopkg.in/telebot.v3"
+)
+
+// Shared fakes for exercising src/handlers without a live Telegram bot
+
+// english formats messages like the bot does for a user without a language
+var english = i18n.New(i18n.DefaultLanguage, "")
+
+// fakeSender records outgoing messages and implements bot.Sender
+// Edits update the recorded message in sent (message IDs are 1-based indexes into sent)
+// Auto-save timers send from their own goroutine, so access goes through mu
+type fakeSender struct {
+	mu      sync.Mutex
+	sent    []fakeMessage
+	edited  []int // IDs of edited messages, in order
+	deleted []int
+
+	// fileBaseURL serves downloads (e.g. an httptest server); empty means unreachable
+	fileBaseURL string
+}
+
+type fakeMessage struct {
+	text   string
+	markup *telebot.ReplyMarkup
+	what   interface{}
+}
+
+func (f *fakeSender) Send(to telebot.Recipient, what interface{}, opts ...interface{}) (*telebot.Message, error) {
+	msg := fakeMessage{what: what}
+	if text, ok := what.(string); ok {
+		msg.text = text
+	}
+	for _, opt := range opts {
+		if markup, ok := opt.(*telebot.ReplyMarkup); ok {
+			msg.markup = markup
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, msg)
+	return &telebot.Message{ID: len(f.sent), Text: msg.text, Chat: &telebot.Chat{ID: 1}}, nil
+}
+
+func (f *fakeSender) Edit(msg telebot.Editable, what interface{}, opts ...interface{}) (*telebot.Message, error) {
+	edited := fakeMessage{what: what}
+	if text, ok := what.(string); ok {
+		edited.text = text
+	}
+	for _, opt := range opts {
+		if markup, ok := opt.(*telebot.ReplyMarkup); ok {
+			edited.markup = markup
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := f.recordEdit(msg)
+	if id >= 1 && id <= len(f.sent) {
+		f.sent[id-1] = edited
+	}
+	return &telebot.Message{ID: id, Text: edited.text, Chat: &telebot.Chat{ID: 1}}, nil
+}
+
+func (f *fakeSender) EditReplyMarkup(msg telebot.Editable, markup *telebot.ReplyMarkup) (*telebot.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := f.recordEdit(msg)
+	if id >= 1 && id <= len(f.sent) {
+		f.sent[id-1].markup = markup
+	}
+	return &telebot.Message{ID: id, Chat: &telebot.Chat{ID: 1}}, nil
+}
+
+// recordEdit notes the edited message ID; messages this fake never sent (e.g. callback contexts) are only recorded
+// The caller must hold mu
+func (f *fakeSender) recordEdit(msg telebot.Editable) int {
+	sig, _ := msg.MessageSig()
+	id, _ := strconv.Atoi(sig)
+	f.edited = append(f.edited, id)
+	return id
+}
+
+func (f *fakeSender) Delete(msg telebot.Editable) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if m, ok := msg.(*telebot.Message); ok {
+		f.deleted = append(f.deleted, m.ID)
+	}
+	return nil
+}
+
+func (f *fakeSender) Respond(callback *telebot.Callback, resp ...*telebot.CallbackResponse) error {
+	return nil
+}
+
+func (f *fakeSender) FileByID(fileID string) (telebot.File, error) {
+	return telebot.File{FileID: fileID, FilePath: "photos/" + fileID}, nil
+}
+
+func (f *fakeSender) GetFileURL(file telebot.File) string {
+	if f.fileBaseURL != "" {
+		return f.fileBaseURL + "/" + file.FilePath
+	}
+	return "http://127.0.0.1:0/" + file.FilePath
+}
+
+func (f *fakeSender) last() fakeMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.sent) == 0 {
+		return fakeMessage{}
+	}
+	return f.sent[len(f.sent)-1]
+}
+
+// button returns the inline button with the given unique name on message id (1-based)
+func (f *fakeSender) button(id int, unique string) telebot.InlineButton {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if markup := f.sent[id-1].markup; markup != nil {
+		for _, row := range markup.InlineKeyboard {
+			for _, btn := range row {
+				if btn.Unique == unique {
+					return btn
+				}
+			}
+		}
+	}
+	return telebot.InlineButton{}
+}
+
+// fakeContext implements the subset of telebot.Context used by the handlers
+// Calling any other method panics via the nil embedded interface
+type fakeContext struct {
+	telebot.Context
+	user     *telebot.User
+	message  *telebot.Message
+	callback *telebot.Callback
+	args     []string
+}
+
+func newTextContext(userID int64, text string, args ...string) *fakeContext {
+	user := &telebot.User{ID: userID, FirstName: "Test"}
+	return &fakeContext{
+		user:    user,
+		message: &telebot.Message{ID: 100, Text: text, Sender: user, Chat: &telebot.Chat{ID: userID}},
+		args:    args,
+	}
+}
+
+func newCallbackContext(userID int64, data string) *fakeContext {
+	user := &telebot.User{ID: userID, FirstName: "Test"}
+	msg := &telebot.Message{ID: 200, Sender: user, Chat: &telebot.Chat{ID: userID}}
+	return &fakeContext{
+		user:     user,
+		message:  msg,
+		callback: &telebot.Callback{ID: "cb", Data: data, Message: msg, Sender: user},
+	}
+}
+
+func (c *fakeContext) Sender() *telebot.User                           { return c.user }
+func (c *fakeContext) Message() *telebot.Message                       { return c.message }
+func (c *fakeContext) Callback() *telebot.Callback                     { return c.callback }
+func (c *fakeContext) Args() []string                                  { return c.args }
+func (c *fakeContext) Respond(resp ...*telebot.CallbackResponse) error { return nil }
+func (c *fakeContext) Text() string {
+	if c.message == nil {
+		return ""
+	}
+	return c.message.Text
+}
+
+// newTapContext taps btn on the bot message with the given ID, with the callback data Telegram delivers
+func newTapContext(userID int64, messageID int, btn telebot.InlineButton) *fakeContext {
+	c := newCallbackContext(userID, "\f"+btn.Unique+"|"+btn.Data)
+	c.message.ID = messageID
+	return c
+}
+
+// fakeEstimator returns a fixed result and records what it was asked
+type fakeEstimator struct {
+	result       *models.EstimateResult
+	err          error
+	descriptions []string
+	images       int // Number of image estimate calls
+	lastImages   []models.ImageInput
+	lastHints    string
+	lastCtx      context.Context
+}
+
+func (f *fakeEstimator) EstimateFromImages(ctx context.Context, images []models.ImageInput, hints string) (*models.EstimateResult, error) {
+	f.images++
+	f.lastImages, f.lastHints, f.lastCtx = images, hints, ctx
+	return f.copyResult()
+}
+
+func (f *fakeEstimator) EstimateFromText(ctx context.Context, description string) (*models.EstimateResult, error) {
+	f.descriptions = append(f.descriptions, description)
+	f.lastCtx = ctx
+	return f.copyResult()
+}
+
+func (f *fakeEstimator) copyResult() (*models.EstimateResult, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	result := *f.result
+	return &result, nil
+}
+
+func newFakeEstimator() *fakeEstimator {
+	return &fakeEstimator{result: &models.EstimateResult{
+		FoodItems: []models.FoodItem{
+			{Name: "Egg", PortionGrams: 100, Calories: intPtr(155)},
+			{Name: "Toast", PortionGrams: 30, Calories: intPtr(80)},
+		},
+		Calories:   235,
+		Confidence: "medium",
+	}}
+}
+
+// oneImage wraps a single photo for Estimator.EstimateFromImages
+func oneImage(data []byte, mimeType string) []models.ImageInput {
+	return []models.ImageInput{{Data: data, MimeType: mimeType}}
+}
+
+// logsFor is a small helper to list logs and fail loudly on storage errors
+func logsFor(store interface {
+	ListLogs(int64) ([]internalmodels.Log, error)
+}, userID int64) []internalmodels.Log {
+	logs, err := store.ListLogs(userID)
+	if err != nil {
+		panic(fmt.Sprintf("ListLogs failed: %v", err))
+	}
+	return logs
+}
+
+// intPtr returns a pointer to an int value
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,182 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/freezind/telegram-calories-bot/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for GeminiClient retries and concurrency limiting
+// Tests: 429/5xx retried with backoff, RetryInfo hints honored, 4xx not retried, in-flight cap
+
+// geminiOK is a minimal successful generateContent response
+const geminiOK = `{"candidates": [{"content": {"role": "model", "parts": [{"text": "{\"calories\": 95, \"confidence\": \"high\", \"items\": [{\"name\": \"Apple\", \"grams\": 180, \"calories\": 95}]}"}]}}]}`
+
+// geminiError builds a google.rpc-style error body
+func geminiError(code int, status, retryDelay string) string {
+	details := "[]"
+	if retryDelay != "" {
+		details = fmt.Sprintf(`[{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": %q}]`, retryDelay)
+	}
+	return fmt.Sprintf(`{"error": {"code": %d, "message": "test error", "status": %q, "details": %s}}`, code, status, details)
+}
+
+// newTestGeminiClient points a GeminiClient at a fake API server
+func newTestGeminiClient(t *testing.T, handler http.HandlerFunc, opts services.GeminiOptions) *services.GeminiClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	opts.APIKey = "test-key"
+	opts.BaseURL = server.URL
+	client, err := services.NewGeminiClientWithOptions(opts)
+	require.NoError(t, err)
+	return client
+}
+
+// fastRetry keeps test backoff short
+var fastRetry = services.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func TestGeminiClient_RetriesTransientErrors(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestGeminiClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(geminiError(503, "UNAVAILABLE", "")))
+			return
+		}
+		w.Write([]byte(geminiOK))
+	}, services.GeminiOptions{Retry: fastRetry})
+
+	result, err := client.EstimateCaloriesFromText(context.Background(), "an apple")
+	require.NoError(t, err)
+	assert.Equal(t, 95, result.Calories)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestGeminiClient_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestGeminiClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(geminiError(400, "INVALID_ARGUMENT", "")))
+	}, services.GeminiOptions{Retry: fastRetry})
+
+	_, err := client.EstimateCaloriesFromText(context.Background(), "an apple")
+	require.Error(t, err)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestGeminiClient_HonorsRetryInfoHint(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestGeminiClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(geminiError(429, "RESOURCE_EXHAUSTED", "0.02s")))
+			return
+		}
+		w.Write([]byte(geminiOK))
+	}, services.GeminiOptions{
+		// Computed backoff would be up to an hour; the 20ms hint must win
+		Retry: services.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Hour, MaxDelay: time.Hour},
+	})
+
+	start := time.Now()
+	_, err := client.EstimateCaloriesFromText(context.Background(), "an apple")
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestGeminiClient_LimitsConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	client := newTestGeminiClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(geminiOK))
+	}, services.GeminiOptions{MaxConcurrent: 2, Retry: fastRetry})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.EstimateCaloriesFromText(context.Background(), "an apple")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+}
+
+func TestRetryPolicy_StopsWhenDelayExceedsDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	calls := 0
+	policy := services.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Minute, MaxDelay: time.Minute}
+	err := policy.Do(ctx, func(ctx context.Context) error {
+		calls++
+		return context.DeadlineExceeded
+	})
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, 1, calls, "should not wait a minute past a 50ms deadline")
+}
+
+func TestRetryPolicy_BackoffWithoutCap(t *testing.T) {
+	uncapped := services.RetryPolicy{BaseDelay: time.Second}
+	capped := services.RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}
+
+	// Doubling overflows long before attempt 100; the delay saturates instead of panicking
+	for attempt := 1; attempt <= 100; attempt++ {
+		assert.Positive(t, uncapped.Backoff(attempt), attempt)
+		delay := capped.Backoff(attempt)
+		assert.Positive(t, delay, attempt)
+		assert.LessOrEqual(t, delay, time.Minute, attempt)
+	}
+	assert.LessOrEqual(t, uncapped.Backoff(1), time.Second)
+}
+
+func TestGeminiClient_RetryInfoHintWithoutCap(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestGeminiClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(geminiError(429, "RESOURCE_EXHAUSTED", "0.05s")))
+			return
+		}
+		w.Write([]byte(geminiOK))
+	}, services.GeminiOptions{
+		// No MaxDelay means the hint is used as is, not cut to zero
+		Retry: services.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+	})
+
+	start := time.Now()
+	_, err := client.EstimateCaloriesFromText(context.Background(), "an apple")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, int32(2), calls.Load())
+}
@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/freezind/telegram-calories-bot/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+)
+
+// Unit tests for structured Gemini output
+// Tests: schema derived from EstimateResult, balanced-JSON extraction, single repair round-trip
+
+func TestEstimateResultSchema_DerivedFromStruct(t *testing.T) {
+	schema := services.EstimateResultSchema()
+
+	assert.Equal(t, genai.TypeObject, schema.Type)
+	assert.ElementsMatch(t, []string{"confidence", "calories"}, schema.Required)
+	assert.Equal(t, []string{"low", "medium", "high"}, schema.Properties["confidence"].Enum)
+	assert.NotContains(t, schema.Properties, "provider", "provider is set server-side")
+
+	items := schema.Properties["items"]
+	require.NotNil(t, items)
+	assert.Equal(t, genai.TypeArray, items.Type)
+	assert.ElementsMatch(t, []string{"name", "calories"}, items.Items.Required)
+	assert.Equal(t, genai.TypeNumber, items.Items.Properties["grams"].Type)
+
+	macros := schema.Properties["macros"]
+	require.NotNil(t, macros)
+	assert.True(t, *macros.Nullable)
+	assert.ElementsMatch(t, []string{"protein", "carbs", "fat"}, macros.Required)
+}
+
+func TestExtractJSONObject(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", `{"calories": 1}`, `{"calories": 1}`},
+		{"fenced", "```json\n{\"calories\": 1}\n```", `{"calories": 1}`},
+		{"prose around", `Sure! Here is the estimate: {"calories": 1, "items": []} Hope this helps.`, `{"calories": 1, "items": []}`},
+		{"braces in strings", `Result: {"reasoning": "a } tricky { string", "calories": 2}`, `{"reasoning": "a } tricky { string", "calories": 2}`},
+		{"skips invalid candidate", `{not json} then {"calories": 3}`, `{"calories": 3}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := services.ExtractJSONObject(tt.input)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, ok := services.ExtractJSONObject("I cannot estimate this image.")
+	assert.False(t, ok)
+}
+
+// geminiTextResponse wraps model text in a generateContent response
+func geminiTextResponse(text string) string {
+	body, _ := json.Marshal(map[string]any{
+		"candidates": []map[string]any{{
+			"content": map[string]any{"role": "model", "parts": []map[string]string{{"text": text}}},
+		}},
+	})
+	return string(body)
+}
+
+func TestGeminiClient_JSONModeAndRepairRoundTrip(t *testing.T) {
+	var calls atomic.Int32
+	var repairBody string
+
+	client := newTestGeminiClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+
+		if calls.Add(1) == 1 {
+			assert.Contains(t, string(body), `"responseMimeType":"application/json"`)
+			assert.Contains(t, string(body), `"responseSchema"`)
+			// Invalid confidence fails validation and triggers a repair
+			w.Write([]byte(geminiTextResponse(`{"calories": 95, "confidence": "certain", "items": [{"name": "Apple", "calories": 95}]}`)))
+			return
+		}
+
+		repairBody = string(body)
+		w.Write([]byte(geminiTextResponse(`{"calories": 95, "confidence": "high", "items": [{"name": "Apple", "calories": 95}]}`)))
+	}, services.GeminiOptions{Retry: fastRetry})
+
+	result, err := client.EstimateCaloriesFromText(context.Background(), "an apple")
+	require.NoError(t, err)
+	assert.Equal(t, "high", result.Confidence)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.True(t, strings.Contains(repairBody, "could not be used"), "repair prompt sent")
+	assert.Contains(t, repairBody, `"role":"model"`, "original reply included in the conversation")
+}
+
+func TestGeminiClient_RepairOnlyOnce(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestGeminiClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(geminiTextResponse(`I'm sorry, I can't help with that.`)))
+	}, services.GeminiOptions{Retry: fastRetry})
+
+	_, err := client.EstimateCaloriesFromText(context.Background(), "an apple")
+	require.Error(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
@@ -29,7 +29,7 @@ func TestGeminiJSONParsing_Success(t *testing.T) {
 			expected: models.EstimateResult{
 				Calories:   650,
 				Confidence: "high",
-				FoodItems:  []string{"Grilled chicken breast (200g)", "Steamed broccoli (100g)", "Brown rice (150g)"},
+				FoodItems:  []models.FoodItem{{Name: "Grilled chicken breast (200g)"}, {Name: "Steamed broccoli (100g)"}, {Name: "Brown rice (150g)"}},
 				Reasoning:  "Standard portions for grilled chicken plate",
 			},
 		},
@@ -44,7 +44,7 @@ func TestGeminiJSONParsing_Success(t *testing.T) {
 			expected: models.EstimateResult{
 				Calories:   300,
 				Confidence: "medium",
-				FoodItems:  []string{"Mixed salad", "Dressing"},
+				FoodItems:  []models.FoodItem{{Name: "Mixed salad"}, {Name: "Dressing"}},
 				Reasoning:  "Portion sizes estimated",
 			},
 		},
@@ -67,6 +67,29 @@ func TestGeminiJSONParsing_Success(t *testing.T) {
 	}
 }
 
+func TestGeminiJSONParsing_WithMacros(t *testing.T) {
+	jsonResp := `{
+		"calories": 450,
+		"confidence": "high",
+		"items": ["Grilled chicken breast (200g)", "Brown rice (150g)"],
+		"macros": {"protein": 48, "carbs": 42, "fat": 8, "fiber": 5},
+		"reasoning": "Standard portions"
+	}`
+
+	var result models.EstimateResult
+	err := json.Unmarshal([]byte(jsonResp), &result)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, result.Macros) {
+		assert.Equal(t, 48.0, result.Macros.Protein)
+		assert.Equal(t, 42.0, result.Macros.Carbs)
+		assert.Equal(t, 8.0, result.Macros.Fat)
+		assert.Equal(t, 5.0, *result.Macros.Fiber)
+		assert.Nil(t, result.Macros.Sugar, "Sugar omitted by model should stay nil")
+	}
+	assert.NoError(t, result.Validate())
+}
+
 func TestGeminiJSONParsing_NoFood(t *testing.T) {
 	jsonResp := `{
 		"calories": 0,
@@ -0,0 +1,67 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for daily calorie goal tracking
+// Tests: per-timezone day totals, progress formatting, profile validation
+
+func TestCaloriesOnDay_UsesUserTimezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	// 2024-03-10 in Tokyo spans 2024-03-09T15:00Z .. 2024-03-10T15:00Z
+	logs := []internalmodels.Log{
+		{Calories: 400, Timestamp: time.Date(2024, 3, 9, 14, 59, 0, 0, time.UTC)}, // previous day in Tokyo
+		{Calories: 500, Timestamp: time.Date(2024, 3, 9, 15, 0, 0, 0, time.UTC)},  // 00:00 Tokyo
+		{Calories: 700, Timestamp: time.Date(2024, 3, 10, 14, 0, 0, 0, time.UTC)}, // 23:00 Tokyo
+		{Calories: 900, Timestamp: time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)}, // next day in Tokyo
+	}
+
+	day := time.Date(2024, 3, 10, 12, 0, 0, 0, tokyo)
+	assert.Equal(t, 1200, internalmodels.CaloriesOnDay(logs, day, tokyo))
+	assert.Equal(t, 1600, internalmodels.CaloriesOnDay(logs, day, time.UTC))
+}
+
+func TestFormatGoalProgress(t *testing.T) {
+	under := models.FormatGoalProgress(english, 1200, 2000)
+	assert.Contains(t, under, "1,200 / 2,000 kcal")
+	assert.Contains(t, under, "Remaining: 800 kcal")
+
+	over := models.FormatGoalProgress(english, 2300, 2000)
+	assert.Contains(t, over, "Over goal by 300 kcal")
+}
+
+func TestUserProfile_Validate(t *testing.T) {
+	assert.NoError(t, (&internalmodels.UserProfile{DailyGoal: 2000, Timezone: "Europe/Madrid"}).Validate())
+	assert.NoError(t, (&internalmodels.UserProfile{}).Validate())
+	assert.Error(t, (&internalmodels.UserProfile{DailyGoal: -1}).Validate())
+	assert.Error(t, (&internalmodels.UserProfile{DailyGoal: 50000}).Validate())
+	assert.Error(t, (&internalmodels.UserProfile{Timezone: "Mars/Olympus"}).Validate())
+}
+
+func TestMemoryStorage_ProfileDefaultsAndSave(t *testing.T) {
+	store := storage.NewMemoryStorage()
+
+	profile, err := store.GetProfile(42)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), profile.UserID)
+	assert.Equal(t, 0, profile.DailyGoal)
+
+	profile.DailyGoal = 1800
+	profile.Timezone = "America/New_York"
+	require.NoError(t, store.SaveProfile(profile))
+
+	saved, err := store.GetProfile(42)
+	require.NoError(t, err)
+	assert.Equal(t, 1800, saved.DailyGoal)
+	assert.Equal(t, "America/New_York", saved.Timezone)
+}
@@ -0,0 +1,143 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/src/i18n"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	"github.com/freezind/telegram-calories-bot/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for localized bot replies
+// Tests: catalog completeness, plural rules, number and date formatting, reply language selection, prompt language
+
+func TestCatalogs_Complete(t *testing.T) {
+	for _, language := range []string{"es", "ru", "zh"} {
+		assert.Empty(t, i18n.Missing(language), "%s catalog is missing keys", language)
+	}
+}
+
+func TestPrinter_Plurals(t *testing.T) {
+	ru := i18n.New("ru", "")
+	for n, want := range map[int]string{
+		2:  "2 фотографии",
+		5:  "5 фотографий",
+		11: "11 фотографий",
+		21: "21 фотографию",
+		22: "22 фотографии",
+	} {
+		assert.Contains(t, ru.N("processing.photos", n, n), want, n)
+	}
+
+	assert.Equal(t, "⏳ Analyzing your 3 photos...", english.N("processing.photos", 3, 3))
+	assert.Contains(t, english.N("note.too_long", 1, 1), "1 character.")
+	assert.Contains(t, i18n.New("zh", "").N("processing.photos", 3, 3), "3 张照片")
+}
+
+func TestPrinter_Numbers(t *testing.T) {
+	assert.Equal(t, "1,234", english.Number(1234))
+	assert.Equal(t, "-1,234,567", english.Number(-1234567))
+	assert.Equal(t, "999", english.Number(999))
+
+	es := i18n.New("es", "es-ES")
+	assert.Equal(t, "1234", es.Number(1234), "Spanish groups only from five digits")
+	assert.Equal(t, "12.345", es.Number(12345))
+	assert.Equal(t, "1,5", es.Decimal(1.5, -1))
+	assert.Equal(t, "12,345", i18n.New("es", "es-MX").Number(12345))
+
+	assert.Equal(t, "1\u00a0234", i18n.New("ru", "").Number(1234))
+	assert.Equal(t, "0,5", i18n.New("ru", "").Decimal(0.5, -1))
+	assert.Equal(t, "1,234", i18n.New("zh", "zh-CN").Number(1234))
+}
+
+func TestPrinter_FallsBackToEnglish(t *testing.T) {
+	p := i18n.New("pt", "pt-BR")
+	assert.Equal(t, "en", p.Language())
+	assert.Equal(t, "Cancel", p.T("button.cancel"))
+	assert.Equal(t, "no.such.key", p.T("no.such.key"))
+}
+
+func TestFormatSummary_Localized(t *testing.T) {
+	loc := time.UTC
+	now := time.Date(2024, 5, 10, 20, 0, 0, 0, loc)
+	logs := []internalmodels.Log{
+		{Calories: 2300, MealType: internalmodels.MealBreakfast, Timestamp: time.Date(2024, 5, 10, 8, 0, 0, 0, loc)},
+		{Calories: 1500, MealType: internalmodels.MealLunch, Timestamp: time.Date(2024, 5, 8, 12, 0, 0, 0, loc)},
+	}
+	summary := internalmodels.SummarizeDays(logs, now, 7, loc, internalmodels.DefaultMealWindows, 2000)
+
+	es := models.FormatSummary(i18n.New("es", ""), "Últimos 7 días", &summary)
+	assert.Contains(t, es, "Media: 1900 kcal/día (2 de 7 días registrados)")
+	assert.Contains(t, es, "• Desayuno: 2300 kcal (1)")
+	assert.Contains(t, es, "• vie, 10 may: 2300 kcal (1) ⚠️")
+
+	ru := models.FormatSummary(i18n.New("ru", ""), "Последние 7 дней", &summary)
+	assert.Contains(t, ru, "Всего: 3\u00a0800 ккал")
+	assert.Contains(t, ru, "• пт, 10 мая: 2\u00a0300 ккал (1) ⚠️")
+
+	zh := models.FormatSummary(i18n.New("zh", ""), "最近 7 天", &summary)
+	assert.Contains(t, zh, "• 午餐：1,500 千卡（1）")
+	assert.Contains(t, zh, "• 5月10日周五：2,300 千卡（1） ⚠️")
+}
+
+func TestHandlers_ReplyInTelegramLanguage(t *testing.T) {
+	h, sender, _, _, _ := newTextTestHandler()
+
+	// Without a saved language the Telegram client language is used
+	c := newTextContext(42, "/estimate")
+	c.user.LanguageCode = "ru"
+	require.NoError(t, h.HandleEstimate(c))
+	assert.Contains(t, sender.last().text, "Отправьте фото еды")
+	require.NotNil(t, sender.last().markup)
+	assert.Equal(t, "Отмена", sender.last().markup.InlineKeyboard[0][0].Text)
+}
+
+func TestHandlers_ReplyInProfileLanguage(t *testing.T) {
+	h, sender, estimator, _, _ := newTextTestHandler()
+	estimator.result.FoodItems = []models.FoodItem{{Name: "Huevo", PortionGrams: 100, Calories: intPtr(155)}, {Name: "Tostada", PortionGrams: 30, Calories: intPtr(80)}}
+
+	require.NoError(t, h.HandleSettings(newTextContext(42, "/settings language es", "language", "es")))
+	assert.Contains(t, sender.last().text, "Ajustes guardados")
+
+	// The profile language wins over the client language
+	c := newTextContext(42, "/log 2 huevos y una tostada", "2", "huevos", "y", "una", "tostada")
+	c.user.LanguageCode = "en"
+	require.NoError(t, h.HandleLog(c))
+
+	result := sender.last()
+	assert.Contains(t, result.text, "Calorías estimadas: 235 kcal")
+	assert.Contains(t, result.text, "Confianza: Media")
+	assert.Contains(t, result.text, "• Huevo (100 g) — 155 kcal")
+	assert.Contains(t, result.text, "¿Guardar esta comida en tu registro?")
+	require.NotNil(t, result.markup)
+	assert.Equal(t, "✅ Guardar", result.markup.InlineKeyboard[0][0].Text)
+
+	// The estimator is asked for item names in Spanish
+	prompt, _, err := services.DefaultPrompts().TextPrompt(estimator.lastCtx, "2 huevos y una tostada")
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "write item names and reasoning in Spanish")
+
+	require.NoError(t, h.HandleSave(newCallbackContext(42, "save")))
+	assert.Equal(t, "✅ Guardado: 235 kcal", sender.last().text)
+}
+
+func TestPrompts_EnglishUsersGetNoLanguageLine(t *testing.T) {
+	h, _, estimator, _, _ := newTextTestHandler()
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log toast", "toast")))
+
+	prompt, _, err := services.DefaultPrompts().TextPrompt(estimator.lastCtx, "toast")
+	require.NoError(t, err)
+	assert.NotContains(t, prompt, "Language:")
+
+	// Any other reply language is named in the prompt
+	ctx := services.WithPromptRequest(context.Background(), services.PromptRequest{Vars: services.PromptVars{Language: "Russian"}})
+	prompt, _, err = services.DefaultPrompts().TextPrompt(ctx, "тост")
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "write item names and reasoning in Russian")
+}
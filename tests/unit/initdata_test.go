@@ -0,0 +1,73 @@
+package unit
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/freezind/telegram-calories-bot/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for Telegram WebApp initData verification
+// Tests: valid signature accepted, tampered/unsigned/stale data rejected
+
+const testBotToken = "123456:TEST-TOKEN"
+
+// signedInitData builds initData signed the same way Telegram does
+func signedInitData(userJSON string, authDate time.Time) string {
+	values := url.Values{}
+	values.Set("query_id", "AAHdF6IQAAAAAN0XohDhrOrc")
+	values.Set("user", userJSON)
+	values.Set("auth_date", strconv.FormatInt(authDate.Unix(), 10))
+	values.Set("hash", auth.SignInitData(values, testBotToken))
+	return values.Encode()
+}
+
+func TestValidateInitData_ValidSignature(t *testing.T) {
+	initData := signedInitData(`{"id":42,"first_name":"Test","username":"tester"}`, time.Now())
+
+	user, err := auth.ValidateInitData(initData, testBotToken, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), user.ID)
+	assert.Equal(t, "tester", user.Username)
+}
+
+func TestValidateInitData_TamperedUser(t *testing.T) {
+	initData := signedInitData(`{"id":42,"first_name":"Test"}`, time.Now())
+
+	values, err := url.ParseQuery(initData)
+	require.NoError(t, err)
+	values.Set("user", `{"id":1337,"first_name":"Attacker"}`)
+
+	_, err = auth.ValidateInitData(values.Encode(), testBotToken, time.Hour)
+	assert.ErrorIs(t, err, auth.ErrInvalidSignature)
+}
+
+func TestValidateInitData_WrongBotToken(t *testing.T) {
+	initData := signedInitData(`{"id":42,"first_name":"Test"}`, time.Now())
+
+	_, err := auth.ValidateInitData(initData, "654321:OTHER-TOKEN", time.Hour)
+	assert.ErrorIs(t, err, auth.ErrInvalidSignature)
+}
+
+func TestValidateInitData_MissingHash(t *testing.T) {
+	initData := `user=%7B%22id%22%3A42%7D&auth_date=1700000000`
+
+	_, err := auth.ValidateInitData(initData, testBotToken, time.Hour)
+	assert.ErrorIs(t, err, auth.ErrMissingHash)
+}
+
+func TestValidateInitData_Expired(t *testing.T) {
+	initData := signedInitData(`{"id":42,"first_name":"Test"}`, time.Now().Add(-2*time.Hour))
+
+	_, err := auth.ValidateInitData(initData, testBotToken, time.Hour)
+	assert.ErrorIs(t, err, auth.ErrExpired)
+
+	// Max age of zero disables the freshness check
+	user, err := auth.ValidateInitData(initData, testBotToken, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), user.ID)
+}
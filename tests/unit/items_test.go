@@ -0,0 +1,200 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for per-item calorie breakdown
+// Tests: structured/legacy item parsing, derived totals, single-item PATCH edits
+
+func TestEstimateResult_StructuredItems(t *testing.T) {
+	jsonResp := `{
+		"calories": 999,
+		"confidence": "high",
+		"items": [
+			{"name": "Grilled chicken breast", "grams": 200, "calories": 330},
+			{"name": "Brown rice", "grams": 150, "calories": 200}
+		]
+	}`
+
+	var result models.EstimateResult
+	require.NoError(t, json.Unmarshal([]byte(jsonResp), &result))
+
+	result.SyncCalories()
+	assert.Equal(t, 530, result.Calories, "Total should be derived from item sum")
+	assert.Equal(t, 200.0, result.FoodItems[0].PortionGrams)
+	assert.NoError(t, result.Validate())
+
+	formatted := models.FormatResult(english, &result)
+	assert.Contains(t, formatted, "• Grilled chicken breast (200g) — 330 kcal")
+	assert.Contains(t, formatted, "• Brown rice (150g) — 200 kcal")
+}
+
+func TestEstimateResult_SyncCalories_KeepsTotalWithoutItemCalories(t *testing.T) {
+	result := models.EstimateResult{
+		Calories:  450,
+		FoodItems: []models.FoodItem{{Name: "Pizza slice"}},
+	}
+	result.SyncCalories()
+	assert.Equal(t, 450, result.Calories)
+}
+
+func TestEstimateResult_SyncCalories_KeepsTotalWithPartialItemCalories(t *testing.T) {
+	result := models.EstimateResult{
+		Calories: 450,
+		FoodItems: []models.FoodItem{
+			{Name: "Pizza slice", Calories: intPtr(300)},
+			{Name: "Side salad"},
+		},
+	}
+	result.SyncCalories()
+	assert.Equal(t, 450, result.Calories, "a partial item sum would undercount")
+}
+
+func TestEstimateResult_SyncCalories_CountsZeroCalorieItems(t *testing.T) {
+	var result models.EstimateResult
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"calories": 400,
+		"confidence": "high",
+		"items": [
+			{"name": "Chicken salad", "grams": 250, "calories": 330},
+			{"name": "Water", "grams": 500, "calories": 0}
+		]
+	}`), &result))
+
+	result.SyncCalories()
+	assert.Equal(t, 330, result.Calories, "0 kcal is an estimate, not a missing one")
+	assert.Contains(t, models.FormatResult(english, &result), "• Water (500g) — 0 kcal")
+
+	// Items the model gave only as names still have no estimate
+	require.NoError(t, json.Unmarshal([]byte(`{"calories": 400, "items": ["Chicken salad", {"name": "Water", "calories": 0}]}`), &result))
+	result.SyncCalories()
+	assert.Equal(t, 400, result.Calories)
+}
+
+func TestEstimateResult_Validate_NegativeItemCalories(t *testing.T) {
+	result := models.EstimateResult{
+		Calories:   100,
+		Confidence: "high",
+		FoodItems:  []models.FoodItem{{Name: "Salad", Calories: intPtr(-5)}},
+	}
+	assert.ErrorContains(t, result.Validate(), "calories must be non-negative")
+}
+
+func TestLogFoodItem_AcceptsLegacyStrings(t *testing.T) {
+	var entry internalmodels.Log
+	err := json.Unmarshal([]byte(`{"foodItems": ["Rice", {"name": "Chicken", "portionGrams": 180, "calories": 300}], "calories": 500}`), &entry)
+	require.NoError(t, err)
+
+	assert.Equal(t, []internalmodels.FoodItem{
+		{Name: "Rice"},
+		{Name: "Chicken", PortionGrams: 180, Calories: intPtr(300)},
+	}, entry.FoodItems)
+}
+
+func TestLogFoodItem_UnknownCaloriesRoundTrip(t *testing.T) {
+	items := []internalmodels.FoodItem{{Name: "Rice"}, {Name: "Black coffee", Calories: intPtr(0)}}
+	data, err := json.Marshal(items)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"name": "Rice"}, {"name": "Black coffee", "calories": 0}]`, string(data))
+
+	var decoded []internalmodels.FoodItem
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, items, decoded)
+}
+
+func TestMemoryStorage_UpdateLog_SingleItem(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	userID := int64(12345)
+
+	entry := &internalmodels.Log{
+		FoodItems: []internalmodels.FoodItem{
+			{Name: "Rice", PortionGrams: 150, Calories: intPtr(200)},
+			{Name: "Chicken", PortionGrams: 180, Calories: intPtr(300)},
+			{Name: "Sauce", PortionGrams: 30, Calories: intPtr(90)},
+		},
+		Confidence: internalmodels.ConfidenceHigh,
+	}
+	require.NoError(t, store.CreateLog(userID, entry))
+	assert.Equal(t, 590, entry.Calories, "Total should be derived on create")
+
+	// Edit a single item's calories
+	calories := 250
+	require.NoError(t, store.UpdateLog(userID, entry.ID, &internalmodels.LogUpdate{
+		Item: &internalmodels.FoodItemEdit{Index: 1, Calories: &calories},
+	}))
+
+	logs, err := store.ListLogs(userID)
+	require.NoError(t, err)
+	assert.Equal(t, 540, logs[0].Calories)
+	assert.Equal(t, intPtr(250), logs[0].FoodItems[1].Calories)
+
+	// Remove a single item
+	require.NoError(t, store.UpdateLog(userID, entry.ID, &internalmodels.LogUpdate{
+		Item: &internalmodels.FoodItemEdit{Index: 2, Remove: true},
+	}))
+
+	logs, err = store.ListLogs(userID)
+	require.NoError(t, err)
+	assert.Len(t, logs[0].FoodItems, 2)
+	assert.Equal(t, 450, logs[0].Calories)
+
+	// Out-of-range index is rejected and leaves the log untouched
+	err = store.UpdateLog(userID, entry.ID, &internalmodels.LogUpdate{
+		Item: &internalmodels.FoodItemEdit{Index: 5, Remove: true},
+	})
+	assert.ErrorContains(t, err, "out of range")
+
+	// Removing the last remaining items fails validation without persisting
+	require.NoError(t, store.UpdateLog(userID, entry.ID, &internalmodels.LogUpdate{
+		Item: &internalmodels.FoodItemEdit{Index: 0, Remove: true},
+	}))
+	err = store.UpdateLog(userID, entry.ID, &internalmodels.LogUpdate{
+		Item: &internalmodels.FoodItemEdit{Index: 0, Remove: true},
+	})
+	assert.ErrorContains(t, err, "food items cannot be empty")
+
+	logs, err = store.ListLogs(userID)
+	require.NoError(t, err)
+	assert.Len(t, logs[0].FoodItems, 1)
+}
+
+func TestLog_ApplyUpdate_KeepsTotalWithPartialItemCalories(t *testing.T) {
+	entry := &internalmodels.Log{
+		FoodItems: []internalmodels.FoodItem{{Name: "Rice", Calories: intPtr(200)}},
+		Calories:  200,
+	}
+	items := []internalmodels.FoodItem{{Name: "Rice", Calories: intPtr(200)}, {Name: "Curry"}}
+	calories := 650
+	require.NoError(t, entry.ApplyUpdate(&internalmodels.LogUpdate{FoodItems: &items, Calories: &calories}))
+	assert.Equal(t, 650, entry.Calories)
+
+	// Once every item has calories the total follows the items again
+	name := "Chicken curry"
+	itemCalories := 400
+	require.NoError(t, entry.ApplyUpdate(&internalmodels.LogUpdate{
+		Item: &internalmodels.FoodItemEdit{Index: 1, Name: &name, Calories: &itemCalories},
+	}))
+	assert.Equal(t, 600, entry.Calories)
+}
+
+func TestLog_ApplyUpdate_RecomputesWithZeroCalorieItem(t *testing.T) {
+	entry := &internalmodels.Log{
+		FoodItems: []internalmodels.FoodItem{{Name: "Croissant", Calories: intPtr(270)}, {Name: "Black coffee", Calories: intPtr(0)}},
+	}
+	entry.SyncCalories()
+	assert.Equal(t, 270, entry.Calories)
+
+	calories := 340
+	require.NoError(t, entry.ApplyUpdate(&internalmodels.LogUpdate{
+		Item: &internalmodels.FoodItemEdit{Index: 0, Calories: &calories},
+	}))
+	assert.Equal(t, 340, entry.Calories, "a 0 kcal item still lets the total follow the items")
+}
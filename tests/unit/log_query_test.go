@@ -0,0 +1,231 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/freezind/telegram-calories-bot/internal/handlers"
+	"github.com/freezind/telegram-calories-bot/internal/middleware"
+	"github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for paginated, filtered log queries (GET /api/logs)
+// Tests: cursor pagination, from/to, confidence and food item search on both backends
+
+// queryBackends returns a fresh instance of each LogStorage implementation
+func queryBackends(t *testing.T) map[string]storage.LogStorage {
+	sqlite, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "query.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlite.Close() })
+
+	return map[string]storage.LogStorage{
+		"memory": storage.NewMemoryStorage(),
+		"sqlite": sqlite,
+	}
+}
+
+func TestQueryLogs_CursorPagination(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for name, store := range queryBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			// Two logs share a timestamp so the ID tie-break is exercised
+			for i, offset := range []int{0, 1, 2, 2, 3} {
+				require.NoError(t, store.CreateLog(1, newTestLog(100*(i+1), base.Add(time.Duration(offset)*time.Hour))))
+			}
+
+			for _, order := range []models.LogOrder{models.OrderDesc, models.OrderAsc} {
+				seen := map[string]bool{}
+				var pages int
+				query := &models.LogQuery{Limit: 2, Order: order}
+				var previous *models.Log
+				for {
+					page, err := store.QueryLogs(1, query)
+					require.NoError(t, err)
+					pages++
+					for i := range page.Logs {
+						l := page.Logs[i]
+						assert.False(t, seen[l.ID], "log returned twice")
+						seen[l.ID] = true
+						if previous != nil {
+							if order == models.OrderAsc {
+								assert.False(t, l.Timestamp.Before(previous.Timestamp), "not sorted ascending")
+							} else {
+								assert.False(t, l.Timestamp.After(previous.Timestamp), "not sorted descending")
+							}
+						}
+						previous = &l
+					}
+					if page.NextCursor == "" {
+						break
+					}
+					query = &models.LogQuery{Limit: 2, Order: order, Cursor: page.NextCursor}
+				}
+
+				assert.Len(t, seen, 5, order)
+				assert.Equal(t, 3, pages, order)
+			}
+		})
+	}
+}
+
+func TestQueryLogs_Filters(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for name, store := range queryBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, store.CreateLog(1, &models.Log{
+				FoodItems: []models.FoodItem{{Name: "Greek Yogurt"}, {Name: "Honey"}}, Calories: 250,
+				Confidence: models.ConfidenceHigh, Timestamp: base,
+			}))
+			require.NoError(t, store.CreateLog(1, &models.Log{
+				FoodItems: []models.FoodItem{{Name: "Pizza"}}, Calories: 800,
+				Confidence: models.ConfidenceLow, Timestamp: base.Add(24 * time.Hour),
+			}))
+			require.NoError(t, store.CreateLog(1, &models.Log{
+				FoodItems: []models.FoodItem{{Name: "Frozen yogurt"}}, Calories: 300,
+				Confidence: models.ConfidenceMedium, Timestamp: base.Add(48 * time.Hour),
+			}))
+			require.NoError(t, store.CreateLog(2, &models.Log{
+				FoodItems: []models.FoodItem{{Name: "Yogurt"}}, Calories: 100,
+				Confidence: models.ConfidenceHigh, Timestamp: base,
+			}))
+
+			page, err := store.QueryLogs(1, &models.LogQuery{Search: "YOGURT"})
+			require.NoError(t, err)
+			require.Len(t, page.Logs, 2)
+			assert.Equal(t, 300, page.Logs[0].Calories)
+			assert.Empty(t, page.NextCursor)
+
+			// Case folding is not limited to ASCII
+			require.NoError(t, store.CreateLog(3, &models.Log{
+				FoodItems: []models.FoodItem{{Name: "Борщ"}, {Name: "Crème Brûlée"}}, Calories: 400,
+				Confidence: models.ConfidenceHigh, Timestamp: base,
+			}))
+			for _, search := range []string{"борщ", "CRÈME BRÛLÉE"} {
+				page, err = store.QueryLogs(3, &models.LogQuery{Search: search})
+				require.NoError(t, err)
+				assert.Len(t, page.Logs, 1, search)
+			}
+
+			// Key names in the stored JSON must not match
+			page, err = store.QueryLogs(1, &models.LogQuery{Search: "calories"})
+			require.NoError(t, err)
+			assert.Empty(t, page.Logs)
+
+			page, err = store.QueryLogs(1, &models.LogQuery{Confidence: []models.ConfidenceLevel{models.ConfidenceLow, models.ConfidenceMedium}})
+			require.NoError(t, err)
+			assert.Len(t, page.Logs, 2)
+
+			from := base.Add(time.Hour)
+			to := base.Add(48 * time.Hour)
+			page, err = store.QueryLogs(1, &models.LogQuery{From: &from, To: &to})
+			require.NoError(t, err)
+			require.Len(t, page.Logs, 1)
+			assert.Equal(t, 800, page.Logs[0].Calories)
+		})
+	}
+}
+
+func TestLogQuery_Validate(t *testing.T) {
+	q := &models.LogQuery{}
+	require.NoError(t, q.Validate())
+	assert.Equal(t, models.DefaultPageSize, q.Limit)
+
+	assert.Error(t, (&models.LogQuery{Limit: models.MaxPageSize + 1}).Validate())
+	assert.Error(t, (&models.LogQuery{Limit: -1}).Validate())
+	assert.Error(t, (&models.LogQuery{Cursor: "not-a-cursor"}).Validate())
+	assert.Error(t, (&models.LogQuery{Confidence: []models.ConfidenceLevel{"certain"}}).Validate())
+
+	now := time.Now()
+	assert.Error(t, (&models.LogQuery{From: &now, To: &now}).Validate())
+
+	assert.Error(t, (&models.LogQuery{Order: "sideways"}).Validate())
+	descCursor := models.EncodeLogCursor(&models.Log{ID: "log-1", Timestamp: now}, models.OrderDesc)
+	assert.NoError(t, (&models.LogQuery{Cursor: descCursor}).Validate())
+	assert.Error(t, (&models.LogQuery{Order: models.OrderAsc, Cursor: descCursor}).Validate(), "cursor from another order")
+}
+
+func TestLogsHandler_ListLogs_Paginated(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.CreateLog(7, newTestLog(100+i, base.Add(time.Duration(i)*time.Hour))))
+	}
+	handler := handlers.NewLogsHandler(store, store)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(7)))
+		rec := httptest.NewRecorder()
+		handler.ListLogs(rec, req)
+		return rec
+	}
+
+	rec := get("/api/logs?limit=2&confidence=high,medium&q=rice")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var page models.LogPage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	require.Len(t, page.Logs, 2)
+	assert.Equal(t, 102, page.Logs[0].Calories)
+	require.NotEmpty(t, page.NextCursor)
+
+	rec = get("/api/logs?limit=2&cursor=" + page.NextCursor)
+	require.Equal(t, http.StatusOK, rec.Code)
+	page = models.LogPage{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	require.Len(t, page.Logs, 1)
+	assert.Empty(t, page.NextCursor)
+
+	assert.Equal(t, http.StatusBadRequest, get("/api/logs?limit=abc").Code)
+	assert.Equal(t, http.StatusBadRequest, get("/api/logs?from=yesterday").Code)
+}
+
+func TestLogsHandler_ListLogs_OrderAndLocalDates(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	require.NoError(t, store.SaveProfile(&models.UserProfile{UserID: 7, Timezone: "Asia/Tokyo"}))
+	// 2024-05-01 23:30 and 2024-05-02 00:30 in Tokyo, and 2024-05-03 09:00
+	for i, ts := range []time.Time{
+		time.Date(2024, 5, 1, 14, 30, 0, 0, time.UTC),
+		time.Date(2024, 5, 1, 15, 30, 0, 0, time.UTC),
+		time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC),
+	} {
+		require.NoError(t, store.CreateLog(7, newTestLog(100+i, ts)))
+	}
+	handler := handlers.NewLogsHandler(store, store)
+
+	list := func(target string) []models.Log {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(7)))
+		rec := httptest.NewRecorder()
+		handler.ListLogs(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var page models.LogPage
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		return page.Logs
+	}
+
+	logs := list("/api/logs?order=asc")
+	require.Len(t, logs, 3)
+	assert.Equal(t, 100, logs[0].Calories)
+
+	// Dates are days in the user's timezone; to is inclusive
+	logs = list("/api/logs?from=2024-05-02&to=2024-05-02")
+	require.Len(t, logs, 1)
+	assert.Equal(t, 101, logs[0].Calories)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/logs?order=random", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(7)))
+	rec := httptest.NewRecorder()
+	handler.ListLogs(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
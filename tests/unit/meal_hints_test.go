@@ -0,0 +1,121 @@
+package unit
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	"github.com/freezind/telegram-calories-bot/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for captions and notes as estimation hints
+// Tests: caption and text notes reach the estimator and the log note, prompt rendering, cache keying, note persistence
+
+func TestMealHints_CaptionBecomesHintAndNote(t *testing.T) {
+	h, _, estimator, _, store := newMultiPhotoHandler(t)
+	require.NoError(t, h.HandleEstimate(newTextContext(7, "/estimate")))
+
+	c := newPhotoContext(7, "plate")
+	c.message.Caption = "  half portion, no sauce "
+	require.NoError(t, h.HandlePhoto(c))
+	require.NoError(t, h.HandleDone(newCallbackContext(7, "done")))
+	require.NoError(t, h.HandleSave(newCallbackContext(7, "save")))
+
+	assert.Equal(t, "half portion, no sauce", estimator.lastHints)
+	logs := logsFor(store, 7)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "half portion, no sauce", logs[0].Note)
+}
+
+func TestMealHints_TextWhileAwaitingImage(t *testing.T) {
+	h, sender, estimator, sm, store := newMultiPhotoHandler(t)
+	require.NoError(t, h.HandleEstimate(newTextContext(7, "/estimate")))
+
+	require.NoError(t, h.HandleText(newTextContext(7, "cooked in butter")))
+	assert.Contains(t, sender.last().text, "Noted")
+	assert.Equal(t, models.StateAwaitingImage, sm.GetSession(7).State)
+
+	c := newPhotoContext(7, "plate")
+	c.message.Caption = "large plate"
+	require.NoError(t, h.HandlePhoto(c))
+	require.NoError(t, h.HandleText(newTextContext(7, "no dessert")))
+	assert.Contains(t, sender.last().text, "tap Done")
+
+	require.NoError(t, h.HandleDone(newCallbackContext(7, "done")))
+	require.NoError(t, h.HandleSave(newCallbackContext(7, "save")))
+	assert.Equal(t, "cooked in butter\nlarge plate\nno dessert", estimator.lastHints)
+	assert.Equal(t, estimator.lastHints, logsFor(store, 7)[0].Note)
+
+	// Hints are consumed by the estimate
+	assert.Empty(t, sm.GetSession(7).Hints)
+}
+
+func TestMealHints_IgnoredOutsidePhotoFlow(t *testing.T) {
+	h, sender, _, sm, _ := newMultiPhotoHandler(t)
+
+	require.NoError(t, h.HandleText(newTextContext(7, "hello")))
+	assert.Empty(t, sender.sent)
+	assert.Empty(t, sm.GetSession(7).Hints)
+}
+
+func TestMealHints_TooLong(t *testing.T) {
+	h, sender, _, sm, _ := newMultiPhotoHandler(t)
+	sm.UpdateSession(7, models.StateAwaitingImage)
+
+	require.NoError(t, h.HandleText(newTextContext(7, strings.Repeat("a", 501))))
+	assert.Contains(t, sender.last().text, "Note is too long")
+	assert.Empty(t, sm.GetSession(7).Hints)
+}
+
+func TestImagePrompt_IncludesHints(t *testing.T) {
+	p := services.DefaultPrompts()
+
+	withHints, _, err := p.ImagePrompt(context.Background(), 1, "half portion, no sauce")
+	require.NoError(t, err)
+	assert.Contains(t, withHints, "notes about the meal")
+	assert.Contains(t, withHints, "half portion, no sauce")
+
+	without, _, err := p.ImagePrompt(context.Background(), 1, "")
+	require.NoError(t, err)
+	assert.NotContains(t, without, "notes about the meal")
+}
+
+func TestCachingEstimator_KeyedByHints(t *testing.T) {
+	inner := newFakeEstimator()
+	cache := services.NewCachingEstimator(inner, nil, services.CacheOptions{})
+	photo := oneImage([]byte("plate"), "image/jpeg")
+
+	for _, hints := range []string{"", "half portion", "half portion"} {
+		_, err := cache.EstimateFromImages(context.Background(), photo, hints)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, inner.images)
+}
+
+func TestSQLiteStorage_PersistsNote(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "notes.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	entry := newTestLog(400, time.Now())
+	entry.Note = "no sauce"
+	require.NoError(t, store.CreateLog(1, entry))
+
+	logs := logsFor(store, 1)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "no sauce", logs[0].Note)
+
+	note := " extra cheese "
+	require.NoError(t, store.UpdateLog(1, entry.ID, &internalmodels.LogUpdate{Note: &note}))
+	assert.Equal(t, "extra cheese", logsFor(store, 1)[0].Note)
+
+	tooLong := strings.Repeat("x", internalmodels.MaxNoteLength+1)
+	assert.Error(t, store.UpdateLog(1, entry.ID, &internalmodels.LogUpdate{Note: &tooLong}))
+}
@@ -0,0 +1,211 @@
+package unit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for meal type classification
+// Tests: inference from time and timezone, custom windows, storage defaults and overrides, per-meal summaries, bot buttons
+
+func TestInferMealType_DefaultWindows(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 5, 10, hour, minute, 0, 0, madrid)
+	}
+	windows := internalmodels.DefaultMealWindows
+	assert.Equal(t, internalmodels.MealBreakfast, internalmodels.InferMealType(at(8, 0), madrid, windows))
+	assert.Equal(t, internalmodels.MealSnack, internalmodels.InferMealType(at(10, 30), madrid, windows), "window end is exclusive")
+	assert.Equal(t, internalmodels.MealLunch, internalmodels.InferMealType(at(13, 15), madrid, windows))
+	assert.Equal(t, internalmodels.MealSnack, internalmodels.InferMealType(at(16, 0), madrid, windows))
+	assert.Equal(t, internalmodels.MealDinner, internalmodels.InferMealType(at(20, 0), madrid, windows))
+	assert.Equal(t, internalmodels.MealSnack, internalmodels.InferMealType(at(23, 30), madrid, windows))
+
+	// 06:00 UTC is breakfast in UTC but 15:00 (a snack) in Tokyo
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	utcMorning := time.Date(2024, 5, 10, 6, 0, 0, 0, time.UTC)
+	assert.Equal(t, internalmodels.MealBreakfast, internalmodels.InferMealType(utcMorning, time.UTC, windows))
+	assert.Equal(t, internalmodels.MealSnack, internalmodels.InferMealType(utcMorning, tokyo, windows))
+}
+
+func TestParseMealWindows(t *testing.T) {
+	windows, err := internalmodels.ParseMealWindows("breakfast=06:00-10:00, lunch=12:00-15:00,dinner=18:00-22:00")
+	require.NoError(t, err)
+	require.Len(t, windows, 3)
+	assert.Equal(t, internalmodels.MealWindow{Type: internalmodels.MealLunch, Start: 12 * 60, End: 15 * 60}, windows[1])
+	assert.Equal(t, "breakfast=06:00-10:00,lunch=12:00-15:00,dinner=18:00-22:00", internalmodels.FormatMealWindows(windows))
+
+	for _, spec := range []string{
+		"",
+		"brunch=10:00-12:00",
+		"snack=15:00-16:00",
+		"lunch=12:00",
+		"lunch=15:00-12:00",
+		"lunch=25:00-26:00",
+		"lunch=12:00-15:00,lunch=13:00-14:00",
+		"breakfast=06:00-12:30,lunch=12:00-15:00",
+	} {
+		_, err := internalmodels.ParseMealWindows(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestCreateLog_InfersMealTypeFromProfile(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	require.NoError(t, store.SaveProfile(&internalmodels.UserProfile{
+		UserID:      1,
+		Timezone:    "America/New_York",
+		MealWindows: "breakfast=07:00-09:00,dinner=19:00-21:00",
+	}))
+
+	// 23:30 UTC is 19:30 in New York (EDT)
+	entry := &internalmodels.Log{
+		FoodItems:  []internalmodels.FoodItem{{Name: "Pasta", Calories: intPtr(600)}},
+		Confidence: internalmodels.ConfidenceHigh,
+		Timestamp:  time.Date(2024, 5, 10, 23, 30, 0, 0, time.UTC),
+	}
+	require.NoError(t, store.CreateLog(1, entry))
+	assert.Equal(t, internalmodels.MealDinner, entry.MealType)
+
+	// An explicit meal type is kept
+	entry = &internalmodels.Log{
+		FoodItems:  []internalmodels.FoodItem{{Name: "Cake", Calories: intPtr(300)}},
+		Confidence: internalmodels.ConfidenceHigh,
+		MealType:   internalmodels.MealSnack,
+		Timestamp:  time.Date(2024, 5, 10, 23, 30, 0, 0, time.UTC),
+	}
+	require.NoError(t, store.CreateLog(1, entry))
+	assert.Equal(t, internalmodels.MealSnack, entry.MealType)
+
+	invalid := &internalmodels.Log{
+		FoodItems:  []internalmodels.FoodItem{{Name: "Tea", Calories: intPtr(5)}},
+		Confidence: internalmodels.ConfidenceHigh,
+		MealType:   "brunch",
+		Timestamp:  time.Now(),
+	}
+	assert.Error(t, store.CreateLog(1, invalid))
+}
+
+func TestSQLiteStorage_PersistsMealType(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "meals.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	require.NoError(t, store.SaveProfile(&internalmodels.UserProfile{UserID: 1, MealWindows: "lunch=11:00-13:00"}))
+	profile, err := store.GetProfile(1)
+	require.NoError(t, err)
+	assert.Equal(t, "lunch=11:00-13:00", profile.MealWindows)
+
+	entry := &internalmodels.Log{
+		FoodItems:  []internalmodels.FoodItem{{Name: "Salad", Calories: intPtr(350)}},
+		Confidence: internalmodels.ConfidenceMedium,
+		Timestamp:  time.Date(2024, 5, 10, 12, 0, 0, 0, time.Local),
+	}
+	require.NoError(t, store.CreateLog(1, entry))
+	require.Equal(t, internalmodels.MealLunch, logsFor(store, 1)[0].MealType)
+
+	// PATCH override
+	dinner := internalmodels.MealDinner
+	require.NoError(t, store.UpdateLog(1, entry.ID, &internalmodels.LogUpdate{MealType: &dinner}))
+	assert.Equal(t, internalmodels.MealDinner, logsFor(store, 1)[0].MealType)
+
+	brunch := internalmodels.MealType("brunch")
+	assert.Error(t, store.UpdateLog(1, entry.ID, &internalmodels.LogUpdate{MealType: &brunch}))
+	assert.Equal(t, internalmodels.MealDinner, logsFor(store, 1)[0].MealType)
+}
+
+func TestSummarizeDays_GroupsByMeal(t *testing.T) {
+	loc := time.UTC
+	now := time.Date(2024, 5, 10, 20, 0, 0, 0, loc)
+	logs := []internalmodels.Log{
+		{Calories: 400, MealType: internalmodels.MealBreakfast, Timestamp: time.Date(2024, 5, 10, 8, 0, 0, 0, loc)},
+		{Calories: 700, MealType: internalmodels.MealDinner, Timestamp: time.Date(2024, 5, 10, 19, 0, 0, 0, loc)},
+		{Calories: 150, MealType: internalmodels.MealSnack, Timestamp: time.Date(2024, 5, 9, 19, 0, 0, 0, loc)},
+		// Saved before meal types existed: classified by time of day
+		{Calories: 600, Timestamp: time.Date(2024, 5, 9, 13, 0, 0, 0, loc)},
+	}
+
+	summary := internalmodels.SummarizeDays(logs, now, 7, loc, internalmodels.DefaultMealWindows, 0)
+	require.Len(t, summary.Meals, 4)
+	assert.Equal(t, internalmodels.MealSummary{MealType: internalmodels.MealBreakfast, Calories: 400, Entries: 1}, summary.Meals[0])
+	assert.Equal(t, internalmodels.MealSummary{MealType: internalmodels.MealLunch, Calories: 600, Entries: 1}, summary.Meals[1])
+	assert.Equal(t, internalmodels.MealSummary{MealType: internalmodels.MealDinner, Calories: 700, Entries: 1}, summary.Meals[2])
+	assert.Equal(t, internalmodels.MealSummary{MealType: internalmodels.MealSnack, Calories: 150, Entries: 1}, summary.Meals[3])
+
+	msg := models.FormatSummary(english, "Last 7 days", &summary)
+	assert.Contains(t, msg, "By meal:")
+	assert.Contains(t, msg, "• Breakfast: 400 kcal (1)")
+	assert.Contains(t, msg, "• Snack: 150 kcal (1)")
+}
+
+func TestSummarizeDays_UsesMealWindowsForUntypedLogs(t *testing.T) {
+	loc := time.UTC
+	now := time.Date(2024, 5, 10, 20, 0, 0, 0, loc)
+	logs := []internalmodels.Log{{Calories: 500, Timestamp: time.Date(2024, 5, 10, 15, 30, 0, 0, loc)}}
+
+	// 15:30 is a snack by default but lunch for a late luncher
+	windows, err := internalmodels.ParseMealWindows("lunch=12:00-16:00")
+	require.NoError(t, err)
+	summary := internalmodels.SummarizeDays(logs, now, 1, loc, windows, 0)
+	assert.Equal(t, 500, summary.Meals[1].Calories)
+
+	summary = internalmodels.SummarizeDays(logs, now, 1, loc, internalmodels.DefaultMealWindows, 0)
+	assert.Equal(t, 500, summary.Meals[3].Calories)
+}
+
+func TestMealTypeButtons_OverrideBeforeSave(t *testing.T) {
+	h, sender, _, _, store := newTextTestHandler()
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log soup", "soup")))
+	result := len(sender.sent) - 1
+	assert.Contains(t, sender.sent[result].text, "Meal: ")
+	require.NotNil(t, sender.sent[result].markup)
+	row := sender.sent[result].markup.InlineKeyboard[1]
+	assert.Equal(t, []string{"meal_breakfast", "meal_lunch", "meal_dinner", "meal_snack"},
+		[]string{row[0].Unique, row[1].Unique, row[2].Unique, row[3].Unique})
+
+	require.NoError(t, h.HandleMealType(newCallbackContext(42, "meal_dinner")))
+	assert.Contains(t, sender.sent[result].text, "Meal: Dinner", "the result is updated in place")
+	assert.Equal(t, "✓ Dinner", sender.sent[result].markup.InlineKeyboard[1][2].Text)
+
+	require.NoError(t, h.HandleMealType(newCallbackContext(42, "meal_brunch")))
+	assert.Contains(t, sender.sent[result].text, "Meal: Dinner")
+
+	require.NoError(t, h.HandleSave(newCallbackContext(42, "save")))
+	logs := logsFor(store, 42)
+	require.Len(t, logs, 1)
+	assert.Equal(t, internalmodels.MealDinner, logs[0].MealType)
+}
+
+func TestMeals_Command(t *testing.T) {
+	h, sender, _, _, store := newTextTestHandler()
+
+	require.NoError(t, h.HandleMeals(newTextContext(42, "/meals")))
+	assert.Contains(t, sender.last().text, "Meal times (default)")
+	assert.Contains(t, sender.last().text, "Breakfast: 05:00–10:30")
+
+	require.NoError(t, h.HandleMeals(newTextContext(42, "/meals breakfast=06:00-09:00 dinner=19:00-22:00",
+		"breakfast=06:00-09:00", "dinner=19:00-22:00")))
+	assert.Contains(t, sender.last().text, "Your meal times")
+	profile, err := store.GetProfile(42)
+	require.NoError(t, err)
+	assert.Equal(t, "breakfast=06:00-09:00,dinner=19:00-22:00", profile.MealWindows)
+
+	require.NoError(t, h.HandleMeals(newTextContext(42, "/meals lunch=15:00-12:00", "lunch=15:00-12:00")))
+	assert.Contains(t, sender.last().text, "Invalid meal windows")
+
+	require.NoError(t, h.HandleMeals(newTextContext(42, "/meals reset", "reset")))
+	profile, err = store.GetProfile(42)
+	require.NoError(t, err)
+	assert.Empty(t, profile.MealWindows)
+}
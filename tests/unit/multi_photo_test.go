@@ -0,0 +1,173 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/freezind/telegram-calories-bot/src/handlers"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	"github.com/freezind/telegram-calories-bot/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for multi-photo meal estimation
+// Tests: collecting photos and albums until Done, one combined estimate and log, photo limit, multi-image prompts
+
+// newMultiPhotoHandler serves a small valid JPEG for every file download
+func newMultiPhotoHandler(t *testing.T) (*handlers.EstimateHandler, *fakeSender, *fakeEstimator, *services.SessionManager, *storage.MemoryStorage) {
+	photo := halvesJPEG(t, 64, 48)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(photo)
+	}))
+	t.Cleanup(server.Close)
+
+	sender := &fakeSender{fileBaseURL: server.URL}
+	estimator := newFakeEstimator()
+	sm := services.NewSessionManager()
+	store := storage.NewMemoryStorage()
+	return handlers.NewEstimateHandler(sender, sm, estimator, store, store, nil), sender, estimator, sm, store
+}
+
+func TestMultiPhoto_CollectUntilDone(t *testing.T) {
+	h, sender, estimator, sm, store := newMultiPhotoHandler(t)
+	require.NoError(t, h.HandleEstimate(newTextContext(7, "/estimate")))
+
+	// A single photo, then a two-photo album
+	require.NoError(t, h.HandlePhoto(newPhotoContext(7, "plate")))
+	assert.Contains(t, sender.last().text, "Photo 1 added")
+	require.NotNil(t, sender.last().markup)
+	assert.Equal(t, "done", sender.last().markup.InlineKeyboard[0][0].Unique)
+
+	sentBefore := len(sender.sent)
+	for _, fileID := range []string{"side", "drink"} {
+		c := newPhotoContext(7, fileID)
+		c.message.AlbumID = "album-1"
+		require.NoError(t, h.HandlePhoto(c))
+	}
+	assert.Len(t, sender.sent, sentBefore+1, "album acknowledged once")
+	assert.Contains(t, sender.last().text, "Album received")
+
+	assert.Equal(t, models.StateCollectingImages, sm.GetSession(7).State)
+	assert.Equal(t, 0, estimator.images, "nothing estimated before Done")
+
+	require.NoError(t, h.HandleDone(newCallbackContext(7, "done")))
+	assert.Equal(t, 1, estimator.images, "one combined estimate")
+	assert.Len(t, estimator.lastImages, 3)
+	require.NoError(t, h.HandleSave(newCallbackContext(7, "save")))
+	assert.Len(t, logsFor(store, 7), 1, "one log entry for the meal")
+
+	session := sm.GetSession(7)
+	assert.Equal(t, models.StateAwaitingImage, session.State)
+	assert.Empty(t, session.Images)
+}
+
+func TestMultiPhoto_DoneWithoutPhotos(t *testing.T) {
+	h, _, estimator, sm, _ := newMultiPhotoHandler(t)
+	sm.UpdateSession(7, models.StateAwaitingImage)
+
+	require.NoError(t, h.HandleDone(newCallbackContext(7, "done")))
+	assert.Equal(t, 0, estimator.images)
+}
+
+func TestMultiPhoto_Limit(t *testing.T) {
+	h, sender, _, sm, _ := newMultiPhotoHandler(t)
+	sm.UpdateSession(7, models.StateAwaitingImage)
+
+	for i := 0; i < models.MaxMealImages; i++ {
+		require.NoError(t, h.HandlePhoto(newPhotoContext(7, "photo")))
+	}
+	require.NoError(t, h.HandlePhoto(newPhotoContext(7, "one-too-many")))
+	assert.Contains(t, sender.last().text, "up to 10 photos")
+	assert.Len(t, sm.GetSession(7).Images, models.MaxMealImages)
+}
+
+func TestMultiPhoto_PhotoWhileProcessing(t *testing.T) {
+	h, sender, estimator, sm, _ := newMultiPhotoHandler(t)
+	sm.UpdateSession(7, models.StateProcessing)
+
+	require.NoError(t, h.HandlePhoto(newPhotoContext(7, "plate")))
+	assert.Contains(t, sender.last().text, "Still working on your previous meal")
+
+	sentBefore := len(sender.sent)
+	for _, fileID := range []string{"side", "drink"} {
+		c := newPhotoContext(7, fileID)
+		c.message.AlbumID = "album-1"
+		require.NoError(t, h.HandlePhoto(c))
+	}
+	assert.Len(t, sender.sent, sentBefore+1, "album answered once")
+
+	assert.Equal(t, models.StateProcessing, sm.GetSession(7).State)
+	assert.Empty(t, sm.GetSession(7).Images, "photos are not queued")
+	assert.Equal(t, 0, estimator.images)
+}
+
+func TestMultiPhoto_CancelDiscardsPhotos(t *testing.T) {
+	h, _, estimator, sm, _ := newMultiPhotoHandler(t)
+	sm.UpdateSession(7, models.StateAwaitingImage)
+
+	require.NoError(t, h.HandlePhoto(newPhotoContext(7, "plate")))
+	require.NoError(t, h.HandleCancel(newCallbackContext(7, "cancel")))
+	require.NoError(t, h.HandleDone(newCallbackContext(7, "done")))
+
+	assert.Equal(t, 0, estimator.images)
+	assert.Empty(t, sm.GetSession(7).Images)
+}
+
+func TestImagePrompt_MultiplePhotos(t *testing.T) {
+	p := services.DefaultPrompts()
+
+	single, _, err := p.ImagePrompt(context.Background(), 1, "")
+	require.NoError(t, err)
+	assert.Contains(t, single, "Analyze this food image")
+
+	multi, _, err := p.ImagePrompt(context.Background(), 3, "")
+	require.NoError(t, err)
+	assert.Contains(t, multi, "These 3 photos show one meal")
+	assert.NotContains(t, multi, "Analyze this food image")
+}
+
+func TestOpenAIEstimator_MultipleImages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		encoded, _ := json.Marshal(body["messages"])
+		assert.Equal(t, 2, strings.Count(string(encoded), `"type":"image_url"`))
+
+		reply, _ := json.Marshal(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"content": registryEstimateJSON}}},
+		})
+		w.Write(reply)
+	}))
+	defer server.Close()
+
+	images := []models.ImageInput{
+		{Data: []byte{1}, MimeType: "image/jpeg"},
+		{Data: []byte{2}, MimeType: "image/jpeg"},
+	}
+	_, err := services.NewOpenAIEstimator(server.URL, "", "m", nil).EstimateFromImages(context.Background(), images, "")
+	require.NoError(t, err)
+
+	_, err = services.NewOpenAIEstimator(server.URL, "", "m", nil).EstimateFromImages(context.Background(), nil, "")
+	assert.Error(t, err)
+}
+
+func TestCachingEstimator_MultiplePhotos(t *testing.T) {
+	inner := newFakeEstimator()
+	cache := services.NewCachingEstimator(inner, nil, services.CacheOptions{})
+	ctx := context.Background()
+
+	plate := models.ImageInput{Data: []byte("plate"), MimeType: "image/jpeg"}
+	drink := models.ImageInput{Data: []byte("drink"), MimeType: "image/jpeg"}
+
+	for _, images := range [][]models.ImageInput{{plate, drink}, {plate, drink}, {plate}, {drink, plate}} {
+		_, err := cache.EstimateFromImages(ctx, images, "")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, inner.images, "only the repeated meal is served from cache")
+}
@@ -0,0 +1,117 @@
+package unit
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	telebot "gopkg.in/telebot.v3"
+)
+
+// Unit tests for photos sent without /estimate
+// Tests: helpful reply instead of silence, /oneshot setting, immediate estimation, albums in one-shot mode
+
+func TestPhotoOutsideFlow_RepliesWithHint(t *testing.T) {
+	h, sender, estimator, _, _ := newMultiPhotoHandler(t)
+
+	require.NoError(t, h.HandlePhoto(newPhotoContext(7, "plate")))
+	require.Len(t, sender.sent, 1)
+	assert.Contains(t, sender.last().text, "send /estimate first")
+	assert.Contains(t, sender.last().text, "/oneshot on")
+	assert.Equal(t, 0, estimator.images)
+
+	// An album gets one reply, not one per photo
+	for _, fileID := range []string{"side", "drink"} {
+		c := newPhotoContext(7, fileID)
+		c.message.AlbumID = "album-1"
+		require.NoError(t, h.HandlePhoto(c))
+	}
+	assert.Len(t, sender.sent, 2)
+
+	// Unrelated files are still ignored
+	c := newTextContext(7, "")
+	c.message.Document = &telebot.Document{File: telebot.File{FileID: "report"}, MIME: "application/pdf"}
+	require.NoError(t, h.HandleDocument(c))
+	assert.Len(t, sender.sent, 2)
+}
+
+func TestOneShot_Command(t *testing.T) {
+	h, sender, _, _, store := newMultiPhotoHandler(t)
+
+	require.NoError(t, h.HandleOneShot(newTextContext(7, "/oneshot")))
+	assert.Contains(t, sender.last().text, "One-shot mode is off")
+
+	require.NoError(t, h.HandleOneShot(newTextContext(7, "/oneshot on", "on")))
+	assert.Contains(t, sender.last().text, "One-shot mode is on")
+	profile, err := store.GetProfile(7)
+	require.NoError(t, err)
+	assert.True(t, profile.OneShot)
+
+	require.NoError(t, h.HandleOneShot(newTextContext(7, "/oneshot maybe", "maybe")))
+	assert.Contains(t, sender.last().text, "Usage")
+
+	require.NoError(t, h.HandleOneShot(newTextContext(7, "/oneshot off", "off")))
+	profile, err = store.GetProfile(7)
+	require.NoError(t, err)
+	assert.False(t, profile.OneShot)
+}
+
+func TestOneShot_EstimatesPhotoImmediately(t *testing.T) {
+	h, _, estimator, sm, store := newMultiPhotoHandler(t)
+	require.NoError(t, h.HandleOneShot(newTextContext(7, "/oneshot on", "on")))
+
+	c := newPhotoContext(7, "plate")
+	c.message.Caption = "no sauce"
+	require.NoError(t, h.HandlePhoto(c))
+
+	assert.Equal(t, 1, estimator.images)
+	assert.Equal(t, "no sauce", estimator.lastHints)
+	require.NoError(t, h.HandleSave(newCallbackContext(7, "save")))
+	logs := logsFor(store, 7)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "no sauce", logs[0].Note)
+
+	// The next single photo is its own meal
+	require.NoError(t, h.HandlePhoto(newPhotoContext(7, "dessert")))
+	assert.Equal(t, 2, estimator.images)
+	assert.Len(t, estimator.lastImages, 1)
+	assert.Empty(t, sm.GetSession(7).Images)
+}
+
+func TestOneShot_AlbumCollectedUntilDone(t *testing.T) {
+	h, sender, estimator, sm, _ := newMultiPhotoHandler(t)
+	require.NoError(t, h.HandleOneShot(newTextContext(7, "/oneshot on", "on")))
+
+	for _, fileID := range []string{"plate", "drink"} {
+		c := newPhotoContext(7, fileID)
+		c.message.AlbumID = "album-1"
+		require.NoError(t, h.HandlePhoto(c))
+	}
+	assert.Contains(t, sender.last().text, "Album received")
+	assert.Equal(t, models.StateCollectingImages, sm.GetSession(7).State)
+	assert.Equal(t, 0, estimator.images)
+
+	require.NoError(t, h.HandleDone(newCallbackContext(7, "done")))
+	assert.Equal(t, 1, estimator.images)
+	assert.Len(t, estimator.lastImages, 2)
+}
+
+func TestSQLiteStorage_PersistsOneShot(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "oneshot.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	profile, err := store.GetProfile(1)
+	require.NoError(t, err)
+	assert.False(t, profile.OneShot)
+
+	profile.OneShot = true
+	require.NoError(t, store.SaveProfile(profile))
+
+	profile, err = store.GetProfile(1)
+	require.NoError(t, err)
+	assert.True(t, profile.OneShot)
+}
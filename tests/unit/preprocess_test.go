@@ -0,0 +1,189 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/freezind/telegram-calories-bot/src/handlers"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	"github.com/freezind/telegram-calories-bot/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	telebot "gopkg.in/telebot.v3"
+)
+
+// Unit tests for image preprocessing before estimation
+// Tests: downsizing, EXIF stripping and auto-orientation, WebP decoding, size/format rejection in the handler
+
+// tinyWebP is a 1x1 lossless WebP image
+const tinyWebP = "UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA=="
+
+// halvesJPEG encodes a width x height JPEG that is red on the left half and blue on the right
+func halvesJPEG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.RGBA{255, 0, 0, 255}
+			if x >= width/2 {
+				c = color.RGBA{0, 0, 255, 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}))
+	return buf.Bytes()
+}
+
+// withExif inserts an APP1 Exif segment carrying orientation and a fake GPS payload after the SOI marker
+func withExif(jpegBytes []byte, orientation uint16) []byte {
+	var tiff bytes.Buffer
+	tiff.WriteString("MM")
+	binary.Write(&tiff, binary.BigEndian, uint16(42))
+	binary.Write(&tiff, binary.BigEndian, uint32(8)) // IFD0 offset
+	binary.Write(&tiff, binary.BigEndian, uint16(1)) // One entry
+	binary.Write(&tiff, binary.BigEndian, uint16(0x0112))
+	binary.Write(&tiff, binary.BigEndian, uint16(3)) // SHORT
+	binary.Write(&tiff, binary.BigEndian, uint32(1))
+	binary.Write(&tiff, binary.BigEndian, orientation)
+	binary.Write(&tiff, binary.BigEndian, uint16(0))
+	binary.Write(&tiff, binary.BigEndian, uint32(0)) // No next IFD
+	tiff.WriteString("GPS 52.5200N 13.4050E")
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, jpegBytes[:2]...)
+	out = append(out, segment...)
+	return append(out, jpegBytes[2:]...)
+}
+
+func decodeImage(t *testing.T, data []byte) image.Image {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, "jpeg", format)
+	return img
+}
+
+func TestImagePreprocessor_Downsizes(t *testing.T) {
+	p := services.NewImagePreprocessor(services.ImageOptions{MaxEdge: 100})
+
+	out, mimeType, err := p.Process(encodePNG(t, testPhoto(400, 200, 1)))
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", mimeType)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), decodeImage(t, out).Bounds())
+
+	// Small images keep their size
+	out, _, err = p.Process(encodePNG(t, testPhoto(60, 40, 1)))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 60, 40), decodeImage(t, out).Bounds())
+}
+
+func TestImagePreprocessor_StripsExifAndOrients(t *testing.T) {
+	p := services.NewImagePreprocessor(services.ImageOptions{})
+	input := withExif(halvesJPEG(t, 80, 40), 6) // Camera held sideways: rotate 90° clockwise
+	require.Contains(t, string(input), "GPS 52.5200N")
+
+	out, _, err := p.Process(input)
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "Exif")
+	assert.NotContains(t, string(out), "GPS 52.5200N")
+
+	img := decodeImage(t, out)
+	require.Equal(t, image.Rect(0, 0, 40, 80), img.Bounds(), "width and height swapped")
+
+	// The left (red) half is now on top, the right (blue) half at the bottom
+	r, _, b, _ := img.At(20, 10).RGBA()
+	assert.Greater(t, r, b, "top is red")
+	r, _, b, _ = img.At(20, 70).RGBA()
+	assert.Greater(t, b, r, "bottom is blue")
+}
+
+func TestImagePreprocessor_DecodesWebP(t *testing.T) {
+	webp, err := base64.StdEncoding.DecodeString(tinyWebP)
+	require.NoError(t, err)
+
+	out, mimeType, err := services.NewImagePreprocessor(services.ImageOptions{}).Process(webp)
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", mimeType)
+	assert.Equal(t, image.Rect(0, 0, 1, 1), decodeImage(t, out).Bounds())
+}
+
+func TestImagePreprocessor_Rejects(t *testing.T) {
+	p := services.NewImagePreprocessor(services.ImageOptions{MaxBytes: 1000})
+
+	_, _, err := p.Process(encodePNG(t, testPhoto(400, 400, 1)))
+	assert.ErrorIs(t, err, services.ErrImageTooLarge)
+
+	_, _, err = p.Process([]byte("%PDF-1.4 not an image"))
+	assert.ErrorIs(t, err, services.ErrUnsupportedImage)
+}
+
+// newPhotoContext simulates a photo message in the /estimate flow
+func newPhotoContext(userID int64, fileID string) *fakeContext {
+	c := newTextContext(userID, "")
+	c.message.Photo = &telebot.Photo{File: telebot.File{FileID: fileID}}
+	return c
+}
+
+func TestHandlePhoto_PreprocessesBeforeEstimate(t *testing.T) {
+	original := withExif(halvesJPEG(t, 3000, 1000), 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(original)
+	}))
+	defer server.Close()
+
+	sender := &fakeSender{fileBaseURL: server.URL}
+	estimator := newFakeEstimator()
+	sm := services.NewSessionManager()
+	h := handlers.NewEstimateHandler(sender, sm, estimator, nil, nil, services.NewImagePreprocessor(services.ImageOptions{MaxEdge: 300}))
+
+	sm.UpdateSession(7, models.StateAwaitingImage)
+	require.NoError(t, h.HandlePhoto(newPhotoContext(7, "photo-1")))
+	require.NoError(t, h.HandleDone(newCallbackContext(7, "done")))
+
+	require.Equal(t, 1, estimator.images)
+	require.Len(t, estimator.lastImages, 1)
+	sent := estimator.lastImages[0]
+	assert.Equal(t, "image/jpeg", sent.MimeType)
+	assert.Less(t, len(sent.Data), len(original))
+	assert.NotContains(t, string(sent.Data), "GPS")
+	assert.Equal(t, image.Rect(0, 0, 300, 100), decodeImage(t, sent.Data).Bounds())
+}
+
+func TestHandleDocument_RejectsBeforeEstimate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("this is not really a png"))
+	}))
+	defer server.Close()
+
+	sender := &fakeSender{fileBaseURL: server.URL}
+	estimator := newFakeEstimator()
+	sm := services.NewSessionManager()
+	h := handlers.NewEstimateHandler(sender, sm, estimator, nil, nil, services.NewImagePreprocessor(services.ImageOptions{MaxBytes: 5 << 20}))
+
+	// Oversized document: rejected from its metadata without downloading
+	sm.UpdateSession(7, models.StateAwaitingImage)
+	c := newTextContext(7, "")
+	c.message.Document = &telebot.Document{File: telebot.File{FileID: "big", FileSize: 6 << 20}, MIME: "image/png"}
+	require.NoError(t, h.HandleDocument(c))
+	assert.Contains(t, sender.last().text, "under 5 MB")
+
+	// Corrupt document: downloaded, fails to decode, never reaches the estimator
+	c.message.Document = &telebot.Document{File: telebot.File{FileID: "corrupt", FileSize: 100}, MIME: "image/png"}
+	require.NoError(t, h.HandleDocument(c))
+	assert.True(t, strings.HasPrefix(sender.last().text, "❌ Could not read this image"))
+	assert.Equal(t, models.StateAwaitingImage, sm.GetSession(7).State, "user can try another image")
+
+	assert.Equal(t, 0, estimator.images)
+}
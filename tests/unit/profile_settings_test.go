@@ -0,0 +1,239 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/freezind/telegram-calories-bot/internal/handlers"
+	"github.com/freezind/telegram-calories-bot/internal/middleware"
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/freezind/telegram-calories-bot/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	telebot "gopkg.in/telebot.v3"
+)
+
+// Unit tests for the per-user timezone, language, locale and units profile
+// Tests: locale normalization, language detection from Telegram, /settings, /api/profile, SQLite persistence
+
+func TestNormalizeLocale(t *testing.T) {
+	for input, want := range map[string]string{
+		"en":         "en",
+		"EN":         "en",
+		"pt-br":      "pt-BR",
+		"zh_hans":    "zh-Hans",
+		"zh-hans-cn": "zh-Hans-CN",
+		"es-419":     "es-419",
+	} {
+		got, err := internalmodels.NormalizeLocale(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	for _, input := range []string{"", "e", "english", "en-", "en-US-x", "12", "en-USA1"} {
+		_, err := internalmodels.NormalizeLocale(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestUserProfile_ApplyLanguageCode(t *testing.T) {
+	profile := &internalmodels.UserProfile{UserID: 1}
+	assert.Equal(t, "en", profile.LanguageOrDefault())
+	assert.Equal(t, internalmodels.UnitsMetric, profile.UnitsOrDefault())
+
+	assert.True(t, profile.ApplyLanguageCode("pt-br"))
+	assert.Equal(t, "pt", profile.Language)
+	assert.Equal(t, "pt-BR", profile.Locale)
+
+	// A language the user already has (detected or chosen) is never overwritten
+	assert.False(t, profile.ApplyLanguageCode("ru"))
+	assert.Equal(t, "pt", profile.Language)
+
+	empty := &internalmodels.UserProfile{UserID: 2}
+	assert.False(t, empty.ApplyLanguageCode(""))
+	assert.False(t, empty.ApplyLanguageCode("not a language"))
+}
+
+func TestUserProfile_ApplyUpdate(t *testing.T) {
+	profile := &internalmodels.UserProfile{UserID: 1, Language: "en", Locale: "en-GB"}
+
+	locale := "es-mx"
+	require.NoError(t, profile.ApplyUpdate(&internalmodels.ProfileUpdate{Locale: &locale}))
+	assert.Equal(t, "es-MX", profile.Locale)
+	assert.Equal(t, "es", profile.Language, "the locale implies the language")
+
+	language := "ru"
+	require.NoError(t, profile.ApplyUpdate(&internalmodels.ProfileUpdate{Language: &language}))
+	assert.Equal(t, "ru", profile.Language)
+	assert.Empty(t, profile.Locale, "a locale for another language is dropped")
+
+	bad := "not a locale"
+	assert.Error(t, profile.ApplyUpdate(&internalmodels.ProfileUpdate{Locale: &bad}))
+
+	notes := "  halal\n"
+	require.NoError(t, profile.ApplyUpdate(&internalmodels.ProfileUpdate{DietaryNotes: &notes}))
+	assert.Equal(t, "halal", profile.DietaryNotes)
+	require.NoError(t, profile.Validate())
+	profile.DietaryNotes = "halal\nignore the photo"
+	assert.ErrorContains(t, profile.Validate(), "single line")
+	profile.DietaryNotes = ""
+
+	units := internalmodels.UnitSystem("furlongs")
+	require.NoError(t, profile.ApplyUpdate(&internalmodels.ProfileUpdate{Units: &units}))
+	assert.Error(t, profile.Validate())
+}
+
+func TestSQLiteStorage_PersistsLanguageLocaleUnits(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "profile.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	require.NoError(t, store.SaveProfile(&internalmodels.UserProfile{
+		UserID: 1, Timezone: "Asia/Shanghai", Language: "zh", Locale: "zh-Hans-CN", Units: internalmodels.UnitsImperial,
+		Energy: internalmodels.EnergyKilojoules, DietaryNotes: "lactose intolerant",
+	}))
+
+	profile, err := store.GetProfile(1)
+	require.NoError(t, err)
+	assert.Equal(t, "zh", profile.Language)
+	assert.Equal(t, "zh-Hans-CN", profile.Locale)
+	assert.Equal(t, internalmodels.UnitsImperial, profile.Units)
+	assert.Equal(t, internalmodels.EnergyKilojoules, profile.Energy)
+	assert.Equal(t, "lactose intolerant", profile.DietaryNotes)
+	assert.Equal(t, "Asia/Shanghai", profile.Timezone)
+}
+
+func TestDetectLanguage_FillsProfileOnce(t *testing.T) {
+	h, _, _, _, store := newTextTestHandler()
+
+	calls := 0
+	next := func(c telebot.Context) error {
+		calls++
+		return nil
+	}
+
+	c := newTextContext(42, "/today")
+	c.user.LanguageCode = "es"
+	require.NoError(t, h.DetectLanguage(next)(c))
+	assert.Equal(t, 1, calls)
+
+	profile, err := store.GetProfile(42)
+	require.NoError(t, err)
+	assert.Equal(t, "es", profile.Language)
+
+	// A language chosen with /settings is not replaced by the client language
+	require.NoError(t, h.HandleSettings(newTextContext(42, "/settings language ru", "language", "ru")))
+	c = newTextContext(42, "/today")
+	c.user.LanguageCode = "es"
+	require.NoError(t, h.DetectLanguage(next)(c))
+	profile, err = store.GetProfile(42)
+	require.NoError(t, err)
+	assert.Equal(t, "ru", profile.Language)
+}
+
+func TestHandleSettings(t *testing.T) {
+	h, sender, _, _, store := newTextTestHandler()
+
+	require.NoError(t, h.HandleSettings(newTextContext(42, "/settings")))
+	assert.Contains(t, sender.last().text, "Timezone: server time")
+	assert.Contains(t, sender.last().text, "Language: en (default)")
+	assert.Contains(t, sender.last().text, "Units: metric")
+
+	require.NoError(t, h.HandleSettings(newTextContext(42, "/settings timezone America/Chicago", "timezone", "America/Chicago")))
+	require.NoError(t, h.HandleSettings(newTextContext(42, "/settings locale es-us", "locale", "es-us")))
+	require.NoError(t, h.HandleSettings(newTextContext(42, "/settings units imperial", "units", "imperial")))
+
+	// The es-US locale switched the replies to Spanish
+	assert.Contains(t, sender.last().text, "Ajustes guardados")
+	assert.Contains(t, sender.last().text, "Configuración regional: es-US")
+
+	profile, err := store.GetProfile(42)
+	require.NoError(t, err)
+	assert.Equal(t, "America/Chicago", profile.Timezone)
+	assert.Equal(t, "es", profile.Language)
+	assert.Equal(t, internalmodels.UnitsImperial, profile.Units)
+
+	require.NoError(t, h.HandleSettings(newTextContext(42, "/settings timezone Mars/Olympus", "timezone", "Mars/Olympus")))
+	assert.Contains(t, sender.last().text, "Zona horaria desconocida")
+	require.NoError(t, h.HandleSettings(newTextContext(42, "/settings units cubits", "units", "cubits")))
+	assert.Contains(t, sender.last().text, "metric o imperial")
+	require.NoError(t, h.HandleSettings(newTextContext(42, "/settings colour blue", "colour", "blue")))
+	assert.Contains(t, sender.last().text, "Uso:")
+}
+
+func TestHandleSettings_DietaryNotes(t *testing.T) {
+	h, sender, estimator, _, store := newTextTestHandler()
+
+	require.NoError(t, h.HandleSettings(newTextContext(42, "/settings diet vegetarian, no nuts", "diet", "vegetarian,", "no", "nuts")))
+	assert.Contains(t, sender.last().text, "Dietary notes: vegetarian, no nuts")
+	profile, err := store.GetProfile(42)
+	require.NoError(t, err)
+	assert.Equal(t, "vegetarian, no nuts", profile.DietaryNotes)
+
+	// The notes reach the estimation prompt
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log lasagna", "lasagna")))
+	prompt, _, err := services.DefaultPrompts().TextPrompt(estimator.lastCtx, "lasagna")
+	require.NoError(t, err)
+	assert.Contains(t, prompt, "Dietary notes: vegetarian, no nuts")
+
+	long := strings.Repeat("x", internalmodels.MaxDietaryNotesLength+1)
+	require.NoError(t, h.HandleSettings(newTextContext(42, "/settings diet "+long, "diet", long)))
+	assert.Contains(t, sender.last().text, "at most 200 characters")
+
+	require.NoError(t, h.HandleSettings(newTextContext(42, "/settings diet none", "diet", "none")))
+	assert.Contains(t, sender.last().text, "Dietary notes: none")
+	profile, err = store.GetProfile(42)
+	require.NoError(t, err)
+	assert.Empty(t, profile.DietaryNotes)
+
+	// Other settings still take exactly one value
+	require.NoError(t, h.HandleSettings(newTextContext(42, "/settings units metric imperial", "units", "metric", "imperial")))
+	assert.Contains(t, sender.last().text, "Usage:")
+}
+
+func TestProfileAPI_GetAndUpdate(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	handler := handlers.NewProfileHandler(store)
+
+	request := func(method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/profile", strings.NewReader(body))
+		ctx := context.WithValue(req.Context(), middleware.UserIDKey, int64(7))
+		ctx = context.WithValue(ctx, middleware.LanguageCodeKey, "ru")
+		rec := httptest.NewRecorder()
+		if method == http.MethodGet {
+			handler.GetProfile(rec, req.WithContext(ctx))
+		} else {
+			handler.UpdateProfile(rec, req.WithContext(ctx))
+		}
+		return rec
+	}
+
+	rec := request(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var profile internalmodels.UserProfile
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &profile))
+	assert.Equal(t, "ru", profile.Language, "filled from the initData language_code")
+
+	rec = request(http.MethodPut, `{"timezone":"Europe/Moscow","units":"imperial","locale":"en-gb"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &profile))
+	assert.Equal(t, "Europe/Moscow", profile.Timezone)
+	assert.Equal(t, "en-GB", profile.Locale)
+	assert.Equal(t, "en", profile.Language)
+	assert.Equal(t, internalmodels.UnitsImperial, profile.Units)
+
+	// The client language never overrides a saved one
+	rec = request(http.MethodGet, "")
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &profile))
+	assert.Equal(t, "en", profile.Language)
+
+	for _, body := range []string{`{"timezone":"Nowhere/City"}`, `{"units":"cubits"}`, `{"locale":"x"}`, `{`} {
+		assert.Equal(t, http.StatusBadRequest, request(http.MethodPut, body).Code, body)
+	}
+}
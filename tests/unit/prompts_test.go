@@ -0,0 +1,116 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/freezind/telegram-calories-bot/prompts"
+	"github.com/freezind/telegram-calories-bot/src/handlers"
+	"github.com/freezind/telegram-calories-bot/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for versioned prompt templates
+// Tests: embedded versions, rendering with user vars, stable A/B assignment, version stamped on results and logs
+
+func TestLoadPrompts_EmbeddedVersions(t *testing.T) {
+	p, err := services.LoadPrompts(prompts.FS, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v1", "v2"}, p.Versions())
+
+	_, err = services.LoadPrompts(prompts.FS, "v99")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "v99")
+}
+
+func TestLoadPrompts_RejectsIncompleteTemplate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"estimation/v1.tmpl": {Data: []byte(`{{ define "image" }}photo{{ end }}`)},
+	}
+
+	_, err := services.LoadPrompts(fsys, "v1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"text"`)
+}
+
+func TestPrompts_Render(t *testing.T) {
+	p := services.DefaultPrompts()
+
+	image, version, err := p.ImagePrompt(context.Background(), 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, services.DefaultPromptVersion, version)
+	assert.Contains(t, image, `"confidence": "low|medium|high"`)
+	assert.NotContains(t, image, "User context")
+
+	ctx := services.WithPromptRequest(context.Background(), services.PromptRequest{
+		UserID: 1,
+		Vars:   services.PromptVars{Locale: "es-MX", DietaryNotes: "vegetarian"},
+	})
+	text, _, err := p.TextPrompt(ctx, "two tacos")
+	require.NoError(t, err)
+	assert.Contains(t, text, "two tacos")
+	assert.Contains(t, text, "User context")
+	assert.Contains(t, text, "Locale: es-MX")
+	assert.Contains(t, text, "Dietary notes: vegetarian")
+	assert.NotContains(t, text, "Preferred units")
+}
+
+func TestPrompts_ExperimentAssignment(t *testing.T) {
+	p, err := services.LoadPrompts(prompts.FS, "v1")
+	require.NoError(t, err)
+	require.Error(t, p.SetExperiment("v3", 10))
+	require.Error(t, p.SetExperiment("v2", 101))
+	require.NoError(t, p.SetExperiment("v2", 30))
+
+	onExperiment := 0
+	for userID := int64(1); userID <= 1000; userID++ {
+		version := p.VersionFor(userID)
+		assert.Equal(t, version, p.VersionFor(userID), "assignment is stable")
+		if version == "v2" {
+			onExperiment++
+		}
+	}
+	assert.InDelta(t, 300, onExperiment, 60, "roughly 30% of users on the experiment")
+}
+
+func TestLocalEstimator_StampsPromptVersion(t *testing.T) {
+	p, err := services.LoadPrompts(prompts.FS, "v2")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Contains(t, body["prompt"], "cooking oil")
+		w.Write([]byte(registryEstimateJSON))
+	}))
+	defer server.Close()
+
+	result, err := services.NewLocalEstimator(server.URL, p).EstimateFromText(context.Background(), "fried rice")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", result.PromptVersion)
+}
+
+func TestEstimateHandler_RecordsPromptVersionOnLog(t *testing.T) {
+	estimator := newFakeEstimator()
+	estimator.result.PromptVersion = "v2"
+
+	dbStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "prompts.db"))
+	require.NoError(t, err)
+	defer dbStore.Close()
+
+	h := handlers.NewEstimateHandler(&fakeSender{}, services.NewSessionManager(), estimator, dbStore, dbStore, nil)
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log oatmeal", "oatmeal")))
+	require.NoError(t, h.HandleSave(newCallbackContext(42, "save")))
+
+	logs, err := dbStore.ListLogs(42)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "v2", logs[0].PromptVersion, "prompt version persisted")
+}
@@ -94,16 +94,15 @@ func TestSessionManager_CleanupStale(t *testing.T) {
 	user2 := int64(222)
 	user3 := int64(333)
 
-	sm.GetSession(user1)
+	// user2 is inactive since before the cutoff
 	sm.GetSession(user2)
+	time.Sleep(time.Millisecond)
+	cutoff := time.Now()
+	sm.GetSession(user1)
 	sm.GetSession(user3)
 
-	// Manually set one session to be stale (>15 min old)
-	session := sm.GetSession(user2)
-	session.LastActivity = time.Now().Add(-20 * time.Minute)
-
 	// Run cleanup
-	cleaned := sm.CleanupStale()
+	cleaned := sm.CleanupInactiveSince(cutoff)
 
 	// Should have cleaned 1 session
 	assert.Equal(t, 1, cleaned)
@@ -114,6 +113,46 @@ func TestSessionManager_CleanupStale(t *testing.T) {
 	assert.WithinDuration(t, time.Now(), newSession.LastActivity, time.Second)
 }
 
+func TestSessionManager_CleanupStale_KeepsPendingEstimate(t *testing.T) {
+	sm := services.NewSessionManager()
+	userID := int64(12345)
+
+	sm.SetPending(userID, &models.EstimateResult{Calories: 300, Confidence: "high"}, "", models.StateIdle, "")
+
+	// An unconfirmed estimate survives cleanup so Save and auto-save still find it
+	assert.Equal(t, 0, sm.CleanupInactiveSince(time.Now().Add(time.Hour)))
+	assert.NotNil(t, sm.TakePending(userID, 0))
+}
+
+func TestSessionManager_GetSession_ReturnsCopy(t *testing.T) {
+	sm := services.NewSessionManager()
+	userID := int64(12345)
+
+	session := sm.GetSession(userID)
+	session.State = models.StateProcessing
+
+	assert.Equal(t, models.StateIdle, sm.GetSession(userID).State, "changes to the copy are not stored")
+}
+
+func TestSessionManager_AddImage_RequiresCollectingState(t *testing.T) {
+	sm := services.NewSessionManager()
+	userID := int64(12345)
+	image := models.ImageInput{Data: []byte{0xFF}, MimeType: "image/jpeg"}
+
+	// Idle sessions only start a meal when asked to (one-shot albums)
+	_, result, _ := sm.AddImage(userID, image, "", false)
+	assert.Equal(t, services.ImageNotCollecting, result)
+	count, result, _ := sm.AddImage(userID, image, "", true)
+	assert.Equal(t, services.ImageAdded, result)
+	assert.Equal(t, 1, count)
+
+	// Photos arriving after Done never reopen the meal
+	sm.UpdateSession(userID, models.StateProcessing)
+	_, result, _ = sm.AddImage(userID, image, "", true)
+	assert.Equal(t, services.ImageNotCollecting, result)
+	assert.Equal(t, models.StateProcessing, sm.GetSession(userID).State)
+}
+
 func TestSessionManager_ConcurrentAccess(t *testing.T) {
 	sm := services.NewSessionManager()
 	userID := int64(12345)
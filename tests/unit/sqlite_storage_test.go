@@ -0,0 +1,239 @@
+package unit
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Unit tests for SQLiteStorage
+// Tests: CRUD parity with MemoryStorage, persistence across reopen
+
+func newTestLog(calories int, ts time.Time) *models.Log {
+	return &models.Log{
+		FoodItems:  []models.FoodItem{{Name: "Rice"}, {Name: "Chicken"}},
+		Calories:   calories,
+		Confidence: models.ConfidenceHigh,
+		Timestamp:  ts,
+	}
+}
+
+// execSQLite runs a statement directly against a database file, e.g. to store rows older
+// releases wrote; the storage must not hold a write in progress
+func execSQLite(t *testing.T, path, query string, args ...any) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(query, args...)
+	require.NoError(t, err)
+}
+
+func TestSQLiteStorage_CreateAndList(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	userID := int64(12345)
+	now := time.Now()
+
+	require.NoError(t, store.CreateLog(userID, newTestLog(300, now.Add(-time.Hour))))
+	require.NoError(t, store.CreateLog(userID, newTestLog(500, now)))
+	require.NoError(t, store.CreateLog(int64(999), newTestLog(700, now)))
+
+	logs, err := store.ListLogs(userID)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+
+	// Sorted by Timestamp descending
+	assert.Equal(t, 500, logs[0].Calories)
+	assert.Equal(t, 300, logs[1].Calories)
+	assert.Equal(t, userID, logs[0].UserID)
+	assert.Equal(t, []models.FoodItem{{Name: "Rice"}, {Name: "Chicken"}}, logs[0].FoodItems)
+	assert.True(t, logs[0].Timestamp.Equal(now))
+}
+
+func TestSQLiteStorage_Macros(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	userID := int64(12345)
+	entry := newTestLog(500, time.Now())
+	entry.Macros = &models.Macros{Protein: 40, Carbs: 50, Fat: 12}
+	require.NoError(t, store.CreateLog(userID, entry))
+
+	withoutMacros := newTestLog(300, time.Now().Add(-time.Hour))
+	require.NoError(t, store.CreateLog(userID, withoutMacros))
+
+	sugar := 10.0
+	require.NoError(t, store.UpdateLog(userID, entry.ID, &models.LogUpdate{
+		Macros: &models.Macros{Protein: 42, Carbs: 50, Fat: 12, Sugar: &sugar},
+	}))
+
+	logs, err := store.ListLogs(userID)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	require.NotNil(t, logs[0].Macros)
+	assert.Equal(t, 42.0, logs[0].Macros.Protein)
+	assert.Equal(t, 10.0, *logs[0].Macros.Sugar)
+	assert.Nil(t, logs[1].Macros)
+}
+
+func TestSQLiteStorage_ListLogs_EmptyForNewUser(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	logs, err := store.ListLogs(42)
+	require.NoError(t, err)
+	assert.NotNil(t, logs)
+	assert.Empty(t, logs)
+}
+
+func TestSQLiteStorage_UpdateAndDelete(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	userID := int64(12345)
+	entry := newTestLog(500, time.Now())
+	require.NoError(t, store.CreateLog(userID, entry))
+
+	calories := 650
+	require.NoError(t, store.UpdateLog(userID, entry.ID, &models.LogUpdate{Calories: &calories}))
+
+	logs, err := store.ListLogs(userID)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, 650, logs[0].Calories)
+
+	// Other users cannot modify or delete the log
+	err = store.UpdateLog(999, entry.ID, &models.LogUpdate{Calories: &calories})
+	assert.EqualError(t, err, "unauthorized: log does not belong to user")
+	err = store.DeleteLog(999, entry.ID)
+	assert.EqualError(t, err, "unauthorized: log does not belong to user")
+
+	require.NoError(t, store.DeleteLog(userID, entry.ID))
+	assert.EqualError(t, store.DeleteLog(userID, entry.ID), "log not found")
+	assert.EqualError(t, store.UpdateLog(userID, entry.ID, &models.LogUpdate{}), "log not found")
+}
+
+func TestSQLiteStorage_UpdateLog_ValidationFailure(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	userID := int64(12345)
+	entry := newTestLog(500, time.Now())
+	require.NoError(t, store.CreateLog(userID, entry))
+
+	negative := -1
+	assert.Error(t, store.UpdateLog(userID, entry.ID, &models.LogUpdate{Calories: &negative}))
+
+	// Failed update must not be persisted
+	logs, err := store.ListLogs(userID)
+	require.NoError(t, err)
+	assert.Equal(t, 500, logs[0].Calories)
+}
+
+func TestSQLiteStorage_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "test.db")
+	userID := int64(12345)
+
+	store, err := storage.NewSQLiteStorage(path)
+	require.NoError(t, err)
+	require.NoError(t, store.CreateLog(userID, newTestLog(500, time.Now())))
+	require.NoError(t, store.Close())
+
+	// Reopening runs migrations again and must be a no-op
+	reopened, err := storage.NewSQLiteStorage(path)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	logs, err := reopened.ListLogs(userID)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, 500, logs[0].Calories)
+}
+
+func TestSQLiteStorage_Profile(t *testing.T) {
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	profile, err := store.GetProfile(42)
+	require.NoError(t, err)
+	assert.Equal(t, 0, profile.DailyGoal)
+
+	profile.DailyGoal = 2200
+	profile.Timezone = "Europe/Madrid"
+	require.NoError(t, store.SaveProfile(profile))
+
+	// Saving again replaces the existing row
+	profile.DailyGoal = 2100
+	require.NoError(t, store.SaveProfile(profile))
+
+	saved, err := store.GetProfile(42)
+	require.NoError(t, err)
+	assert.Equal(t, 2100, saved.DailyGoal)
+	assert.Equal(t, "Europe/Madrid", saved.Timezone)
+}
+
+func TestSQLiteStorage_MigratesZeroItemCaloriesToUnknown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	store, err := storage.NewSQLiteStorage(path)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	// A row saved before item calories were nullable; rolling the schema back to version 10
+	// (undoing the later migrations) replays migration 11 on reopen
+	execSQLite(t, path, `INSERT INTO logs (id, user_id, food_items, calories, confidence, timestamp, created_at, updated_at)
+		VALUES ('old', 1, '["Tea",{"name":"Rice","portionGrams":150,"calories":200},{"name":"Curry","calories":0}]', 650, 'high', 1, 1, 1)`)
+	execSQLite(t, path, `DELETE FROM schema_migrations WHERE version > 10;
+		ALTER TABLE profiles DROP COLUMN dietary_notes;`)
+
+	store, err = storage.NewSQLiteStorage(path)
+	require.NoError(t, err)
+	defer store.Close()
+
+	logs, err := store.ListLogs(1)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, []models.FoodItem{
+		{Name: "Tea"},
+		{Name: "Rice", PortionGrams: 150, Calories: intPtr(200)},
+		{Name: "Curry"},
+	}, logs[0].FoodItems)
+	assert.Equal(t, 650, logs[0].Calories)
+}
+
+func TestSQLiteStorage_QueryLogs_SearchesLegacyStringItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	store, err := storage.NewSQLiteStorage(path)
+	require.NoError(t, err)
+	defer store.Close()
+
+	// Older releases stored items as plain names
+	execSQLite(t, path, `INSERT INTO logs (id, user_id, food_items, calories, confidence, timestamp, created_at, updated_at)
+		VALUES ('old', 1, '["Fried Rice",{"name":"Egg"}]', 450, 'high', 1, 1, 1)`)
+
+	for _, search := range []string{"rice", "EGG"} {
+		page, err := store.QueryLogs(1, &models.LogQuery{Search: search})
+		require.NoError(t, err, search)
+		require.Len(t, page.Logs, 1, search)
+		assert.Equal(t, "old", page.Logs[0].ID)
+	}
+
+	page, err := store.QueryLogs(1, &models.LogQuery{Search: "name"})
+	require.NoError(t, err)
+	assert.Empty(t, page.Logs, "key names in the stored JSON must not match")
+}
@@ -0,0 +1,114 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/freezind/telegram-calories-bot/internal/handlers"
+	"github.com/freezind/telegram-calories-bot/internal/middleware"
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for bucketed statistics (/api/stats)
+// Tests: day/week/month bucketing in the user's timezone, confidence counts, query validation
+
+func TestBuildStats_DayBucketsInUserTimezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+
+	from := time.Date(2024, 3, 10, 0, 0, 0, 0, tokyo)
+	to := time.Date(2024, 3, 12, 0, 0, 0, 0, tokyo)
+	logs := []internalmodels.Log{
+		{Calories: 400, Confidence: internalmodels.ConfidenceHigh, Timestamp: time.Date(2024, 3, 9, 14, 59, 0, 0, time.UTC)},   // before range
+		{Calories: 500, Confidence: internalmodels.ConfidenceHigh, Timestamp: time.Date(2024, 3, 9, 15, 0, 0, 0, time.UTC)},    // 03-10 00:00 Tokyo
+		{Calories: 300, Confidence: internalmodels.ConfidenceLow, Timestamp: time.Date(2024, 3, 10, 14, 0, 0, 0, time.UTC)},    // 03-10 23:00 Tokyo
+		{Calories: 700, Confidence: internalmodels.ConfidenceMedium, Timestamp: time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)}, // 03-11 00:00 Tokyo
+	}
+
+	stats, err := internalmodels.BuildStats(logs, from, to, internalmodels.GranularityDay, tokyo)
+	require.NoError(t, err)
+
+	require.Len(t, stats.Buckets, 2)
+	assert.Equal(t, 800, stats.Buckets[0].Calories)
+	assert.Equal(t, 2, stats.Buckets[0].Entries)
+	assert.Equal(t, internalmodels.ConfidenceCounts{High: 1, Low: 1}, stats.Buckets[0].Confidence)
+	assert.Equal(t, 700, stats.Buckets[1].Calories)
+	assert.Equal(t, 1500, stats.TotalCalories)
+	assert.Equal(t, 3, stats.Entries)
+	assert.Equal(t, internalmodels.ConfidenceCounts{High: 1, Medium: 1, Low: 1}, stats.Confidence)
+	assert.Equal(t, "Asia/Tokyo", stats.Timezone)
+}
+
+func TestBuildStats_WeekAndMonthBoundaries(t *testing.T) {
+	// 2024-03-13 is a Wednesday; its week starts Monday 2024-03-11
+	wed := time.Date(2024, 3, 13, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), internalmodels.BucketStart(wed, internalmodels.GranularityWeek, time.UTC))
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), internalmodels.BucketStart(wed, internalmodels.GranularityMonth, time.UTC))
+
+	logs := []internalmodels.Log{
+		{Calories: 100, Timestamp: time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)},
+		{Calories: 200, Timestamp: time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)},
+		{Calories: 300, Timestamp: time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)},
+	}
+	stats, err := internalmodels.BuildStats(logs,
+		time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		internalmodels.GranularityMonth, time.UTC)
+	require.NoError(t, err)
+
+	require.Len(t, stats.Buckets, 2)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), stats.Buckets[0].Start)
+	assert.Equal(t, 100, stats.Buckets[0].Calories)
+	assert.Equal(t, 500, stats.Buckets[1].Calories)
+}
+
+func TestBuildStats_RejectsInvalidRange(t *testing.T) {
+	now := time.Now()
+	_, err := internalmodels.BuildStats(nil, now, now, internalmodels.GranularityDay, time.UTC)
+	assert.Error(t, err)
+
+	_, err = internalmodels.BuildStats(nil, now.AddDate(-5, 0, 0), now, internalmodels.GranularityDay, time.UTC)
+	assert.Error(t, err, "too many day buckets")
+
+	_, err = internalmodels.ParseGranularity("year")
+	assert.Error(t, err)
+}
+
+func TestStatsHandler_GetStats(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	require.NoError(t, store.SaveProfile(&internalmodels.UserProfile{UserID: 7, Timezone: "Europe/Madrid"}))
+	err := store.CreateLog(7, &internalmodels.Log{
+		FoodItems:  []internalmodels.FoodItem{{Name: "Paella"}},
+		Calories:   650,
+		Confidence: internalmodels.ConfidenceMedium,
+		Timestamp:  time.Date(2024, 6, 4, 21, 30, 0, 0, time.UTC), // 23:30 in Madrid
+	})
+	require.NoError(t, err)
+
+	handler := handlers.NewStatsHandler(store, store)
+	req := httptest.NewRequest(http.MethodGet, "/api/stats?from=2024-06-03&to=2024-06-09&granularity=week", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(7)))
+	rec := httptest.NewRecorder()
+
+	handler.GetStats(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var stats internalmodels.Stats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	require.Len(t, stats.Buckets, 1)
+	assert.Equal(t, 650, stats.Buckets[0].Calories)
+	assert.Equal(t, 1, stats.Confidence.Medium)
+	assert.Equal(t, "Europe/Madrid", stats.Timezone)
+
+	bad := httptest.NewRequest(http.MethodGet, "/api/stats?granularity=hour", nil)
+	bad = bad.WithContext(context.WithValue(bad.Context(), middleware.UserIDKey, int64(7)))
+	rec = httptest.NewRecorder()
+	handler.GetStats(rec, bad)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
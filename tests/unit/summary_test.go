@@ -0,0 +1,103 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/freezind/telegram-calories-bot/src/handlers"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	"github.com/freezind/telegram-calories-bot/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for /today, /week, /month summaries
+// Tests: day bucketing, averages, goal compliance, message format
+
+func TestSummarizeDays_BucketsAndGoal(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+
+	now := time.Date(2024, 5, 10, 20, 0, 0, 0, loc)
+	logs := []internalmodels.Log{
+		{Calories: 900, Timestamp: time.Date(2024, 5, 10, 8, 0, 0, 0, loc)},
+		{Calories: 1300, Timestamp: time.Date(2024, 5, 10, 13, 0, 0, 0, loc)},
+		{Calories: 1500, Timestamp: time.Date(2024, 5, 8, 12, 0, 0, 0, loc)},
+		{Calories: 700, Timestamp: time.Date(2024, 5, 3, 12, 0, 0, 0, loc)}, // outside 7-day window
+	}
+
+	summary := internalmodels.SummarizeDays(logs, now, 7, loc, internalmodels.DefaultMealWindows, 2000)
+
+	assert.Equal(t, time.Date(2024, 5, 4, 0, 0, 0, 0, loc), summary.Start)
+	assert.Equal(t, time.Date(2024, 5, 11, 0, 0, 0, 0, loc), summary.End)
+	require.Len(t, summary.Days, 7)
+	assert.Equal(t, 2200, summary.Days[6].Calories)
+	assert.Equal(t, 2, summary.Days[6].Entries)
+	assert.Equal(t, 1500, summary.Days[4].Calories)
+
+	assert.Equal(t, 3700, summary.TotalCalories)
+	assert.Equal(t, 3, summary.Entries)
+	assert.Equal(t, 2, summary.DaysLogged)
+	assert.Equal(t, 1850, summary.AveragePerLoggedDay())
+	assert.Equal(t, 1, summary.DaysWithinGoal, "Only the 1500 kcal day is within the 2000 kcal goal")
+}
+
+func TestSummarizeDays_AcrossDSTChange(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+
+	// DST starts on 2024-03-31 in Europe/Madrid
+	now := time.Date(2024, 4, 2, 12, 0, 0, 0, loc)
+	logs := []internalmodels.Log{
+		{Calories: 500, Timestamp: time.Date(2024, 3, 30, 23, 30, 0, 0, loc)},
+		{Calories: 600, Timestamp: time.Date(2024, 4, 1, 0, 30, 0, 0, loc)},
+	}
+
+	summary := internalmodels.SummarizeDays(logs, now, 7, loc, internalmodels.DefaultMealWindows, 0)
+	assert.Equal(t, 500, summary.Days[3].Calories) // Mar 30
+	assert.Equal(t, 600, summary.Days[5].Calories) // Apr 1
+}
+
+func TestFormatSummary(t *testing.T) {
+	loc := time.UTC
+	now := time.Date(2024, 5, 10, 20, 0, 0, 0, loc)
+	logs := []internalmodels.Log{
+		{Calories: 2300, Timestamp: time.Date(2024, 5, 10, 8, 0, 0, 0, loc)},
+		{Calories: 1500, Timestamp: time.Date(2024, 5, 8, 12, 0, 0, 0, loc)},
+	}
+
+	summary := internalmodels.SummarizeDays(logs, now, 7, loc, internalmodels.DefaultMealWindows, 2000)
+	msg := models.FormatSummary(english, "Last 7 days", &summary)
+
+	assert.Contains(t, msg, "📊 Last 7 days")
+	assert.Contains(t, msg, "Total: 3,800 kcal")
+	assert.Contains(t, msg, "Average: 1,900 kcal/day (2 of 7 days logged)")
+	assert.Contains(t, msg, "within goal on 1/2 logged days")
+	assert.Contains(t, msg, "• Fri May 10: 2,300 kcal (1) ⚠️")
+	assert.Contains(t, msg, "• Wed May 8: 1,500 kcal (1)")
+
+	empty := internalmodels.SummarizeDays(nil, now, 1, loc, internalmodels.DefaultMealWindows, 0)
+	assert.Contains(t, models.FormatSummary(english, "Today", &empty), "No meals logged")
+}
+
+func TestHandleToday_UsesStoredLogsAndGoal(t *testing.T) {
+	sender := &fakeSender{}
+	store := storage.NewMemoryStorage()
+	h := handlers.NewEstimateHandler(sender, services.NewSessionManager(), newFakeEstimator(), store, store, nil)
+	userID := int64(42)
+
+	require.NoError(t, store.SaveProfile(&internalmodels.UserProfile{UserID: userID, DailyGoal: 2000}))
+	require.NoError(t, store.CreateLog(userID, &internalmodels.Log{
+		FoodItems:  []internalmodels.FoodItem{{Name: "Pasta", Calories: intPtr(650)}},
+		Confidence: internalmodels.ConfidenceHigh,
+		Timestamp:  time.Now(),
+	}))
+
+	require.NoError(t, h.HandleToday(newTextContext(userID, "/today")))
+	msg := sender.last().text
+	assert.Contains(t, msg, "📊 Today")
+	assert.Contains(t, msg, "Total: 650 kcal")
+	assert.Contains(t, msg, "Remaining: 1,350 kcal")
+}
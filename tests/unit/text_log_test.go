@@ -0,0 +1,109 @@
+package unit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/freezind/telegram-calories-bot/src/handlers"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	"github.com/freezind/telegram-calories-bot/src/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for text-based meal logging (/log)
+// Tests: inline description, awaiting-description flow, re-estimate, errors
+
+func newTextTestHandler() (*handlers.EstimateHandler, *fakeSender, *fakeEstimator, *services.SessionManager, *storage.MemoryStorage) {
+	sender := &fakeSender{}
+	estimator := newFakeEstimator()
+	sm := services.NewSessionManager()
+	store := storage.NewMemoryStorage()
+	return handlers.NewEstimateHandler(sender, sm, estimator, store, store, nil), sender, estimator, sm, store
+}
+
+func TestHandleLog_WithInlineDescription(t *testing.T) {
+	h, sender, estimator, sm, store := newTextTestHandler()
+	userID := int64(42)
+
+	require.NoError(t, h.HandleLog(newTextContext(userID, "/log 2 eggs and toast", "2", "eggs", "and", "toast")))
+
+	assert.Equal(t, []string{"2 eggs and toast"}, estimator.descriptions)
+	assert.Contains(t, sender.last().text, "235 kcal")
+	require.NotNil(t, sender.last().markup, "Result should carry Re-estimate/Cancel keyboard")
+	assert.Equal(t, models.StateAwaitingDescription, sm.GetSession(userID).State)
+
+	require.NoError(t, h.HandleSave(newCallbackContext(userID, "save")))
+	logs := logsFor(store, userID)
+	require.Len(t, logs, 1)
+	assert.Equal(t, 235, logs[0].Calories)
+}
+
+func TestHandleLog_PromptThenText(t *testing.T) {
+	h, sender, estimator, sm, store := newTextTestHandler()
+	userID := int64(42)
+
+	require.NoError(t, h.HandleLog(newTextContext(userID, "/log")))
+	assert.Equal(t, models.StateAwaitingDescription, sm.GetSession(userID).State)
+	assert.Contains(t, sender.last().text, "Describe what you ate")
+	assert.Empty(t, estimator.descriptions)
+
+	require.NoError(t, h.HandleText(newTextContext(userID, "a bowl of oatmeal")))
+	assert.Equal(t, []string{"a bowl of oatmeal"}, estimator.descriptions)
+	require.NoError(t, h.HandleSave(newCallbackContext(userID, "save")))
+	assert.Len(t, logsFor(store, userID), 1)
+}
+
+func TestHandleText_IgnoredOutsideDescriptionState(t *testing.T) {
+	h, sender, estimator, _, _ := newTextTestHandler()
+
+	require.NoError(t, h.HandleText(newTextContext(42, "hello there")))
+	assert.Empty(t, estimator.descriptions)
+	assert.Empty(t, sender.sent)
+}
+
+func TestHandleReEstimate_AfterTextAsksForDescription(t *testing.T) {
+	h, sender, _, sm, _ := newTextTestHandler()
+	userID := int64(42)
+
+	require.NoError(t, h.HandleLog(newTextContext(userID, "/log pasta", "pasta")))
+	require.NoError(t, h.HandleReEstimate(newCallbackContext(userID, "re_estimate")))
+
+	assert.Contains(t, sender.last().text, "another meal description")
+	assert.Equal(t, models.StateAwaitingDescription, sm.GetSession(userID).State)
+}
+
+func TestHandleLog_EstimatorError(t *testing.T) {
+	h, sender, estimator, sm, store := newTextTestHandler()
+	userID := int64(42)
+	estimator.err = errors.New("boom")
+
+	require.NoError(t, h.HandleLog(newTextContext(userID, "/log soup", "soup")))
+	assert.Contains(t, sender.last().text, "API error")
+	assert.Equal(t, models.StateIdle, sm.GetSession(userID).State)
+	assert.Empty(t, logsFor(store, userID))
+}
+
+func TestHandleLog_EstimateTimeout(t *testing.T) {
+	sender := &fakeSender{}
+	sm := services.NewSessionManager()
+	store := storage.NewMemoryStorage()
+	h := handlers.NewEstimateHandler(sender, sm, blockingEstimator{}, store, store, nil)
+	h.SetEstimateTimeout(20 * time.Millisecond)
+
+	// An estimator that never answers is cut off by the total deadline
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log soup", "soup")))
+	assert.Contains(t, sender.last().text, "API error")
+	assert.Equal(t, models.StateIdle, sm.GetSession(42).State)
+}
+
+func TestHandleLog_DescriptionTooLong(t *testing.T) {
+	h, sender, estimator, _, _ := newTextTestHandler()
+
+	require.NoError(t, h.HandleLog(newTextContext(42, "/log", strings.Repeat("x", 501))))
+	assert.Contains(t, sender.last().text, "too long")
+	assert.Empty(t, estimator.descriptions)
+}
@@ -0,0 +1,127 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/freezind/telegram-calories-bot/internal/handlers"
+	"github.com/freezind/telegram-calories-bot/internal/middleware"
+	internalmodels "github.com/freezind/telegram-calories-bot/internal/models"
+	"github.com/freezind/telegram-calories-bot/internal/storage"
+	"github.com/freezind/telegram-calories-bot/src/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Unit tests for metric/imperial portions and kcal/kJ energy display
+// Tests: conversions, bot formatting, /settings energy, /api/logs display block and overrides
+
+func TestDisplayUnits_Convert(t *testing.T) {
+	var units internalmodels.DisplayUnits
+	assert.Equal(t, 250, units.ConvertEnergy(250))
+	amount, unit := units.ConvertPortion(200)
+	assert.Equal(t, 200.0, amount)
+	assert.Equal(t, "g", unit)
+
+	units = internalmodels.DisplayUnits{System: internalmodels.UnitsImperial, Energy: internalmodels.EnergyKilojoules}
+	assert.Equal(t, 1046, units.ConvertEnergy(250))
+	amount, unit = units.ConvertPortion(100)
+	assert.Equal(t, 3.5, amount)
+	assert.Equal(t, "oz", unit)
+
+	_, err := internalmodels.ParseEnergyUnit("joules")
+	assert.Error(t, err)
+	energy, err := internalmodels.ParseEnergyUnit("kJ")
+	require.NoError(t, err)
+	assert.Equal(t, internalmodels.EnergyKilojoules, energy)
+}
+
+func TestFormatResult_DisplayUnits(t *testing.T) {
+	result := &models.EstimateResult{
+		Confidence: "high",
+		FoodItems:  []models.FoodItem{{Name: "Steak", PortionGrams: 227, Calories: intPtr(550)}},
+		Calories:   550,
+	}
+
+	metric := models.FormatResult(english, result)
+	assert.Contains(t, metric, "Estimated Calories: 550 kcal")
+	assert.Contains(t, metric, "Steak (227g) — 550 kcal")
+
+	p := english.WithUnits(internalmodels.DisplayUnits{System: internalmodels.UnitsImperial, Energy: internalmodels.EnergyKilojoules})
+	converted := models.FormatResult(p, result)
+	assert.Contains(t, converted, "Estimated Calories: 2,301 kJ")
+	assert.Contains(t, converted, "Steak (8 oz) — 2,301 kJ")
+
+	assert.Equal(t, "🎯 Today: 4,184 / 8,368 kJ\nRemaining: 4,184 kJ", models.FormatGoalProgress(p, 1000, 2000))
+}
+
+func TestHandleSettings_Energy(t *testing.T) {
+	h, sender, _, _, store := newTextTestHandler()
+
+	require.NoError(t, h.HandleGoal(newTextContext(42, "/goal 2000", "2000")))
+	require.NoError(t, h.HandleSettings(newTextContext(42, "/settings energy kj", "energy", "kj")))
+	assert.Contains(t, sender.last().text, "Energy: kJ")
+	assert.Contains(t, sender.last().text, "Daily goal: 8,368 kJ")
+
+	// The goal is still stored in kcal
+	profile, err := store.GetProfile(42)
+	require.NoError(t, err)
+	assert.Equal(t, internalmodels.EnergyKilojoules, profile.Energy)
+	assert.Equal(t, 2000, profile.DailyGoal)
+
+	require.NoError(t, h.HandleSettings(newTextContext(42, "/settings energy calories", "energy", "calories")))
+	assert.Contains(t, sender.last().text, "kcal or kj")
+}
+
+func TestLogsHandler_DisplayUnits(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	require.NoError(t, store.CreateLog(7, &internalmodels.Log{
+		FoodItems:  []internalmodels.FoodItem{{Name: "Rice", PortionGrams: 150, Calories: intPtr(200)}, {Name: "Salt", Calories: intPtr(0)}},
+		Calories:   200,
+		Confidence: internalmodels.ConfidenceHigh,
+		Timestamp:  time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}))
+	require.NoError(t, store.SaveProfile(&internalmodels.UserProfile{UserID: 7, Energy: internalmodels.EnergyKilojoules}))
+	handler := handlers.NewLogsHandler(store, store)
+
+	get := func(target string) (*httptest.ResponseRecorder, internalmodels.DisplayLogPage) {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(7)))
+		rec := httptest.NewRecorder()
+		handler.ListLogs(rec, req)
+		var page internalmodels.DisplayLogPage
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+		}
+		return rec, page
+	}
+
+	// The profile's kJ preference applies; stored values stay kcal and grams
+	rec, page := get("/api/logs")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Len(t, page.Logs, 1)
+	assert.Equal(t, internalmodels.DisplayUnits{System: internalmodels.UnitsMetric, Energy: internalmodels.EnergyKilojoules}, page.Units)
+	assert.Equal(t, 200, page.Logs[0].Calories)
+	assert.Equal(t, 150.0, page.Logs[0].FoodItems[0].PortionGrams)
+	require.NotNil(t, page.Logs[0].Display)
+	assert.Equal(t, 837, page.Logs[0].Display.Energy)
+	assert.Equal(t, internalmodels.EnergyKilojoules, page.Logs[0].Display.EnergyUnit)
+
+	// Query params override the profile
+	rec, page = get("/api/logs?units=imperial&energy=kcal")
+	require.Equal(t, http.StatusOK, rec.Code)
+	display := page.Logs[0].Display
+	assert.Equal(t, 200, display.Energy)
+	require.Len(t, display.Items, 2)
+	assert.Equal(t, internalmodels.ItemDisplay{Portion: 5.3, PortionUnit: "oz", Energy: intPtr(200)}, display.Items[0])
+	assert.Equal(t, internalmodels.ItemDisplay{Energy: intPtr(0)}, display.Items[1], "unknown portions stay empty, 0 kcal is kept")
+
+	rec, _ = get("/api/logs?units=stones")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec, _ = get("/api/logs?energy=joules")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -2,11 +2,15 @@ import { useState, useEffect } from 'react';
 import { LogTable } from './components/LogTable';
 import { LogForm } from './components/LogForm';
 import { DeleteConfirm } from './components/DeleteConfirm';
-import { fetchLogs, deleteLog, Log } from './api/logs';
+import { fetchLogs, deleteLog, formatFoodItems, Log } from './api/logs';
+import { fetchProfile, Profile } from './api/profile';
 import './App.css';
 
 function App() {
   const [logs, setLogs] = useState<Log[]>([]);
+  const [profile, setProfile] = useState<Profile | null>(null);
+  const [nextCursor, setNextCursor] = useState<string | undefined>(undefined);
+  const [loadingMore, setLoadingMore] = useState(false);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
   const [isFormOpen, setIsFormOpen] = useState(false);
@@ -26,14 +30,19 @@ function App() {
     }
 
     loadLogs();
+    // Times are shown in the user's timezone and locale; fall back to the browser's if this fails
+    fetchProfile()
+      .then(setProfile)
+      .catch((err) => console.error('Failed to load profile:', err));
   }, []);
 
   const loadLogs = async () => {
     try {
       setLoading(true);
       setError(null);
-      const data = await fetchLogs();
-      setLogs(data);
+      const page = await fetchLogs();
+      setLogs(page.logs);
+      setNextCursor(page.nextCursor);
     } catch (err) {
       const errorMessage = err instanceof Error ? err.message : 'Failed to load logs';
       if (errorMessage.includes('401') || errorMessage.toLowerCase().includes('unauthorized')) {
@@ -46,6 +55,21 @@ function App() {
     }
   };
 
+  const loadMoreLogs = async () => {
+    if (!nextCursor) return;
+
+    try {
+      setLoadingMore(true);
+      const page = await fetchLogs({ cursor: nextCursor });
+      setLogs((current) => [...current, ...page.logs]);
+      setNextCursor(page.nextCursor);
+    } catch (err) {
+      console.error('Failed to load more logs:', err);
+    } finally {
+      setLoadingMore(false);
+    }
+  };
+
   if (loading) {
     return <div className="loading">Loading logs...</div>;
   }
@@ -108,9 +132,19 @@ function App() {
       <main className="app-main">
         <LogTable
           logs={logs}
+          profile={profile}
           onEdit={handleEditLog}
           onDelete={handleDeleteLog}
         />
+        {nextCursor && (
+          <button
+            className="btn-load-more"
+            onClick={loadMoreLogs}
+            disabled={loadingMore}
+          >
+            {loadingMore ? 'Loading...' : 'Load more'}
+          </button>
+        )}
       </main>
 
       <LogForm
@@ -131,7 +165,7 @@ function App() {
           setDeletingLog(null);
         }}
         onConfirm={confirmDelete}
-        logInfo={deletingLog ? `${deletingLog.foodItems.join(', ')} - ${deletingLog.calories} cal` : undefined}
+        logInfo={deletingLog ? `${formatFoodItems(deletingLog.foodItems)} - ${deletingLog.calories} cal` : undefined}
       />
     </div>
   );
@@ -11,43 +11,148 @@ declare global {
   }
 }
 
+// Macronutrient breakdown in grams (fiber/sugar optional)
+export interface Macros {
+  protein: number;
+  carbs: number;
+  fat: number;
+  fiber?: number;
+  sugar?: number;
+}
+
+// A single food within a log; calories are per item and sum to the log total
+// calories is absent when the item has no estimate (0 is a real value, e.g. water)
+export interface FoodItem {
+  name: string;
+  portionGrams?: number;
+  calories?: number;
+}
+
+// Meal a log belongs to; inferred from the time of day when not given
+export type MealType = 'breakfast' | 'lunch' | 'dinner' | 'snack';
+
+export const MEAL_TYPES: MealType[] = ['breakfast', 'lunch', 'dinner', 'snack'];
+
+// Render food item names as a comma-separated list
+export function formatFoodItems(items: FoodItem[]): string {
+  return items.map((item) => item.name).join(', ');
+}
+
+export type EnergyUnit = 'kcal' | 'kj';
+
+// Units the API converted a log's display block to
+export interface DisplayUnits {
+  system: 'metric' | 'imperial';
+  energy: EnergyUnit;
+}
+
+// A food item in display units, in the same order as foodItems
+export interface ItemDisplay {
+  portion?: number;
+  portionUnit?: 'g' | 'oz';
+  energy?: number; // Absent when the item has no calorie estimate
+}
+
+// A log's energy and portions in display units; calories and portionGrams stay kcal and grams
+export interface LogDisplay {
+  energy: number;
+  energyUnit: EnergyUnit;
+  items?: ItemDisplay[];
+}
+
+// Render a log's energy in display units, e.g. "1046 kJ"
+export function formatEnergy(log: Log): string {
+  if (!log.display) {
+    return `${log.calories} kcal`;
+  }
+  return `${log.display.energy} ${log.display.energyUnit === 'kj' ? 'kJ' : 'kcal'}`;
+}
+
 export interface Log {
   id: string;
   userId: number;
-  foodItems: string[];
+  foodItems: FoodItem[];
   calories: number;
+  macros?: Macros;
   confidence: 'high' | 'medium' | 'low';
+  provider?: string;
+  promptVersion?: string;
+  note?: string;
+  mealType?: MealType;
   timestamp: string;
   createdAt: string;
   updatedAt: string;
+  display?: LogDisplay; // Added by the API in the user's (or requested) units
+}
+
+// One page of logs; pass nextCursor back as `cursor` to fetch the next page
+export interface LogPage {
+  logs: Log[];
+  nextCursor?: string;
+  units?: DisplayUnits;
+}
+
+// Optional filters for fetchLogs
+// from/to are dates (YYYY-MM-DD, in the user's timezone, to inclusive) or RFC3339 timestamps
+export interface LogQuery {
+  limit?: number;
+  cursor?: string; // Only valid with the order it was issued for
+  order?: 'desc' | 'asc'; // Newest first by default
+  from?: string;
+  to?: string;
+  confidence?: Array<'high' | 'medium' | 'low'>;
+  q?: string;
+  units?: 'metric' | 'imperial'; // Overrides the profile's display units
+  energy?: EnergyUnit;
 }
 
 export interface LogCreate {
-  foodItems: string[];
+  foodItems: FoodItem[];
   calories: number;
+  macros?: Macros;
   confidence: 'high' | 'medium' | 'low';
+  mealType?: MealType;
   timestamp?: string;
 }
 
 export interface LogUpdate {
-  foodItems?: string[];
+  foodItems?: FoodItem[];
   calories?: number;
+  macros?: Macros;
   confidence?: 'high' | 'medium' | 'low';
+  note?: string;
+  mealType?: MealType;
   timestamp?: string;
 }
 
 // Get API base URL from environment variable
-const API_BASE_URL = import.meta.env.VITE_API_BASE_URL || '';
+export const API_BASE_URL = import.meta.env.VITE_API_BASE_URL || '';
 
 // Get initData from Telegram WebApp
-function getInitData(): string {
+export function getInitData(): string {
   return window.Telegram?.WebApp?.initData || '';
 }
 
-// Fetch all logs for the current user
-export async function fetchLogs(): Promise<Log[]> {
+// Build the /api/logs query string from optional filters
+function logQueryString(query: LogQuery): string {
+  const params = new URLSearchParams();
+  if (query.limit) params.set('limit', String(query.limit));
+  if (query.cursor) params.set('cursor', query.cursor);
+  if (query.order) params.set('order', query.order);
+  if (query.from) params.set('from', query.from);
+  if (query.to) params.set('to', query.to);
+  if (query.confidence?.length) params.set('confidence', query.confidence.join(','));
+  if (query.q) params.set('q', query.q);
+  if (query.units) params.set('units', query.units);
+  if (query.energy) params.set('energy', query.energy);
+  const encoded = params.toString();
+  return encoded ? `?${encoded}` : '';
+}
+
+// Fetch one page of logs for the current user (newest first)
+export async function fetchLogs(query: LogQuery = {}): Promise<LogPage> {
   try {
-    const response = await fetch(`${API_BASE_URL}/api/logs`, {
+    const response = await fetch(`${API_BASE_URL}/api/logs${logQueryString(query)}`, {
       method: 'GET',
       headers: {
         'Content-Type': 'application/json',
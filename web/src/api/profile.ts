@@ -0,0 +1,76 @@
+import { API_BASE_URL, EnergyUnit, getInitData } from './logs';
+
+export type UnitSystem = 'metric' | 'imperial';
+
+// Per-user preferences shared by the bot and the Mini App
+export interface Profile {
+  userId: number;
+  dailyGoal: number;
+  timezone: string; // IANA zone name; empty means server time
+  oneShot: boolean;
+  mealWindows?: string;
+  language: string; // e.g. "es"; filled from Telegram's language_code
+  locale: string; // BCP 47 tag for number/date formatting, e.g. "es-MX"
+  units: UnitSystem | '';
+  energy: EnergyUnit | ''; // Display unit for calories; empty means kcal
+  dietaryNotes: string; // Passed to the estimator, e.g. "vegetarian"
+  updatedAt: string;
+}
+
+// Fields accepted by PUT /api/profile (only the fields present are changed)
+export interface ProfileUpdate {
+  dailyGoal?: number;
+  timezone?: string;
+  oneShot?: boolean;
+  mealWindows?: string;
+  language?: string;
+  locale?: string;
+  units?: UnitSystem;
+  energy?: EnergyUnit;
+  dietaryNotes?: string;
+}
+
+// Fetch the current user's profile
+export async function fetchProfile(): Promise<Profile> {
+  const response = await fetch(`${API_BASE_URL}/api/profile`, {
+    method: 'GET',
+    headers: {
+      'Content-Type': 'application/json',
+      'X-Telegram-Init-Data': getInitData(),
+    },
+  });
+
+  if (!response.ok) {
+    throw new Error(`Failed to fetch profile: ${response.statusText}`);
+  }
+
+  return response.json();
+}
+
+// Update the current user's profile
+export async function updateProfile(update: ProfileUpdate): Promise<Profile> {
+  const response = await fetch(`${API_BASE_URL}/api/profile`, {
+    method: 'PUT',
+    headers: {
+      'Content-Type': 'application/json',
+      'X-Telegram-Init-Data': getInitData(),
+    },
+    body: JSON.stringify(update),
+  });
+
+  if (!response.ok) {
+    throw new Error(`Failed to update profile: ${response.statusText}`);
+  }
+
+  return response.json();
+}
+
+// Render a timestamp in the user's timezone and locale (browser defaults when unset)
+export function formatTimestamp(timestamp: string, profile: Profile | null): string {
+  const options: Intl.DateTimeFormatOptions = { dateStyle: 'short', timeStyle: 'short' };
+  if (profile?.timezone) {
+    options.timeZone = profile.timezone;
+  }
+  const locale = profile?.locale || profile?.language || undefined;
+  return new Date(timestamp).toLocaleString(locale, options);
+}
@@ -1,6 +1,6 @@
 import { useState, FormEvent, useEffect } from 'react';
 import { Dialog } from '@headlessui/react';
-import { Log, LogCreate, createLog, updateLog } from '../api/logs';
+import { Log, LogCreate, LogUpdate, FoodItem, MealType, MEAL_TYPES, createLog, updateLog, formatFoodItems } from '../api/logs';
 
 interface LogFormProps {
   isOpen: boolean;
@@ -14,20 +14,24 @@ export function LogForm({ isOpen, onClose, onSuccess, initialData = null, mode =
   const [foodItemsText, setFoodItemsText] = useState('');
   const [calories, setCalories] = useState('');
   const [confidence, setConfidence] = useState<'high' | 'medium' | 'low'>('medium');
+  // Empty lets the server infer the meal from the time of day
+  const [mealType, setMealType] = useState<MealType | ''>('');
   const [errors, setErrors] = useState<string[]>([]);
   const [submitting, setSubmitting] = useState(false);
 
   // Populate form with initialData when in edit mode
   useEffect(() => {
     if (isOpen && mode === 'edit' && initialData) {
-      setFoodItemsText(initialData.foodItems.join(', '));
+      setFoodItemsText(formatFoodItems(initialData.foodItems));
       setCalories(initialData.calories.toString());
       setConfidence(initialData.confidence);
+      setMealType(initialData.mealType ?? '');
     } else if (isOpen && mode === 'create') {
       // Reset form for create mode
       setFoodItemsText('');
       setCalories('');
       setConfidence('medium');
+      setMealType('');
     }
   }, [isOpen, mode, initialData]);
 
@@ -38,12 +42,17 @@ export function LogForm({ isOpen, onClose, onSuccess, initialData = null, mode =
     // Client-side validation
     const validationErrors: string[] = [];
 
-    // Parse food items (comma-separated)
-    const foodItems = foodItemsText
+    // Parse food item names (comma-separated)
+    const foodItemNames = foodItemsText
       .split(',')
       .map((item) => item.trim())
       .filter((item) => item.length > 0);
 
+    // Keep per-item portion/calories for names that were already on the log
+    const foodItems: FoodItem[] = foodItemNames.map(
+      (name) => initialData?.foodItems.find((item) => item.name === name) ?? { name }
+    );
+
     if (foodItems.length === 0) {
       validationErrors.push('Food items cannot be empty');
     }
@@ -52,7 +61,7 @@ export function LogForm({ isOpen, onClose, onSuccess, initialData = null, mode =
       validationErrors.push('Cannot exceed 10 food items');
     }
 
-    const totalLength = foodItems.join('').length;
+    const totalLength = foodItemNames.join('').length;
     if (totalLength > 1000) {
       validationErrors.push('Total food items text cannot exceed 1000 characters');
     }
@@ -76,21 +85,30 @@ export function LogForm({ isOpen, onClose, onSuccess, initialData = null, mode =
           foodItems,
           calories: caloriesNum,
           confidence,
+          mealType: mealType || undefined,
           timestamp: new Date().toISOString(),
         };
         await createLog(logData);
       } else if (mode === 'edit' && initialData) {
-        await updateLog(initialData.id, {
-          foodItems,
-          calories: caloriesNum,
-          confidence,
-        });
+        // Only send changed fields: the server recomputes the total when items change
+        const update: LogUpdate = { confidence };
+        if (foodItemsText !== formatFoodItems(initialData.foodItems)) {
+          update.foodItems = foodItems;
+        }
+        if (caloriesNum !== initialData.calories) {
+          update.calories = caloriesNum;
+        }
+        if (mealType && mealType !== initialData.mealType) {
+          update.mealType = mealType;
+        }
+        await updateLog(initialData.id, update);
       }
 
       // Reset form and close
       setFoodItemsText('');
       setCalories('');
       setConfidence('medium');
+      setMealType('');
       onSuccess();
       onClose();
     } catch (err) {
@@ -158,6 +176,22 @@ export function LogForm({ isOpen, onClose, onSuccess, initialData = null, mode =
               </select>
             </div>
 
+            <div className="form-group">
+              <label htmlFor="mealType">Meal</label>
+              <select
+                id="mealType"
+                value={mealType}
+                onChange={(e) => setMealType(e.target.value as MealType | '')}
+              >
+                {!initialData?.mealType && <option value="">Auto (by time of day)</option>}
+                {MEAL_TYPES.map((type) => (
+                  <option key={type} value={type}>
+                    {type.charAt(0).toUpperCase() + type.slice(1)}
+                  </option>
+                ))}
+              </select>
+            </div>
+
             <div className="form-actions">
               <button type="button" onClick={onClose} className="btn-cancel" disabled={submitting}>
                 Cancel
@@ -1,12 +1,14 @@
-import { Log } from '../api/logs';
+import { Log, formatEnergy, formatFoodItems } from '../api/logs';
+import { Profile, formatTimestamp } from '../api/profile';
 
 interface LogTableProps {
   logs: Log[];
+  profile: Profile | null;
   onEdit: (log: Log) => void;
   onDelete: (log: Log) => void;
 }
 
-export function LogTable({ logs, onEdit, onDelete }: LogTableProps) {
+export function LogTable({ logs, profile, onEdit, onDelete }: LogTableProps) {
   if (logs.length === 0) {
     return (
       <div className="empty-state">
@@ -22,6 +24,7 @@ export function LogTable({ logs, onEdit, onDelete }: LogTableProps) {
         <thead>
           <tr>
             <th>Date/Time</th>
+            <th>Meal</th>
             <th>Food Items</th>
             <th>Calories</th>
             <th>Confidence</th>
@@ -32,12 +35,18 @@ export function LogTable({ logs, onEdit, onDelete }: LogTableProps) {
           {logs.map((log) => (
             <tr key={log.id}>
               <td className="date-cell">
-                {new Date(log.timestamp).toLocaleString()}
+                {formatTimestamp(log.timestamp, profile)}
+              </td>
+              <td className="meal-cell">
+                {log.mealType && (
+                  <span className={`meal-badge meal-${log.mealType}`}>{log.mealType}</span>
+                )}
               </td>
               <td className="food-items-cell">
-                {log.foodItems.join(', ')}
+                {formatFoodItems(log.foodItems)}
+                {log.note && <div className="log-note">{log.note}</div>}
               </td>
-              <td className="calories-cell">{log.calories}</td>
+              <td className="calories-cell">{formatEnergy(log)}</td>
               <td className="confidence-cell">
                 <span className={`confidence-badge confidence-${log.confidence}`}>
                   {log.confidence}